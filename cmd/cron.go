@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
 	"integration-go/internal/pkg/cron"
+	"os"
 
+	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
 
@@ -16,5 +20,77 @@ func cronCmd() *cobra.Command {
 		},
 	}
 
+	command.AddCommand(cronListCmd())
+	command.AddCommand(cronTriggerCmd())
+	command.AddCommand(cronPauseCmd())
+	command.AddCommand(cronResumeCmd())
+
+	return command
+}
+
+func cronListCmd() *cobra.Command {
+	var command = &cobra.Command{
+		Use:   "list",
+		Short: "List registered cron jobs and their next run time",
+		Run: func(cmd *cobra.Command, args []string) {
+			srv := cron.NewServer()
+			if err := srv.List(os.Stdout); err != nil {
+				log.Fatal().Msgf("failed to list cron jobs: %s", err.Error())
+			}
+		},
+	}
+
+	return command
+}
+
+func cronTriggerCmd() *cobra.Command {
+	var command = &cobra.Command{
+		Use:   "trigger [job]",
+		Short: "Run a registered cron job once, even when it is paused",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			srv := cron.NewServer()
+			if err := srv.Trigger(args[0]); err != nil {
+				log.Fatal().Msgf("failed to trigger cron job: %s", err.Error())
+			}
+		},
+	}
+
+	return command
+}
+
+func cronPauseCmd() *cobra.Command {
+	var command = &cobra.Command{
+		Use:   "pause [job]",
+		Short: "Pause the scheduled runs of a cron job on every replica",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			srv := cron.NewServer()
+			if err := srv.Pause(context.Background(), args[0]); err != nil {
+				log.Fatal().Msgf("failed to pause cron job: %s", err.Error())
+			}
+
+			fmt.Printf("cron job %s is paused\n", args[0])
+		},
+	}
+
+	return command
+}
+
+func cronResumeCmd() *cobra.Command {
+	var command = &cobra.Command{
+		Use:   "resume [job]",
+		Short: "Resume the scheduled runs of a paused cron job",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			srv := cron.NewServer()
+			if err := srv.Resume(context.Background(), args[0]); err != nil {
+				log.Fatal().Msgf("failed to resume cron job: %s", err.Error())
+			}
+
+			fmt.Printf("cron job %s is resumed\n", args[0])
+		},
+	}
+
 	return command
 }
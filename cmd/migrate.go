@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"integration-go/internal/pkg/config"
+	"integration-go/internal/pkg/postgres"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+func migrateCmd() *cobra.Command {
+	var command = &cobra.Command{
+		Use:   "migrate",
+		Short: "Manage database migrations",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.HelpFunc()(cmd, args)
+		},
+	}
+
+	command.AddCommand(migrateUpCmd(), migrateDownCmd(), migrateStatusCmd(), migrateCreateCmd())
+
+	return command
+}
+
+func newMigrator() *postgres.Migrator {
+	cfg := config.Load()
+	db := postgres.NewGORM(cfg.Database)
+
+	migrator, err := postgres.NewMigrator(db)
+	if err != nil {
+		log.Fatal().Msgf("failed to load migrations: %s", err.Error())
+	}
+
+	return migrator
+}
+
+// parseSteps reads the optional number of migrations argument.
+func parseSteps(args []string, defaultSteps int) int {
+	if len(args) == 0 {
+		return defaultSteps
+	}
+
+	steps, err := strconv.Atoi(args[0])
+	if err != nil || steps < 1 {
+		log.Fatal().Msgf("invalid number of migrations %q", args[0])
+	}
+
+	return steps
+}
+
+func migrateUpCmd() *cobra.Command {
+	var command = &cobra.Command{
+		Use:   "up [n]",
+		Short: "Apply the pending migrations, or only the next n",
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			steps := parseSteps(args, 0)
+
+			migrations, err := newMigrator().Up(context.Background(), steps)
+			for _, migration := range migrations {
+				fmt.Printf("applied %06d_%s\n", migration.Version, migration.Name)
+			}
+
+			if err != nil {
+				log.Fatal().Msgf("failed to apply migrations: %s", err.Error())
+			}
+
+			if len(migrations) == 0 {
+				fmt.Println("no pending migrations")
+			}
+		},
+	}
+
+	return command
+}
+
+func migrateDownCmd() *cobra.Command {
+	var command = &cobra.Command{
+		Use:   "down [n]",
+		Short: "Roll back the last applied migration, or the last n",
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			steps := parseSteps(args, 1)
+
+			migrations, err := newMigrator().Down(context.Background(), steps)
+			for _, migration := range migrations {
+				fmt.Printf("rolled back %06d_%s\n", migration.Version, migration.Name)
+			}
+
+			if err != nil {
+				log.Fatal().Msgf("failed to roll back migrations: %s", err.Error())
+			}
+
+			if len(migrations) == 0 {
+				fmt.Println("no applied migrations")
+			}
+		},
+	}
+
+	return command
+}
+
+func migrateStatusCmd() *cobra.Command {
+	var command = &cobra.Command{
+		Use:   "status",
+		Short: "List the migrations and when they were applied",
+		Run: func(cmd *cobra.Command, args []string) {
+			statuses, err := newMigrator().Status(context.Background())
+			if err != nil {
+				log.Fatal().Msgf("failed to get migration status: %s", err.Error())
+			}
+
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "VERSION\tNAME\tAPPLIED AT")
+			for _, status := range statuses {
+				appliedAt := "pending"
+				if status.AppliedAt != nil {
+					appliedAt = status.AppliedAt.UTC().Format(time.RFC3339)
+				}
+
+				fmt.Fprintf(tw, "%06d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+			}
+
+			tw.Flush()
+		},
+	}
+
+	return command
+}
+
+func migrateCreateCmd() *cobra.Command {
+	var dir string
+	var command = &cobra.Command{
+		Use:   "create [name]",
+		Short: "Create empty up and down files for a new migration",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			paths, err := postgres.CreateMigration(dir, args[0])
+			if err != nil {
+				log.Fatal().Msgf("failed to create migration: %s", err.Error())
+			}
+
+			for _, path := range paths {
+				fmt.Printf("created %s\n", path)
+			}
+		},
+	}
+
+	command.Flags().StringVar(&dir, "dir", postgres.MigrationsDir, "Directory of the migrations")
+	return command
+}
@@ -14,7 +14,7 @@ func Execute() {
 		},
 	}
 
-	command.AddCommand(apiCmd(), cronCmd())
+	command.AddCommand(apiCmd(), cronCmd(), workerCmd(), migrateCmd())
 
 	if err := command.Execute(); err != nil {
 		log.Fatal().Msgf("failed run app: %s", err.Error())
@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"integration-go/internal/pkg/worker"
+
+	"github.com/spf13/cobra"
+)
+
+func workerCmd() *cobra.Command {
+	var command = &cobra.Command{
+		Use:   "worker",
+		Short: "Run background job worker",
+		Run: func(cmd *cobra.Command, args []string) {
+			srv := worker.NewServer()
+			srv.Run()
+		},
+	}
+
+	return command
+}
@@ -0,0 +1,34 @@
+package allocation
+
+import "net/http"
+
+type allocationError struct {
+	code int
+}
+
+const (
+	allocationErrorNoAgentAvailable = iota
+)
+
+func (e *allocationError) Error() string {
+	switch e.code {
+	case allocationErrorNoAgentAvailable:
+		return "No agent available"
+	default:
+		return "Unknown error code"
+	}
+}
+
+// HTTPMessage is the message sent to the client, which is the message of the error.
+func (e *allocationError) HTTPMessage() string {
+	return e.Error()
+}
+
+func (e *allocationError) HTTPStatusCode() int {
+	switch e.code {
+	case allocationErrorNoAgentAvailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,38 @@
+package allocation
+
+import (
+	"integration-go/internal/pkg/api/resp"
+	"integration-go/internal/pkg/qismo"
+	"integration-go/internal/pkg/validate"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+)
+
+type httpHandler struct {
+	svc *Service
+}
+
+func NewHttpHandler(svc *Service) *httpHandler {
+	return &httpHandler{
+		svc: svc,
+	}
+}
+
+func (h *httpHandler) WebhookQismoAgentAllocation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req qismo.WebhookAgentAllocationRequest
+	if err := validate.DecodeJSON(r.Body, &req); err != nil {
+		resp.WriteJSONFromError(w, err)
+		return
+	}
+
+	if err := h.svc.EnqueueAllocateAgent(ctx, &req); err != nil {
+		log.Ctx(ctx).Error().Msgf("failed to enqueue allocate agent: %s", err.Error())
+		resp.WriteJSONFromError(w, err)
+		return
+	}
+
+	resp.WriteJSON(w, http.StatusOK, "ok")
+}
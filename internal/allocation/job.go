@@ -0,0 +1,39 @@
+package allocation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"integration-go/internal/pkg/qismo"
+	"integration-go/internal/pkg/queue"
+)
+
+const (
+	JobAllocateAgent = "allocation.assign"
+)
+
+type jobHandler struct {
+	svc *Service
+}
+
+func NewJobHandler(svc *Service) *jobHandler {
+	return &jobHandler{
+		svc: svc,
+	}
+}
+
+func (h *jobHandler) AllocateAgent(ctx context.Context, payload []byte) error {
+	var req qismo.WebhookAgentAllocationRequest
+	if err := json.Unmarshal(payload, &req); err != nil {
+		return queue.Permanent(err)
+	}
+
+	// No agent available is retried with backoff as well, until an agent comes online.
+	// A room missing in Omnichannel will not appear on retry, dead-letter the job right away
+	err := h.svc.AllocateAgent(ctx, &req)
+	if errors.Is(err, qismo.ErrRoomNotFound) {
+		return queue.Permanent(err)
+	}
+
+	return err
+}
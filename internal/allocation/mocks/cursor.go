@@ -0,0 +1,93 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Cursor is an autogenerated mock type for the Cursor type
+type Cursor struct {
+	mock.Mock
+}
+
+type Cursor_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Cursor) EXPECT() *Cursor_Expecter {
+	return &Cursor_Expecter{mock: &_m.Mock}
+}
+
+// NextCursor provides a mock function with given fields: ctx, key
+func (_m *Cursor) NextCursor(ctx context.Context, key string) (int64, error) {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NextCursor")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int64, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Cursor_NextCursor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NextCursor'
+type Cursor_NextCursor_Call struct {
+	*mock.Call
+}
+
+// NextCursor is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+func (_e *Cursor_Expecter) NextCursor(ctx interface{}, key interface{}) *Cursor_NextCursor_Call {
+	return &Cursor_NextCursor_Call{Call: _e.mock.On("NextCursor", ctx, key)}
+}
+
+func (_c *Cursor_NextCursor_Call) Run(run func(ctx context.Context, key string)) *Cursor_NextCursor_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Cursor_NextCursor_Call) Return(_a0 int64, _a1 error) *Cursor_NextCursor_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Cursor_NextCursor_Call) RunAndReturn(run func(context.Context, string) (int64, error)) *Cursor_NextCursor_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewCursor creates a new instance of Cursor. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCursor(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Cursor {
+	mock := &Cursor{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,203 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	qismo "integration-go/internal/pkg/qismo"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Omnichannel is an autogenerated mock type for the Omnichannel type
+type Omnichannel struct {
+	mock.Mock
+}
+
+type Omnichannel_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Omnichannel) EXPECT() *Omnichannel_Expecter {
+	return &Omnichannel_Expecter{mock: &_m.Mock}
+}
+
+// AssignAgent provides a mock function with given fields: ctx, roomID, agentID
+func (_m *Omnichannel) AssignAgent(ctx context.Context, roomID string, agentID int64) error {
+	ret := _m.Called(ctx, roomID, agentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AssignAgent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) error); ok {
+		r0 = rf(ctx, roomID, agentID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Omnichannel_AssignAgent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AssignAgent'
+type Omnichannel_AssignAgent_Call struct {
+	*mock.Call
+}
+
+// AssignAgent is a helper method to define mock.On call
+//   - ctx context.Context
+//   - roomID string
+//   - agentID int64
+func (_e *Omnichannel_Expecter) AssignAgent(ctx interface{}, roomID interface{}, agentID interface{}) *Omnichannel_AssignAgent_Call {
+	return &Omnichannel_AssignAgent_Call{Call: _e.mock.On("AssignAgent", ctx, roomID, agentID)}
+}
+
+func (_c *Omnichannel_AssignAgent_Call) Run(run func(ctx context.Context, roomID string, agentID int64)) *Omnichannel_AssignAgent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *Omnichannel_AssignAgent_Call) Return(_a0 error) *Omnichannel_AssignAgent_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Omnichannel_AssignAgent_Call) RunAndReturn(run func(context.Context, string, int64) error) *Omnichannel_AssignAgent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAgentsByDivision provides a mock function with given fields: ctx, divisionIDs
+func (_m *Omnichannel) GetAgentsByDivision(ctx context.Context, divisionIDs []int64) ([]qismo.Agent, error) {
+	ret := _m.Called(ctx, divisionIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAgentsByDivision")
+	}
+
+	var r0 []qismo.Agent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) ([]qismo.Agent, error)); ok {
+		return rf(ctx, divisionIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) []qismo.Agent); ok {
+		r0 = rf(ctx, divisionIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]qismo.Agent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = rf(ctx, divisionIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Omnichannel_GetAgentsByDivision_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAgentsByDivision'
+type Omnichannel_GetAgentsByDivision_Call struct {
+	*mock.Call
+}
+
+// GetAgentsByDivision is a helper method to define mock.On call
+//   - ctx context.Context
+//   - divisionIDs []int64
+func (_e *Omnichannel_Expecter) GetAgentsByDivision(ctx interface{}, divisionIDs interface{}) *Omnichannel_GetAgentsByDivision_Call {
+	return &Omnichannel_GetAgentsByDivision_Call{Call: _e.mock.On("GetAgentsByDivision", ctx, divisionIDs)}
+}
+
+func (_c *Omnichannel_GetAgentsByDivision_Call) Run(run func(ctx context.Context, divisionIDs []int64)) *Omnichannel_GetAgentsByDivision_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]int64))
+	})
+	return _c
+}
+
+func (_c *Omnichannel_GetAgentsByDivision_Call) Return(_a0 []qismo.Agent, _a1 error) *Omnichannel_GetAgentsByDivision_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Omnichannel_GetAgentsByDivision_Call) RunAndReturn(run func(context.Context, []int64) ([]qismo.Agent, error)) *Omnichannel_GetAgentsByDivision_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAvailableAgents provides a mock function with given fields: ctx, roomID
+func (_m *Omnichannel) GetAvailableAgents(ctx context.Context, roomID string) ([]qismo.Agent, error) {
+	ret := _m.Called(ctx, roomID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAvailableAgents")
+	}
+
+	var r0 []qismo.Agent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]qismo.Agent, error)); ok {
+		return rf(ctx, roomID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []qismo.Agent); ok {
+		r0 = rf(ctx, roomID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]qismo.Agent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, roomID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Omnichannel_GetAvailableAgents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAvailableAgents'
+type Omnichannel_GetAvailableAgents_Call struct {
+	*mock.Call
+}
+
+// GetAvailableAgents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - roomID string
+func (_e *Omnichannel_Expecter) GetAvailableAgents(ctx interface{}, roomID interface{}) *Omnichannel_GetAvailableAgents_Call {
+	return &Omnichannel_GetAvailableAgents_Call{Call: _e.mock.On("GetAvailableAgents", ctx, roomID)}
+}
+
+func (_c *Omnichannel_GetAvailableAgents_Call) Run(run func(ctx context.Context, roomID string)) *Omnichannel_GetAvailableAgents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Omnichannel_GetAvailableAgents_Call) Return(_a0 []qismo.Agent, _a1 error) *Omnichannel_GetAvailableAgents_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Omnichannel_GetAvailableAgents_Call) RunAndReturn(run func(context.Context, string) ([]qismo.Agent, error)) *Omnichannel_GetAvailableAgents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewOmnichannel creates a new instance of Omnichannel. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOmnichannel(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Omnichannel {
+	mock := &Omnichannel{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,84 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "integration-go/internal/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Repository is an autogenerated mock type for the Repository type
+type Repository struct {
+	mock.Mock
+}
+
+type Repository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Repository) EXPECT() *Repository_Expecter {
+	return &Repository_Expecter{mock: &_m.Mock}
+}
+
+// Save provides a mock function with given fields: ctx, assignment
+func (_m *Repository) Save(ctx context.Context, assignment *entity.Assignment) error {
+	ret := _m.Called(ctx, assignment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Assignment) error); ok {
+		r0 = rf(ctx, assignment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Repository_Save_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Save'
+type Repository_Save_Call struct {
+	*mock.Call
+}
+
+// Save is a helper method to define mock.On call
+//   - ctx context.Context
+//   - assignment *entity.Assignment
+func (_e *Repository_Expecter) Save(ctx interface{}, assignment interface{}) *Repository_Save_Call {
+	return &Repository_Save_Call{Call: _e.mock.On("Save", ctx, assignment)}
+}
+
+func (_c *Repository_Save_Call) Run(run func(ctx context.Context, assignment *entity.Assignment)) *Repository_Save_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.Assignment))
+	})
+	return _c
+}
+
+func (_c *Repository_Save_Call) Return(_a0 error) *Repository_Save_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Repository_Save_Call) RunAndReturn(run func(context.Context, *entity.Assignment) error) *Repository_Save_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewRepository creates a new instance of Repository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Repository {
+	mock := &Repository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
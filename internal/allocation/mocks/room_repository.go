@@ -0,0 +1,87 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "integration-go/internal/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RoomRepository is an autogenerated mock type for the RoomRepository type
+type RoomRepository struct {
+	mock.Mock
+}
+
+type RoomRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *RoomRepository) EXPECT() *RoomRepository_Expecter {
+	return &RoomRepository_Expecter{mock: &_m.Mock}
+}
+
+// Transition provides a mock function with given fields: ctx, multichannelRoomID, to, actor, note
+func (_m *RoomRepository) Transition(ctx context.Context, multichannelRoomID string, to entity.RoomStatus, actor string, note string) error {
+	ret := _m.Called(ctx, multichannelRoomID, to, actor, note)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Transition")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, entity.RoomStatus, string, string) error); ok {
+		r0 = rf(ctx, multichannelRoomID, to, actor, note)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RoomRepository_Transition_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Transition'
+type RoomRepository_Transition_Call struct {
+	*mock.Call
+}
+
+// Transition is a helper method to define mock.On call
+//   - ctx context.Context
+//   - multichannelRoomID string
+//   - to entity.RoomStatus
+//   - actor string
+//   - note string
+func (_e *RoomRepository_Expecter) Transition(ctx interface{}, multichannelRoomID interface{}, to interface{}, actor interface{}, note interface{}) *RoomRepository_Transition_Call {
+	return &RoomRepository_Transition_Call{Call: _e.mock.On("Transition", ctx, multichannelRoomID, to, actor, note)}
+}
+
+func (_c *RoomRepository_Transition_Call) Run(run func(ctx context.Context, multichannelRoomID string, to entity.RoomStatus, actor string, note string)) *RoomRepository_Transition_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(entity.RoomStatus), args[3].(string), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *RoomRepository_Transition_Call) Return(_a0 error) *RoomRepository_Transition_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *RoomRepository_Transition_Call) RunAndReturn(run func(context.Context, string, entity.RoomStatus, string, string) error) *RoomRepository_Transition_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewRoomRepository creates a new instance of RoomRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRoomRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RoomRepository {
+	mock := &RoomRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,142 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	qismo "integration-go/internal/pkg/qismo"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Strategy is an autogenerated mock type for the Strategy type
+type Strategy struct {
+	mock.Mock
+}
+
+type Strategy_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Strategy) EXPECT() *Strategy_Expecter {
+	return &Strategy_Expecter{mock: &_m.Mock}
+}
+
+// Choose provides a mock function with given fields: ctx, req, agents
+func (_m *Strategy) Choose(ctx context.Context, req *qismo.WebhookAgentAllocationRequest, agents []qismo.Agent) (*qismo.Agent, error) {
+	ret := _m.Called(ctx, req, agents)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Choose")
+	}
+
+	var r0 *qismo.Agent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *qismo.WebhookAgentAllocationRequest, []qismo.Agent) (*qismo.Agent, error)); ok {
+		return rf(ctx, req, agents)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *qismo.WebhookAgentAllocationRequest, []qismo.Agent) *qismo.Agent); ok {
+		r0 = rf(ctx, req, agents)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*qismo.Agent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *qismo.WebhookAgentAllocationRequest, []qismo.Agent) error); ok {
+		r1 = rf(ctx, req, agents)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Strategy_Choose_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Choose'
+type Strategy_Choose_Call struct {
+	*mock.Call
+}
+
+// Choose is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req *qismo.WebhookAgentAllocationRequest
+//   - agents []qismo.Agent
+func (_e *Strategy_Expecter) Choose(ctx interface{}, req interface{}, agents interface{}) *Strategy_Choose_Call {
+	return &Strategy_Choose_Call{Call: _e.mock.On("Choose", ctx, req, agents)}
+}
+
+func (_c *Strategy_Choose_Call) Run(run func(ctx context.Context, req *qismo.WebhookAgentAllocationRequest, agents []qismo.Agent)) *Strategy_Choose_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*qismo.WebhookAgentAllocationRequest), args[2].([]qismo.Agent))
+	})
+	return _c
+}
+
+func (_c *Strategy_Choose_Call) Return(_a0 *qismo.Agent, _a1 error) *Strategy_Choose_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Strategy_Choose_Call) RunAndReturn(run func(context.Context, *qismo.WebhookAgentAllocationRequest, []qismo.Agent) (*qismo.Agent, error)) *Strategy_Choose_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Name provides a mock function with no fields
+func (_m *Strategy) Name() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Name")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// Strategy_Name_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Name'
+type Strategy_Name_Call struct {
+	*mock.Call
+}
+
+// Name is a helper method to define mock.On call
+func (_e *Strategy_Expecter) Name() *Strategy_Name_Call {
+	return &Strategy_Name_Call{Call: _e.mock.On("Name")}
+}
+
+func (_c *Strategy_Name_Call) Run(run func()) *Strategy_Name_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Strategy_Name_Call) Return(_a0 string) *Strategy_Name_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Strategy_Name_Call) RunAndReturn(run func() string) *Strategy_Name_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewStrategy creates a new instance of Strategy. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStrategy(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Strategy {
+	mock := &Strategy{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,32 @@
+package allocation
+
+import (
+	"context"
+	"integration-go/internal/entity"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+type repo struct {
+	db  *gorm.DB
+	rdb *redis.Client
+}
+
+func NewRepository(db *gorm.DB, rdb *redis.Client) *repo {
+	return &repo{
+		db:  db,
+		rdb: rdb,
+	}
+}
+
+func (r *repo) Save(ctx context.Context, assignment *entity.Assignment) error {
+	err := r.db.WithContext(ctx).Save(assignment).Error
+	return err
+}
+
+// NextCursor increments the cursor stored in redis, so every API and worker replica
+// shares the same round-robin position.
+func (r *repo) NextCursor(ctx context.Context, key string) (int64, error) {
+	return r.rdb.Incr(ctx, key).Result()
+}
@@ -0,0 +1,132 @@
+package allocation
+
+import (
+	"context"
+	"fmt"
+	"integration-go/internal/entity"
+	"integration-go/internal/pkg/qismo"
+
+	"github.com/rs/zerolog/log"
+)
+
+//go:generate mockery --with-expecter --case snake --name Omnichannel
+type Omnichannel interface {
+	GetAvailableAgents(ctx context.Context, roomID string) ([]qismo.Agent, error)
+	GetAgentsByDivision(ctx context.Context, divisionIDs []int64) ([]qismo.Agent, error)
+	AssignAgent(ctx context.Context, roomID string, agentID int64) error
+}
+
+//go:generate mockery --with-expecter --case snake --name Repository
+type Repository interface {
+	Save(ctx context.Context, assignment *entity.Assignment) error
+}
+
+//go:generate mockery --with-expecter --case snake --name RoomRepository
+type RoomRepository interface {
+	Transition(ctx context.Context, multichannelRoomID string, to entity.RoomStatus, actor, note string) error
+}
+
+//go:generate mockery --with-expecter --case snake --name Queue
+type Queue interface {
+	Enqueue(ctx context.Context, jobType string, key string, payload any) error
+}
+
+type Service struct {
+	repo         Repository
+	roomRepo     RoomRepository
+	omni         Omnichannel
+	queue        Queue
+	strategy     Strategy
+	maxCustomers int
+}
+
+func NewService(repo Repository, roomRepo RoomRepository, omni Omnichannel, queue Queue, strategy Strategy, maxCustomers int) *Service {
+	return &Service{
+		repo:         repo,
+		roomRepo:     roomRepo,
+		omni:         omni,
+		queue:        queue,
+		strategy:     strategy,
+		maxCustomers: maxCustomers,
+	}
+}
+
+// EnqueueAllocateAgent persists the allocation request, so the agent is assigned by the
+// worker and the webhook can be acknowledged without waiting for the Omnichannel API.
+func (s *Service) EnqueueAllocateAgent(ctx context.Context, req *qismo.WebhookAgentAllocationRequest) error {
+	if err := s.queue.Enqueue(ctx, JobAllocateAgent, req.RoomID, req); err != nil {
+		return fmt.Errorf("failed to enqueue allocate agent: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) AllocateAgent(ctx context.Context, req *qismo.WebhookAgentAllocationRequest) error {
+	if req.IsResolved {
+		log.Ctx(ctx).Info().Str("room_id", req.RoomID).Msg("skip allocation, room is resolved")
+		return nil
+	}
+
+	agents, err := s.omni.GetAvailableAgents(ctx, req.RoomID)
+	if err != nil {
+		return fmt.Errorf("failed to get available agents: %w", err)
+	}
+
+	agent, err := s.strategy.Choose(ctx, req, s.candidates(agents))
+	if err != nil {
+		return fmt.Errorf("failed to choose agent: %w", err)
+	}
+
+	if agent == nil {
+		s.transition(ctx, req.RoomID, entity.RoomStatusAssignFailed, "", "no agent available")
+		return &allocationError{allocationErrorNoAgentAvailable}
+	}
+
+	if err := s.omni.AssignAgent(ctx, req.RoomID, agent.ID); err != nil {
+		return fmt.Errorf("failed to assign agent: %w", err)
+	}
+
+	err = s.repo.Save(ctx, &entity.Assignment{
+		MultichannelRoomID: req.RoomID,
+		AgentID:            agent.ID,
+		AgentEmail:         agent.Email,
+		Strategy:           s.strategy.Name(),
+	})
+
+	if err != nil {
+		// The agent is already assigned in Omnichannel, failing here would assign another
+		// agent on retry.
+		log.Ctx(ctx).Error().Msgf("failed to save assignment: %s", err.Error())
+	}
+
+	s.transition(ctx, req.RoomID, entity.RoomStatusAssigned, agent.Email, s.strategy.Name())
+
+	return nil
+}
+
+// transition updates the room status, only logging failures since the allocation itself
+// is already decided at this point.
+func (s *Service) transition(ctx context.Context, roomID string, to entity.RoomStatus, actor, note string) {
+	if err := s.roomRepo.Transition(ctx, roomID, to, actor, note); err != nil {
+		log.Ctx(ctx).Error().Msgf("failed to update room status: %s", err.Error())
+	}
+}
+
+// candidates drops agents that are offline or already handle the maximum number of
+// customers. A zero maximum means unlimited.
+func (s *Service) candidates(agents []qismo.Agent) []qismo.Agent {
+	candidates := make([]qismo.Agent, 0, len(agents))
+	for _, agent := range agents {
+		if !agent.IsAvailable || agent.ForceOffline {
+			continue
+		}
+
+		if s.maxCustomers > 0 && agent.CurrentCustomerCount >= s.maxCustomers {
+			continue
+		}
+
+		candidates = append(candidates, agent)
+	}
+
+	return candidates
+}
@@ -0,0 +1,129 @@
+package allocation
+
+import (
+	"context"
+	"fmt"
+	"integration-go/internal/allocation/mocks"
+	"integration-go/internal/entity"
+	"integration-go/internal/pkg/qismo"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var errUnexpected = fmt.Errorf("unexpected")
+
+func TestEnqueueAllocateAgent(t *testing.T) {
+	mockQueue := mocks.NewQueue(t)
+	req := &qismo.WebhookAgentAllocationRequest{RoomID: "room-123"}
+
+	t.Run("error enqueue", func(t *testing.T) {
+		mockQueue.EXPECT().Enqueue(mock.Anything, JobAllocateAgent, req.RoomID, req).Return(errUnexpected).Once()
+
+		svc := Service{queue: mockQueue}
+		err := svc.EnqueueAllocateAgent(context.Background(), req)
+		assert.Equal(t, fmt.Errorf("failed to enqueue allocate agent: %w", errUnexpected), err)
+	})
+
+	t.Run("success enqueue", func(t *testing.T) {
+		mockQueue.EXPECT().Enqueue(mock.Anything, JobAllocateAgent, req.RoomID, req).Return(nil).Once()
+
+		svc := Service{queue: mockQueue}
+		err := svc.EnqueueAllocateAgent(context.Background(), req)
+		assert.Nil(t, err)
+	})
+}
+
+func TestAllocateAgent(t *testing.T) {
+	mockRepo := mocks.NewRepository(t)
+	mockOmni := mocks.NewOmnichannel(t)
+	mockStrategy := mocks.NewStrategy(t)
+	mockRoomRepo := mocks.NewRoomRepository(t)
+
+	req := &qismo.WebhookAgentAllocationRequest{RoomID: "room-123"}
+	agents := []qismo.Agent{
+		{ID: 1, Email: "a@mail.com", IsAvailable: true, CurrentCustomerCount: 1},
+		{ID: 2, Email: "b@mail.com", IsAvailable: false},
+		{ID: 3, Email: "c@mail.com", IsAvailable: true, ForceOffline: true},
+		{ID: 4, Email: "d@mail.com", IsAvailable: true, CurrentCustomerCount: 5},
+	}
+	candidates := []qismo.Agent{agents[0]}
+
+	newService := func() *Service {
+		return &Service{
+			repo:         mockRepo,
+			roomRepo:     mockRoomRepo,
+			omni:         mockOmni,
+			strategy:     mockStrategy,
+			maxCustomers: 5,
+		}
+	}
+
+	t.Run("skip resolved room", func(t *testing.T) {
+		err := newService().AllocateAgent(context.Background(), &qismo.WebhookAgentAllocationRequest{RoomID: "room-123", IsResolved: true})
+		assert.Nil(t, err)
+	})
+
+	t.Run("error get available agents", func(t *testing.T) {
+		mockOmni.EXPECT().GetAvailableAgents(mock.Anything, "room-123").Return(nil, errUnexpected).Once()
+
+		err := newService().AllocateAgent(context.Background(), req)
+		assert.Equal(t, fmt.Errorf("failed to get available agents: %w", errUnexpected), err)
+	})
+
+	t.Run("error choose agent", func(t *testing.T) {
+		mockOmni.EXPECT().GetAvailableAgents(mock.Anything, "room-123").Return(agents, nil).Once()
+		mockStrategy.EXPECT().Choose(mock.Anything, req, candidates).Return(nil, errUnexpected).Once()
+
+		err := newService().AllocateAgent(context.Background(), req)
+		assert.Equal(t, fmt.Errorf("failed to choose agent: %w", errUnexpected), err)
+	})
+
+	t.Run("error no agent available", func(t *testing.T) {
+		mockOmni.EXPECT().GetAvailableAgents(mock.Anything, "room-123").Return(agents, nil).Once()
+		mockStrategy.EXPECT().Choose(mock.Anything, req, candidates).Return(nil, nil).Once()
+		mockRoomRepo.EXPECT().Transition(mock.Anything, "room-123", entity.RoomStatusAssignFailed, "", "no agent available").Return(nil).Once()
+
+		err := newService().AllocateAgent(context.Background(), req)
+		assert.Equal(t, &allocationError{allocationErrorNoAgentAvailable}, err)
+	})
+
+	t.Run("error assign agent", func(t *testing.T) {
+		mockOmni.EXPECT().GetAvailableAgents(mock.Anything, "room-123").Return(agents, nil).Once()
+		mockStrategy.EXPECT().Choose(mock.Anything, req, candidates).Return(&agents[0], nil).Once()
+		mockOmni.EXPECT().AssignAgent(mock.Anything, "room-123", int64(1)).Return(errUnexpected).Once()
+
+		err := newService().AllocateAgent(context.Background(), req)
+		assert.Equal(t, fmt.Errorf("failed to assign agent: %w", errUnexpected), err)
+	})
+
+	t.Run("error save assignment and room status is not returned", func(t *testing.T) {
+		mockOmni.EXPECT().GetAvailableAgents(mock.Anything, "room-123").Return(agents, nil).Once()
+		mockStrategy.EXPECT().Choose(mock.Anything, req, candidates).Return(&agents[0], nil).Once()
+		mockOmni.EXPECT().AssignAgent(mock.Anything, "room-123", int64(1)).Return(nil).Once()
+		mockStrategy.EXPECT().Name().Return(StrategyLeastLoad).Twice()
+		mockRepo.EXPECT().Save(mock.Anything, mock.Anything).Return(errUnexpected).Once()
+		mockRoomRepo.EXPECT().Transition(mock.Anything, "room-123", entity.RoomStatusAssigned, "a@mail.com", StrategyLeastLoad).Return(errUnexpected).Once()
+
+		err := newService().AllocateAgent(context.Background(), req)
+		assert.Nil(t, err)
+	})
+
+	t.Run("success allocate agent", func(t *testing.T) {
+		mockOmni.EXPECT().GetAvailableAgents(mock.Anything, "room-123").Return(agents, nil).Once()
+		mockStrategy.EXPECT().Choose(mock.Anything, req, candidates).Return(&agents[0], nil).Once()
+		mockOmni.EXPECT().AssignAgent(mock.Anything, "room-123", int64(1)).Return(nil).Once()
+		mockStrategy.EXPECT().Name().Return(StrategyLeastLoad).Twice()
+		mockRoomRepo.EXPECT().Transition(mock.Anything, "room-123", entity.RoomStatusAssigned, "a@mail.com", StrategyLeastLoad).Return(nil).Once()
+		mockRepo.EXPECT().Save(mock.Anything, &entity.Assignment{
+			MultichannelRoomID: "room-123",
+			AgentID:            1,
+			AgentEmail:         "a@mail.com",
+			Strategy:           StrategyLeastLoad,
+		}).Return(nil).Once()
+
+		err := newService().AllocateAgent(context.Background(), req)
+		assert.Nil(t, err)
+	})
+}
@@ -0,0 +1,141 @@
+package allocation
+
+import (
+	"context"
+	"fmt"
+	"integration-go/internal/pkg/qismo"
+	"sort"
+)
+
+const (
+	StrategyRoundRobin = "round_robin"
+	StrategyLeastLoad  = "least_load"
+
+	roundRobinCursorKey = "allocation:round_robin:cursor"
+)
+
+// Strategy chooses the agent to assign among the available candidates.
+// It returns nil when none of the candidates is suitable.
+//
+//go:generate mockery --with-expecter --case snake --name Strategy
+type Strategy interface {
+	Name() string
+	Choose(ctx context.Context, req *qismo.WebhookAgentAllocationRequest, agents []qismo.Agent) (*qismo.Agent, error)
+}
+
+//go:generate mockery --with-expecter --case snake --name Cursor
+type Cursor interface {
+	NextCursor(ctx context.Context, key string) (int64, error)
+}
+
+// NewStrategy builds the strategy by name. When divisions are given, candidates are
+// first narrowed down to agents of the division mapped to the room's channel.
+func NewStrategy(name string, divisions map[string]int64, cursor Cursor, omni Omnichannel) (Strategy, error) {
+	var s Strategy
+	switch name {
+	case StrategyRoundRobin:
+		s = &roundRobin{cursor: cursor}
+	case StrategyLeastLoad:
+		s = &leastLoad{}
+	default:
+		return nil, fmt.Errorf("unknown allocation strategy %q", name)
+	}
+
+	if len(divisions) > 0 {
+		s = &divisionMatch{divisions: divisions, omni: omni, next: s}
+	}
+
+	return s, nil
+}
+
+// roundRobin assigns agents in turn, ordered by ID.
+type roundRobin struct {
+	cursor Cursor
+}
+
+func (s *roundRobin) Name() string {
+	return StrategyRoundRobin
+}
+
+func (s *roundRobin) Choose(ctx context.Context, req *qismo.WebhookAgentAllocationRequest, agents []qismo.Agent) (*qismo.Agent, error) {
+	if len(agents) == 0 {
+		return nil, nil
+	}
+
+	sorted := make([]qismo.Agent, len(agents))
+	copy(sorted, agents)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+
+	n, err := s.cursor.NextCursor(ctx, roundRobinCursorKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get round-robin cursor: %w", err)
+	}
+
+	agent := sorted[(n-1)%int64(len(sorted))]
+	return &agent, nil
+}
+
+// leastLoad assigns the agent handling the fewest customers, the lowest ID on ties.
+type leastLoad struct{}
+
+func (s *leastLoad) Name() string {
+	return StrategyLeastLoad
+}
+
+func (s *leastLoad) Choose(ctx context.Context, req *qismo.WebhookAgentAllocationRequest, agents []qismo.Agent) (*qismo.Agent, error) {
+	var chosen *qismo.Agent
+	for i := range agents {
+		agent := &agents[i]
+		if chosen == nil ||
+			agent.CurrentCustomerCount < chosen.CurrentCustomerCount ||
+			(agent.CurrentCustomerCount == chosen.CurrentCustomerCount && agent.ID < chosen.ID) {
+			chosen = agent
+		}
+	}
+
+	if chosen == nil {
+		return nil, nil
+	}
+
+	agent := *chosen
+	return &agent, nil
+}
+
+// divisionMatch keeps the candidates that belong to the division mapped to the room's
+// channel (source) and lets the next strategy choose among them. Rooms from channels
+// without a mapped division are left to the next strategy as is.
+type divisionMatch struct {
+	divisions map[string]int64
+	omni      Omnichannel
+	next      Strategy
+}
+
+func (s *divisionMatch) Name() string {
+	return s.next.Name() + "+division"
+}
+
+func (s *divisionMatch) Choose(ctx context.Context, req *qismo.WebhookAgentAllocationRequest, agents []qismo.Agent) (*qismo.Agent, error) {
+	divisionID, ok := s.divisions[req.Source]
+	if !ok {
+		return s.next.Choose(ctx, req, agents)
+	}
+
+	members, err := s.omni.GetAgentsByDivision(ctx, []int64{divisionID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agents by division: %w", err)
+	}
+
+	inDivision := make(map[int64]struct{}, len(members))
+	for _, member := range members {
+		inDivision[member.ID] = struct{}{}
+	}
+
+	matched := make([]qismo.Agent, 0, len(agents))
+	for _, agent := range agents {
+		if _, ok := inDivision[agent.ID]; ok {
+			matched = append(matched, agent)
+		}
+	}
+
+	return s.next.Choose(ctx, req, matched)
+}
@@ -0,0 +1,101 @@
+package allocation
+
+import (
+	"context"
+	"integration-go/internal/allocation/mocks"
+	"integration-go/internal/pkg/qismo"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewStrategy(t *testing.T) {
+	s, err := NewStrategy(StrategyRoundRobin, nil, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, StrategyRoundRobin, s.Name())
+
+	s, err = NewStrategy(StrategyLeastLoad, map[string]int64{"wa": 1}, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "least_load+division", s.Name())
+
+	s, err = NewStrategy("random", nil, nil, nil)
+	assert.EqualError(t, err, `unknown allocation strategy "random"`)
+	assert.Nil(t, s)
+}
+
+func TestRoundRobin_Choose(t *testing.T) {
+	mockCursor := mocks.NewCursor(t)
+	agents := []qismo.Agent{{ID: 3}, {ID: 1}, {ID: 2}}
+	req := &qismo.WebhookAgentAllocationRequest{RoomID: "room-123"}
+
+	s := &roundRobin{cursor: mockCursor}
+
+	for i, expectedID := range []int64{1, 2, 3, 1} {
+		mockCursor.EXPECT().NextCursor(mock.Anything, roundRobinCursorKey).Return(int64(i+1), nil).Once()
+
+		agent, err := s.Choose(context.Background(), req, agents)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedID, agent.ID)
+	}
+
+	agent, err := s.Choose(context.Background(), req, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, agent)
+}
+
+func TestLeastLoad_Choose(t *testing.T) {
+	s := &leastLoad{}
+	req := &qismo.WebhookAgentAllocationRequest{RoomID: "room-123"}
+
+	agent, err := s.Choose(context.Background(), req, []qismo.Agent{
+		{ID: 1, CurrentCustomerCount: 5},
+		{ID: 3, CurrentCustomerCount: 2},
+		{ID: 2, CurrentCustomerCount: 2},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), agent.ID)
+
+	agent, err = s.Choose(context.Background(), req, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, agent)
+}
+
+func TestDivisionMatch_Choose(t *testing.T) {
+	mockOmni := mocks.NewOmnichannel(t)
+	agents := []qismo.Agent{
+		{ID: 1, CurrentCustomerCount: 0},
+		{ID: 2, CurrentCustomerCount: 3},
+		{ID: 3, CurrentCustomerCount: 1},
+	}
+
+	s := &divisionMatch{
+		divisions: map[string]int64{"wa": 10},
+		omni:      mockOmni,
+		next:      &leastLoad{},
+	}
+
+	t.Run("choose among division members", func(t *testing.T) {
+		mockOmni.EXPECT().GetAgentsByDivision(mock.Anything, []int64{10}).
+			Return([]qismo.Agent{{ID: 2}, {ID: 3}, {ID: 4}}, nil).Once()
+
+		agent, err := s.Choose(context.Background(), &qismo.WebhookAgentAllocationRequest{Source: "wa"}, agents)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), agent.ID)
+	})
+
+	t.Run("no division member available", func(t *testing.T) {
+		mockOmni.EXPECT().GetAgentsByDivision(mock.Anything, []int64{10}).
+			Return([]qismo.Agent{{ID: 4}}, nil).Once()
+
+		agent, err := s.Choose(context.Background(), &qismo.WebhookAgentAllocationRequest{Source: "wa"}, agents)
+		assert.Nil(t, err)
+		assert.Nil(t, agent)
+	})
+
+	t.Run("channel without division", func(t *testing.T) {
+		agent, err := s.Choose(context.Background(), &qismo.WebhookAgentAllocationRequest{Source: "telegram"}, agents)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), agent.ID)
+	})
+}
@@ -0,0 +1,44 @@
+package deadletter
+
+import "net/http"
+
+type deadLetterError struct {
+	code int
+}
+
+const (
+	deadLetterErrorNotFound = iota
+	deadLetterErrorAlreadyRetried
+	deadLetterErrorEmptyFilter
+)
+
+func (e *deadLetterError) Error() string {
+	switch e.code {
+	case deadLetterErrorNotFound:
+		return "Failed event not found"
+	case deadLetterErrorAlreadyRetried:
+		return "Failed event is already retried"
+	case deadLetterErrorEmptyFilter:
+		return "At least one filter is required"
+	default:
+		return "Unknown error code"
+	}
+}
+
+// HTTPMessage is the message sent to the client, which is the message of the error.
+func (e *deadLetterError) HTTPMessage() string {
+	return e.Error()
+}
+
+func (e *deadLetterError) HTTPStatusCode() int {
+	switch e.code {
+	case deadLetterErrorNotFound:
+		return http.StatusNotFound
+	case deadLetterErrorAlreadyRetried:
+		return http.StatusConflict
+	case deadLetterErrorEmptyFilter:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,149 @@
+package deadletter
+
+import (
+	"integration-go/internal/entity"
+	"integration-go/internal/pkg/api/resp"
+	"integration-go/internal/pkg/validate"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+type httpHandler struct {
+	svc *Service
+}
+
+func NewHttpHandler(svc *Service) *httpHandler {
+	return &httpHandler{
+		svc: svc,
+	}
+}
+
+func (h *httpHandler) GetFailedEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, err := parseFilter(r)
+	if err != nil {
+		resp.WriteJSONFromError(w, err)
+		return
+	}
+
+	deadJobs, total, err := h.svc.GetFailedEvents(ctx, filter)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("failed to get failed events: %s", err.Error())
+		resp.WriteJSONFromError(w, err)
+		return
+	}
+
+	resp.WriteJSONWithPaginate(w, http.StatusOK, deadJobs, int(total), filter.Page, filter.Limit)
+}
+
+func (h *httpHandler) GetFailedEventByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		resp.WriteJSONFromError(w, err)
+		return
+	}
+
+	deadJob, err := h.svc.GetFailedEventByID(ctx, int64(id))
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("failed to get failed event: %s", err.Error())
+		resp.WriteJSONFromError(w, err)
+		return
+	}
+
+	resp.WriteJSON(w, http.StatusOK, deadJob)
+}
+
+func (h *httpHandler) Retry(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		resp.WriteJSONFromError(w, err)
+		return
+	}
+
+	res, err := h.svc.Retry(ctx, int64(id))
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("failed to retry failed event: %s", err.Error())
+		resp.WriteJSONFromError(w, err)
+		return
+	}
+
+	resp.WriteJSON(w, http.StatusAccepted, res)
+}
+
+func (h *httpHandler) BulkRetry(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var filter entity.DeadJobFilter
+	if err := validate.DecodeJSON(r.Body, &filter); err != nil {
+		resp.WriteJSONFromError(w, err)
+		return
+	}
+
+	res, err := h.svc.BulkRetry(ctx, &filter)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("failed to bulk retry failed events: %s", err.Error())
+		resp.WriteJSONFromError(w, err)
+		return
+	}
+
+	resp.WriteJSON(w, http.StatusAccepted, res)
+}
+
+func parseFilter(r *http.Request) (*entity.DeadJobFilter, error) {
+	q := r.URL.Query()
+
+	filter := &entity.DeadJobFilter{
+		Type:      q.Get("type"),
+		Status:    entity.DeadJobStatus(q.Get("status")),
+		RequestID: q.Get("request_id"),
+		Page:      1,
+		Limit:     defaultLimit,
+	}
+
+	if v := q.Get("page"); v != "" {
+		page, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, err
+		}
+		filter.Page = max(page, 1)
+	}
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, err
+		}
+		filter.Limit = min(max(limit, 1), maxLimit)
+	}
+
+	if v := q.Get("from"); v != "" {
+		from, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, err
+		}
+		filter.From = &from
+	}
+
+	if v := q.Get("to"); v != "" {
+		to, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, err
+		}
+		filter.To = &to
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,221 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "integration-go/internal/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Repository is an autogenerated mock type for the Repository type
+type Repository struct {
+	mock.Mock
+}
+
+type Repository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Repository) EXPECT() *Repository_Expecter {
+	return &Repository_Expecter{mock: &_m.Mock}
+}
+
+// Fetch provides a mock function with given fields: ctx, filter
+func (_m *Repository) Fetch(ctx context.Context, filter *entity.DeadJobFilter) ([]entity.DeadJob, int64, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Fetch")
+	}
+
+	var r0 []entity.DeadJob
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.DeadJobFilter) ([]entity.DeadJob, int64, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.DeadJobFilter) []entity.DeadJob); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.DeadJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.DeadJobFilter) int64); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *entity.DeadJobFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Repository_Fetch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Fetch'
+type Repository_Fetch_Call struct {
+	*mock.Call
+}
+
+// Fetch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter *entity.DeadJobFilter
+func (_e *Repository_Expecter) Fetch(ctx interface{}, filter interface{}) *Repository_Fetch_Call {
+	return &Repository_Fetch_Call{Call: _e.mock.On("Fetch", ctx, filter)}
+}
+
+func (_c *Repository_Fetch_Call) Run(run func(ctx context.Context, filter *entity.DeadJobFilter)) *Repository_Fetch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.DeadJobFilter))
+	})
+	return _c
+}
+
+func (_c *Repository_Fetch_Call) Return(_a0 []entity.DeadJob, _a1 int64, _a2 error) *Repository_Fetch_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *Repository_Fetch_Call) RunAndReturn(run func(context.Context, *entity.DeadJobFilter) ([]entity.DeadJob, int64, error)) *Repository_Fetch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByID provides a mock function with given fields: ctx, id
+func (_m *Repository) FindByID(ctx context.Context, id int64) (*entity.DeadJob, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByID")
+	}
+
+	var r0 *entity.DeadJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*entity.DeadJob, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *entity.DeadJob); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.DeadJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_FindByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByID'
+type Repository_FindByID_Call struct {
+	*mock.Call
+}
+
+// FindByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *Repository_Expecter) FindByID(ctx interface{}, id interface{}) *Repository_FindByID_Call {
+	return &Repository_FindByID_Call{Call: _e.mock.On("FindByID", ctx, id)}
+}
+
+func (_c *Repository_FindByID_Call) Run(run func(ctx context.Context, id int64)) *Repository_FindByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Repository_FindByID_Call) Return(_a0 *entity.DeadJob, _a1 error) *Repository_FindByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_FindByID_Call) RunAndReturn(run func(context.Context, int64) (*entity.DeadJob, error)) *Repository_FindByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Requeue provides a mock function with given fields: ctx, filter, limit
+func (_m *Repository) Requeue(ctx context.Context, filter *entity.DeadJobFilter, limit int) (int64, error) {
+	ret := _m.Called(ctx, filter, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Requeue")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.DeadJobFilter, int) (int64, error)); ok {
+		return rf(ctx, filter, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.DeadJobFilter, int) int64); ok {
+		r0 = rf(ctx, filter, limit)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.DeadJobFilter, int) error); ok {
+		r1 = rf(ctx, filter, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_Requeue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Requeue'
+type Repository_Requeue_Call struct {
+	*mock.Call
+}
+
+// Requeue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter *entity.DeadJobFilter
+//   - limit int
+func (_e *Repository_Expecter) Requeue(ctx interface{}, filter interface{}, limit interface{}) *Repository_Requeue_Call {
+	return &Repository_Requeue_Call{Call: _e.mock.On("Requeue", ctx, filter, limit)}
+}
+
+func (_c *Repository_Requeue_Call) Run(run func(ctx context.Context, filter *entity.DeadJobFilter, limit int)) *Repository_Requeue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.DeadJobFilter), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *Repository_Requeue_Call) Return(_a0 int64, _a1 error) *Repository_Requeue_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_Requeue_Call) RunAndReturn(run func(context.Context, *entity.DeadJobFilter, int) (int64, error)) *Repository_Requeue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewRepository creates a new instance of Repository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Repository {
+	mock := &Repository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,151 @@
+package deadletter
+
+import (
+	"context"
+	"integration-go/internal/entity"
+	"integration-go/internal/pkg/tracing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repo struct {
+	db          *gorm.DB
+	maxAttempts int
+}
+
+func NewRepository(db *gorm.DB, maxAttempts int) *repo {
+	return &repo{
+		db:          db,
+		maxAttempts: maxAttempts,
+	}
+}
+
+func (r *repo) Fetch(ctx context.Context, filter *entity.DeadJobFilter) ([]entity.DeadJob, int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Model(&entity.DeadJob{}).Scopes(filterScope(filter)).Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var deadJobs []entity.DeadJob
+	err = r.db.WithContext(ctx).
+		Scopes(filterScope(filter)).
+		Order("id DESC").
+		Offset((filter.Page - 1) * filter.Limit).
+		Limit(filter.Limit).
+		Find(&deadJobs).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return deadJobs, total, nil
+}
+
+func (r *repo) FindByID(ctx context.Context, id int64) (*entity.DeadJob, error) {
+	var deadJob entity.DeadJob
+	err := r.db.WithContext(ctx).First(&deadJob, id).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &deadJob, nil
+}
+
+// Record stores a failure that happened outside the worker. A failure with the same type
+// and key as a dead job that is not retried yet increments its attempts instead.
+func (r *repo) Record(ctx context.Context, deadJob *entity.DeadJob) error {
+	deadJob.Status = entity.DeadJobStatusFailed
+	deadJob.Attempts = 1
+
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "type"}, {Name: "key"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "status = 'failed' AND key <> ''"}}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"attempts":   gorm.Expr("dead_jobs.attempts + 1"),
+			"payload":    deadJob.Payload,
+			"error":      deadJob.Error,
+			"request_id": deadJob.RequestID,
+			"updated_at": time.Now(),
+		}),
+	}).Create(deadJob).Error
+	return err
+}
+
+// Requeue enqueues a new job for up to limit failed dead jobs matching the filter, oldest
+// first, and marks them as retried. It returns the number of requeued jobs; callers page
+// through the matching jobs by calling it again until it returns less than limit.
+func (r *repo) Requeue(ctx context.Context, filter *entity.DeadJobFilter, limit int) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var deadJobs []entity.DeadJob
+		err := tx.Scopes(filterScope(filter)).
+			Where("status = ?", entity.DeadJobStatusFailed).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Order("id").
+			Limit(limit).
+			Find(&deadJobs).Error
+		if err != nil || len(deadJobs) == 0 {
+			return err
+		}
+
+		now := time.Now()
+		jobs := make([]entity.Job, 0, len(deadJobs))
+		ids := make([]int64, 0, len(deadJobs))
+		for _, deadJob := range deadJobs {
+			jobs = append(jobs, entity.Job{
+				Type:        deadJob.Type,
+				Key:         deadJob.Key,
+				Payload:     deadJob.Payload,
+				MaxAttempts: r.maxAttempts,
+				RequestID:   deadJob.RequestID,
+				TraceParent: tracing.TraceParent(ctx),
+				RunAt:       now,
+			})
+			ids = append(ids, deadJob.ID)
+		}
+
+		if err := tx.Create(&jobs).Error; err != nil {
+			return err
+		}
+
+		err = tx.Model(&entity.DeadJob{}).Where("id IN ?", ids).Updates(map[string]any{
+			"status":     entity.DeadJobStatusRetried,
+			"retried_at": now,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		count = int64(len(ids))
+		return nil
+	})
+
+	return count, err
+}
+
+func filterScope(f *entity.DeadJobFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(f.IDs) > 0 {
+			db = db.Where("id IN ?", f.IDs)
+		}
+		if f.Type != "" {
+			db = db.Where("type = ?", f.Type)
+		}
+		if f.Status != "" {
+			db = db.Where("status = ?", f.Status)
+		}
+		if f.RequestID != "" {
+			db = db.Where("request_id = ?", f.RequestID)
+		}
+		if f.From != nil {
+			db = db.Where("created_at >= ?", *f.From)
+		}
+		if f.To != nil {
+			db = db.Where("created_at < ?", *f.To)
+		}
+
+		return db
+	}
+}
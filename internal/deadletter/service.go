@@ -0,0 +1,129 @@
+package deadletter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"integration-go/internal/entity"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --with-expecter --case snake --name Repository
+type Repository interface {
+	Fetch(ctx context.Context, filter *entity.DeadJobFilter) ([]entity.DeadJob, int64, error)
+	FindByID(ctx context.Context, id int64) (*entity.DeadJob, error)
+	Requeue(ctx context.Context, filter *entity.DeadJobFilter, limit int) (int64, error)
+}
+
+const (
+	// requeueBatchSize is the number of failed events requeued per transaction.
+	requeueBatchSize = 100
+	// maxBulkRetry caps the failed events requeued by a bulk retry, so a broad filter does
+	// not flood the queue in one call.
+	maxBulkRetry = 1000
+)
+
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{
+		repo: repo,
+	}
+}
+
+func emptyFilter(f *entity.DeadJobFilter) bool {
+	return len(f.IDs) == 0 && f.Type == "" && f.RequestID == "" && f.From == nil && f.To == nil
+}
+
+// RetryResponse counts the requeued failed events. HasMore is set when a bulk retry
+// reached its cap, so more events matching the filter are left to retry.
+type RetryResponse struct {
+	Retried int64 `json:"retried"`
+	HasMore bool  `json:"has_more"`
+}
+
+func (s *Service) GetFailedEvents(ctx context.Context, filter *entity.DeadJobFilter) ([]entity.DeadJob, int64, error) {
+	deadJobs, total, err := s.repo.Fetch(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch failed events: %w", err)
+	}
+
+	return deadJobs, total, nil
+}
+
+func (s *Service) GetFailedEventByID(ctx context.Context, id int64) (*entity.DeadJob, error) {
+	deadJob, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &deadLetterError{deadLetterErrorNotFound}
+		}
+
+		return nil, fmt.Errorf("failed to find failed event: %w", err)
+	}
+
+	return deadJob, nil
+}
+
+// Retry enqueues the failed event again, to be processed by the worker.
+func (s *Service) Retry(ctx context.Context, id int64) (*RetryResponse, error) {
+	deadJob, err := s.GetFailedEventByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if deadJob.Status != entity.DeadJobStatusFailed {
+		return nil, &deadLetterError{deadLetterErrorAlreadyRetried}
+	}
+
+	count, err := s.repo.Requeue(ctx, &entity.DeadJobFilter{IDs: []int64{id}}, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to requeue failed event: %w", err)
+	}
+
+	if count == 0 {
+		// Retried by a concurrent request in the meantime
+		return nil, &deadLetterError{deadLetterErrorAlreadyRetried}
+	}
+
+	log.Ctx(ctx).Info().Int64("failed_event_id", id).Str("type", deadJob.Type).Msg("failed event requeued")
+
+	return &RetryResponse{Retried: count}, nil
+}
+
+// BulkRetry enqueues the failed events matching the filter again, in batches and up to
+// maxBulkRetry per call. At least one filter is required, so a request with an empty body
+// does not replay the whole table.
+func (s *Service) BulkRetry(ctx context.Context, filter *entity.DeadJobFilter) (*RetryResponse, error) {
+	if emptyFilter(filter) {
+		return nil, &deadLetterError{deadLetterErrorEmptyFilter}
+	}
+
+	res := &RetryResponse{}
+	for {
+		if res.Retried >= maxBulkRetry {
+			res.HasMore = true
+			break
+		}
+
+		limit := min(requeueBatchSize, maxBulkRetry-int(res.Retried))
+		count, err := s.repo.Requeue(ctx, filter, limit)
+		if err != nil {
+			// The previous batches are committed, log them so the retry can be resumed
+			log.Ctx(ctx).Error().Int64("count", res.Retried).Msg("failed events partially requeued")
+			return nil, fmt.Errorf("failed to requeue failed events: %w", err)
+		}
+
+		res.Retried += count
+		if count < int64(limit) {
+			break
+		}
+	}
+
+	log.Ctx(ctx).Info().Int64("count", res.Retried).Bool("has_more", res.HasMore).Msg("failed events requeued")
+
+	return res, nil
+}
@@ -0,0 +1,148 @@
+package deadletter
+
+import (
+	"context"
+	"fmt"
+	"integration-go/internal/deadletter/mocks"
+	"integration-go/internal/entity"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+var errUnexpected = fmt.Errorf("unexpected")
+
+func TestGetFailedEvents(t *testing.T) {
+	mockRepo := mocks.NewRepository(t)
+	filter := &entity.DeadJobFilter{Type: "room.create", Page: 1, Limit: 20}
+
+	t.Run("error fetch failed events", func(t *testing.T) {
+		mockRepo.EXPECT().Fetch(mock.Anything, filter).Return(nil, 0, errUnexpected).Once()
+
+		svc := Service{repo: mockRepo}
+		deadJobs, total, err := svc.GetFailedEvents(context.Background(), filter)
+		assert.Equal(t, fmt.Errorf("failed to fetch failed events: %w", errUnexpected), err)
+		assert.Nil(t, deadJobs)
+		assert.Equal(t, int64(0), total)
+	})
+
+	t.Run("success fetch failed events", func(t *testing.T) {
+		mockRepo.EXPECT().Fetch(mock.Anything, filter).Return([]entity.DeadJob{{ID: 1}}, 1, nil).Once()
+
+		svc := Service{repo: mockRepo}
+		deadJobs, total, err := svc.GetFailedEvents(context.Background(), filter)
+		assert.Nil(t, err)
+		assert.Len(t, deadJobs, 1)
+		assert.Equal(t, int64(1), total)
+	})
+}
+
+func TestRetry(t *testing.T) {
+	mockRepo := mocks.NewRepository(t)
+
+	t.Run("error failed event not found", func(t *testing.T) {
+		mockRepo.EXPECT().FindByID(mock.Anything, int64(1)).Return(nil, gorm.ErrRecordNotFound).Once()
+
+		svc := Service{repo: mockRepo}
+		res, err := svc.Retry(context.Background(), 1)
+		assert.Equal(t, &deadLetterError{deadLetterErrorNotFound}, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("error failed event already retried", func(t *testing.T) {
+		mockRepo.EXPECT().FindByID(mock.Anything, int64(1)).
+			Return(&entity.DeadJob{ID: 1, Status: entity.DeadJobStatusRetried}, nil).Once()
+
+		svc := Service{repo: mockRepo}
+		res, err := svc.Retry(context.Background(), 1)
+		assert.Equal(t, &deadLetterError{deadLetterErrorAlreadyRetried}, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("error requeue", func(t *testing.T) {
+		mockRepo.EXPECT().FindByID(mock.Anything, int64(1)).
+			Return(&entity.DeadJob{ID: 1, Status: entity.DeadJobStatusFailed}, nil).Once()
+		mockRepo.EXPECT().Requeue(mock.Anything, &entity.DeadJobFilter{IDs: []int64{1}}, 1).Return(0, errUnexpected).Once()
+
+		svc := Service{repo: mockRepo}
+		res, err := svc.Retry(context.Background(), 1)
+		assert.Equal(t, fmt.Errorf("failed to requeue failed event: %w", errUnexpected), err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("error retried concurrently", func(t *testing.T) {
+		mockRepo.EXPECT().FindByID(mock.Anything, int64(1)).
+			Return(&entity.DeadJob{ID: 1, Status: entity.DeadJobStatusFailed}, nil).Once()
+		mockRepo.EXPECT().Requeue(mock.Anything, &entity.DeadJobFilter{IDs: []int64{1}}, 1).Return(0, nil).Once()
+
+		svc := Service{repo: mockRepo}
+		res, err := svc.Retry(context.Background(), 1)
+		assert.Equal(t, &deadLetterError{deadLetterErrorAlreadyRetried}, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("success retry", func(t *testing.T) {
+		mockRepo.EXPECT().FindByID(mock.Anything, int64(1)).
+			Return(&entity.DeadJob{ID: 1, Status: entity.DeadJobStatusFailed}, nil).Once()
+		mockRepo.EXPECT().Requeue(mock.Anything, &entity.DeadJobFilter{IDs: []int64{1}}, 1).Return(1, nil).Once()
+
+		svc := Service{repo: mockRepo}
+		res, err := svc.Retry(context.Background(), 1)
+		assert.Nil(t, err)
+		assert.Equal(t, &RetryResponse{Retried: 1}, res)
+	})
+}
+
+func TestBulkRetry(t *testing.T) {
+	mockRepo := mocks.NewRepository(t)
+
+	t.Run("error empty filter", func(t *testing.T) {
+		svc := Service{repo: mockRepo}
+		res, err := svc.BulkRetry(context.Background(), &entity.DeadJobFilter{Status: entity.DeadJobStatusFailed})
+		assert.Equal(t, &deadLetterError{deadLetterErrorEmptyFilter}, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("error requeue", func(t *testing.T) {
+		filter := &entity.DeadJobFilter{Type: "room.resolve"}
+		mockRepo.EXPECT().Requeue(mock.Anything, filter, requeueBatchSize).Return(0, errUnexpected).Once()
+
+		svc := Service{repo: mockRepo}
+		res, err := svc.BulkRetry(context.Background(), filter)
+		assert.Equal(t, fmt.Errorf("failed to requeue failed events: %w", errUnexpected), err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("success bulk retry", func(t *testing.T) {
+		filter := &entity.DeadJobFilter{Type: "room.resolve"}
+		mockRepo.EXPECT().Requeue(mock.Anything, filter, requeueBatchSize).Return(3, nil).Once()
+
+		svc := Service{repo: mockRepo}
+		res, err := svc.BulkRetry(context.Background(), filter)
+		assert.Nil(t, err)
+		assert.Equal(t, &RetryResponse{Retried: 3}, res)
+	})
+
+	t.Run("success bulk retry in batches", func(t *testing.T) {
+		filter := &entity.DeadJobFilter{Type: "room.resolve"}
+		mockRepo.EXPECT().Requeue(mock.Anything, filter, requeueBatchSize).Return(requeueBatchSize, nil).Twice()
+		mockRepo.EXPECT().Requeue(mock.Anything, filter, requeueBatchSize).Return(20, nil).Once()
+
+		svc := Service{repo: mockRepo}
+		res, err := svc.BulkRetry(context.Background(), filter)
+		assert.Nil(t, err)
+		assert.Equal(t, &RetryResponse{Retried: 2*requeueBatchSize + 20}, res)
+	})
+
+	t.Run("success bulk retry up to the cap", func(t *testing.T) {
+		filter := &entity.DeadJobFilter{Type: "room.resolve"}
+		mockRepo.EXPECT().Requeue(mock.Anything, filter, requeueBatchSize).Return(requeueBatchSize, nil).Times(maxBulkRetry / requeueBatchSize)
+
+		svc := Service{repo: mockRepo}
+		res, err := svc.BulkRetry(context.Background(), filter)
+		assert.Nil(t, err)
+		assert.Equal(t, &RetryResponse{Retried: maxBulkRetry, HasMore: true}, res)
+	})
+}
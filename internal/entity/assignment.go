@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// Assignment records an agent assigned to a room by custom agent allocation.
+type Assignment struct {
+	ID                 int64     `json:"id"`
+	MultichannelRoomID string    `json:"multichannel_room_id" gorm:"index"`
+	AgentID            int64     `json:"agent_id" gorm:"index"`
+	AgentEmail         string    `json:"agent_email"`
+	Strategy           string    `json:"strategy"`
+	CreatedAt          time.Time `json:"created_at"`
+}
@@ -0,0 +1,63 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Job is a unit of work persisted to be processed asynchronously by the worker.
+// A job is deleted once it succeeds, or moved to DeadJob once it runs out of attempts.
+// Key identifies what the job works on, e.g. the multichannel room ID, and becomes the key
+// of its dead job.
+type Job struct {
+	ID          int64           `json:"id"`
+	Type        string          `json:"type" gorm:"index"`
+	Key         string          `json:"key"`
+	Payload     json.RawMessage `json:"payload" gorm:"type:jsonb"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	LastError   string          `json:"last_error"`
+	RequestID   string          `json:"request_id"`
+	TraceParent string          `json:"trace_parent"`
+	RunAt       time.Time       `json:"run_at" gorm:"index"`
+	LockedAt    *time.Time      `json:"locked_at"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+type DeadJobStatus string
+
+const (
+	DeadJobStatusFailed  DeadJobStatus = "failed"
+	DeadJobStatusRetried DeadJobStatus = "retried"
+)
+
+// DeadJob is a job that exhausted its attempts or failed permanently, kept for
+// inspection and replay. Failures recorded outside the worker set Key, so repeated
+// failures of the same operation are counted as attempts of a single dead job.
+type DeadJob struct {
+	ID        int64           `json:"id"`
+	JobID     int64           `json:"job_id" gorm:"index"`
+	Type      string          `json:"type" gorm:"index;uniqueIndex:uidx_dead_jobs_type_key,where:status = 'failed' AND key <> ''"`
+	Key       string          `json:"key" gorm:"uniqueIndex:uidx_dead_jobs_type_key"`
+	Payload   json.RawMessage `json:"payload" gorm:"type:jsonb"`
+	Attempts  int             `json:"attempts"`
+	Error     string          `json:"error"`
+	RequestID string          `json:"request_id" gorm:"index"`
+	Status    DeadJobStatus   `json:"status" gorm:"index;default:failed"`
+	RetriedAt *time.Time      `json:"retried_at"`
+	CreatedAt time.Time       `json:"created_at" gorm:"index"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// DeadJobFilter narrows dead jobs by any combination of its fields.
+type DeadJobFilter struct {
+	IDs       []int64       `json:"ids" validate:"omitempty,dive,gt=0"`
+	Type      string        `json:"type"`
+	Status    DeadJobStatus `json:"status" validate:"omitempty,oneof=failed retried"`
+	RequestID string        `json:"request_id"`
+	From      *time.Time    `json:"from"`
+	To        *time.Time    `json:"to"`
+	Page      int           `json:"-"`
+	Limit     int           `json:"-"`
+}
@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type JobRunStatus string
+
+const (
+	JobRunStatusRunning   JobRunStatus = "running"
+	JobRunStatusSucceeded JobRunStatus = "succeeded"
+	JobRunStatusFailed    JobRunStatus = "failed"
+)
+
+// JobRun records a single execution of a cron job. A run whose replica died stays
+// running, so it is never counted as a success.
+type JobRun struct {
+	ID         int64           `json:"id"`
+	Job        string          `json:"job" gorm:"index:idx_job_runs_job_started_at,priority:1"`
+	RequestID  string          `json:"request_id" gorm:"index"`
+	TraceID    string          `json:"trace_id"`
+	Status     JobRunStatus    `json:"status" gorm:"index"`
+	StartedAt  time.Time       `json:"started_at" gorm:"index:idx_job_runs_job_started_at,priority:2"`
+	FinishedAt *time.Time      `json:"finished_at"`
+	DurationMs int64           `json:"duration_ms"`
+	Error      string          `json:"error"`
+	Summary    json.RawMessage `json:"summary" gorm:"type:jsonb"`
+}
+
+// Finish completes the run with the outcome of the job.
+func (r *JobRun) Finish(summary map[string]int, err error, at time.Time) {
+	r.FinishedAt = &at
+	r.DurationMs = at.Sub(r.StartedAt).Milliseconds()
+	r.Status = JobRunStatusSucceeded
+	if err != nil {
+		r.Status = JobRunStatusFailed
+		r.Error = err.Error()
+	}
+
+	if summary != nil {
+		r.Summary, _ = json.Marshal(summary)
+	}
+}
+
+// JobHealth is the current state of a job, derived from its recent runs.
+type JobHealth struct {
+	Job             string     `json:"job"`
+	Healthy         bool       `json:"healthy"`
+	Paused          bool       `json:"paused"`
+	Window          string     `json:"window"`
+	LastSucceededAt *time.Time `json:"last_succeeded_at"`
+	LastRuns        []JobRun   `json:"last_runs"`
+}
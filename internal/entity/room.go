@@ -1,13 +1,144 @@
 package entity
 
 import (
+	"errors"
+	"fmt"
 	"time"
+
+	"gorm.io/gorm"
+)
+
+type RoomStatus string
+
+const (
+	RoomStatusNew           RoomStatus = "new"
+	RoomStatusAssigned      RoomStatus = "assigned"
+	RoomStatusResolved      RoomStatus = "resolved"
+	RoomStatusAssignFailed  RoomStatus = "assign_failed"
+	RoomStatusResolveFailed RoomStatus = "resolve_failed"
 )
 
+// IsValid reports whether the status is one of the known room statuses.
+func (s RoomStatus) IsValid() bool {
+	switch s {
+	case RoomStatusNew, RoomStatusAssigned, RoomStatusResolved, RoomStatusAssignFailed, RoomStatusResolveFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+var ErrInvalidRoomTransition = errors.New("invalid room status transition")
+
+// roomTransitions lists the statuses a room can move to from each status.
+// A resolved room is final within its session, see Reopen.
+var roomTransitions = map[RoomStatus][]RoomStatus{
+	RoomStatusNew:           {RoomStatusAssigned, RoomStatusAssignFailed, RoomStatusResolved, RoomStatusResolveFailed},
+	RoomStatusAssigned:      {RoomStatusResolved, RoomStatusResolveFailed},
+	RoomStatusAssignFailed:  {RoomStatusAssigned, RoomStatusResolved, RoomStatusResolveFailed},
+	RoomStatusResolveFailed: {RoomStatusResolved},
+}
+
 // Room ...
 type Room struct {
-	ID                 int64     `json:"id"`
-	MultichannelRoomID string    `json:"multichannel_room_id" gorm:"index"`
-	CreatedAt          time.Time `json:"created_at"`
-	UpdatedAt          time.Time `json:"updated_at"`
+	ID                 int64      `json:"id"`
+	MultichannelRoomID string     `json:"multichannel_room_id" gorm:"uniqueIndex:uidx_rooms_multichannel_room_id"`
+	Status             RoomStatus `json:"status" gorm:"index;default:new"`
+	AssignedAt         *time.Time `json:"assigned_at"`
+	ResolvedAt         *time.Time `json:"resolved_at"`
+	ResolvedBy         string     `json:"resolved_by"`
+	FailedAt           *time.Time `json:"failed_at"`
+	// ClosingSentAt is when the resolver sent the closing message, so a retried resolution
+	// does not send it twice.
+	ClosingSentAt *time.Time     `json:"closing_sent_at"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+}
+
+// IsResolved reports whether the room is already closed in Omnichannel.
+func (r *Room) IsResolved() bool {
+	return r.Status == RoomStatusResolved
+}
+
+// Transition moves the room to the status and stamps the time of the transition.
+// The actor is kept as ResolvedBy when the room is resolved.
+func (r *Room) Transition(to RoomStatus, actor string, at time.Time) error {
+	valid := false
+	for _, status := range roomTransitions[r.Status] {
+		if status == to {
+			valid = true
+			break
+		}
+	}
+
+	if !valid {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidRoomTransition, r.Status, to)
+	}
+
+	r.Status = to
+	switch to {
+	case RoomStatusAssigned:
+		r.AssignedAt = &at
+	case RoomStatusResolved:
+		r.ResolvedAt = &at
+		r.ResolvedBy = actor
+	case RoomStatusAssignFailed, RoomStatusResolveFailed:
+		r.FailedAt = &at
+	}
+
+	return nil
+}
+
+// Reopen moves a resolved room back to new for the next session of its customer, since
+// Qiscus reuses the room ID. The room starts over at the given time, without the
+// assignment and resolution of the previous session.
+func (r *Room) Reopen(at time.Time) error {
+	if r.Status != RoomStatusResolved {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidRoomTransition, r.Status, RoomStatusNew)
+	}
+
+	r.Status = RoomStatusNew
+	r.AssignedAt = nil
+	r.ResolvedAt = nil
+	r.ResolvedBy = ""
+	r.FailedAt = nil
+	r.ClosingSentAt = nil
+	r.DeletedAt = gorm.DeletedAt{}
+	r.CreatedAt = at
+
+	return nil
+}
+
+// RoomEvent is a status transition of a room, kept as the room history.
+type RoomEvent struct {
+	ID                 int64      `json:"id"`
+	RoomID             int64      `json:"room_id" gorm:"index"`
+	MultichannelRoomID string     `json:"multichannel_room_id" gorm:"index"`
+	FromStatus         RoomStatus `json:"from_status"`
+	ToStatus           RoomStatus `json:"to_status"`
+	Actor              string     `json:"actor"`
+	Note               string     `json:"note"`
+	CreatedAt          time.Time  `json:"created_at"`
+}
+
+// RoomFilter narrows rooms by any combination of its fields, sorted by SortBy. Cursor
+// continues after the last room of the previous page and takes precedence over Page.
+type RoomFilter struct {
+	MultichannelRoomID string
+	Status             RoomStatus
+	From               *time.Time
+	To                 *time.Time
+	SortBy             string
+	SortDesc           bool
+	Cursor             *RoomCursor
+	Page               int
+	Limit              int
+}
+
+// RoomCursor is the position of a room in a listing: its ID and its value of the sort
+// column, zero when sorting by ID.
+type RoomCursor struct {
+	ID    int64     `json:"id"`
+	Value time.Time `json:"value"`
 }
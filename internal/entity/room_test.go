@@ -0,0 +1,89 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestRoom_Transition(t *testing.T) {
+	at := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		from      RoomStatus
+		to        RoomStatus
+		expectErr bool
+	}{
+		{name: "new to assigned", from: RoomStatusNew, to: RoomStatusAssigned},
+		{name: "new to assign failed", from: RoomStatusNew, to: RoomStatusAssignFailed},
+		{name: "assign failed to assigned", from: RoomStatusAssignFailed, to: RoomStatusAssigned},
+		{name: "assigned to resolved", from: RoomStatusAssigned, to: RoomStatusResolved},
+		{name: "assigned to resolve failed", from: RoomStatusAssigned, to: RoomStatusResolveFailed},
+		{name: "resolve failed to resolved", from: RoomStatusResolveFailed, to: RoomStatusResolved},
+		{name: "assigned to new", from: RoomStatusAssigned, to: RoomStatusNew, expectErr: true},
+		{name: "resolve failed to assigned", from: RoomStatusResolveFailed, to: RoomStatusAssigned, expectErr: true},
+		{name: "resolved is final", from: RoomStatusResolved, to: RoomStatusAssigned, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			room := &Room{Status: tt.from}
+			err := room.Transition(tt.to, "agent@mail.com", at)
+
+			if tt.expectErr {
+				assert.True(t, errors.Is(err, ErrInvalidRoomTransition))
+				assert.Equal(t, tt.from, room.Status)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.to, room.Status)
+		})
+	}
+}
+
+func TestRoom_Transition_Timestamps(t *testing.T) {
+	at := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	room := &Room{Status: RoomStatusNew}
+	assert.Nil(t, room.Transition(RoomStatusAssignFailed, "", at))
+	assert.Equal(t, &at, room.FailedAt)
+
+	assert.Nil(t, room.Transition(RoomStatusAssigned, "agent@mail.com", at))
+	assert.Equal(t, &at, room.AssignedAt)
+	assert.Empty(t, room.ResolvedBy)
+
+	assert.Nil(t, room.Transition(RoomStatusResolved, "agent@mail.com", at))
+	assert.Equal(t, &at, room.ResolvedAt)
+	assert.Equal(t, "agent@mail.com", room.ResolvedBy)
+	assert.True(t, room.IsResolved())
+}
+
+func TestRoomStatus_IsValid(t *testing.T) {
+	assert.True(t, RoomStatusNew.IsValid())
+	assert.True(t, RoomStatusResolveFailed.IsValid())
+	assert.False(t, RoomStatus("closed").IsValid())
+	assert.False(t, RoomStatus("").IsValid())
+}
+
+func TestRoom_Reopen(t *testing.T) {
+	at := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	reopenedAt := at.Add(24 * time.Hour)
+
+	room := &Room{Status: RoomStatusNew, CreatedAt: at}
+	assert.Nil(t, room.Transition(RoomStatusAssigned, "agent@mail.com", at))
+	assert.Nil(t, room.Transition(RoomStatusResolved, "agent@mail.com", at))
+	room.DeletedAt = gorm.DeletedAt{Time: at, Valid: true}
+	room.ClosingSentAt = &at
+
+	assert.Nil(t, room.Reopen(reopenedAt))
+	assert.Equal(t, &Room{Status: RoomStatusNew, CreatedAt: reopenedAt}, room)
+
+	// Only a resolved room starts a new session
+	err := room.Reopen(reopenedAt)
+	assert.True(t, errors.Is(err, ErrInvalidRoomTransition))
+}
@@ -0,0 +1,35 @@
+package jobrun
+
+import (
+	"context"
+	"time"
+)
+
+type cronJob struct {
+	svc *Service
+}
+
+// NewCronJob returns the job that deletes the runs older than the retention, to be
+// registered in the cron server.
+func NewCronJob(svc *Service) *cronJob {
+	return &cronJob{
+		svc: svc,
+	}
+}
+
+func (j *cronJob) Name() string {
+	return "job_runs_cleanup"
+}
+
+func (j *cronJob) Schedule() string {
+	return "0 3 * * *"
+}
+
+func (j *cronJob) Timeout() time.Duration {
+	return 5 * time.Minute
+}
+
+func (j *cronJob) Run(ctx context.Context) (map[string]int, error) {
+	deleted, err := j.svc.Prune(ctx)
+	return map[string]int{"deleted": int(deleted)}, err
+}
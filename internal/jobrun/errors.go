@@ -0,0 +1,39 @@
+package jobrun
+
+import "net/http"
+
+type jobRunError struct {
+	code int
+}
+
+const (
+	jobRunErrorInvalidLimit = iota
+	jobRunErrorJobNotFound
+)
+
+func (e *jobRunError) Error() string {
+	switch e.code {
+	case jobRunErrorInvalidLimit:
+		return "Limit must be a positive number"
+	case jobRunErrorJobNotFound:
+		return "Job not found"
+	default:
+		return "Unknown error code"
+	}
+}
+
+// HTTPMessage is the message sent to the client, which is the message of the error.
+func (e *jobRunError) HTTPMessage() string {
+	return e.Error()
+}
+
+func (e *jobRunError) HTTPStatusCode() int {
+	switch e.code {
+	case jobRunErrorInvalidLimit:
+		return http.StatusBadRequest
+	case jobRunErrorJobNotFound:
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
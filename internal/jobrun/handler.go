@@ -0,0 +1,104 @@
+package jobrun
+
+import (
+	"integration-go/internal/pkg/api/resp"
+	"net/http"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultLimit = 10
+	maxLimit     = 100
+)
+
+type httpHandler struct {
+	svc *Service
+}
+
+func NewHttpHandler(svc *Service) *httpHandler {
+	return &httpHandler{
+		svc: svc,
+	}
+}
+
+func (h *httpHandler) GetJobs(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit := defaultLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		l, err := strconv.Atoi(v)
+		if err != nil || l < 1 {
+			resp.WriteJSONFromError(w, &jobRunError{jobRunErrorInvalidLimit})
+			return
+		}
+		limit = min(l, maxLimit)
+	}
+
+	jobs, err := h.svc.GetJobs(ctx, limit)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("failed to get jobs: %s", err.Error())
+		resp.WriteJSONFromError(w, err)
+		return
+	}
+
+	resp.WriteJSON(w, http.StatusOK, jobs)
+}
+
+func (h *httpHandler) Check(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	jobs, isHealthy, err := h.svc.Check(ctx)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("failed to check jobs: %s", err.Error())
+		resp.WriteJSONFromError(w, err)
+		return
+	}
+
+	statusCode := http.StatusOK
+	if !isHealthy {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	resp.WriteJSON(w, statusCode, jobs)
+}
+
+func (h *httpHandler) Trigger(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	res, err := h.svc.Trigger(ctx, r.PathValue("name"))
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("failed to trigger job: %s", err.Error())
+		resp.WriteJSONFromError(w, err)
+		return
+	}
+
+	resp.WriteJSON(w, http.StatusAccepted, res)
+}
+
+func (h *httpHandler) Pause(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	res, err := h.svc.Pause(ctx, r.PathValue("name"))
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("failed to pause job: %s", err.Error())
+		resp.WriteJSONFromError(w, err)
+		return
+	}
+
+	resp.WriteJSON(w, http.StatusOK, res)
+}
+
+func (h *httpHandler) Resume(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	res, err := h.svc.Resume(ctx, r.PathValue("name"))
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("failed to resume job: %s", err.Error())
+		resp.WriteJSONFromError(w, err)
+		return
+	}
+
+	resp.WriteJSON(w, http.StatusOK, res)
+}
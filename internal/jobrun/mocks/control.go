@@ -0,0 +1,350 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Control is an autogenerated mock type for the Control type
+type Control struct {
+	mock.Mock
+}
+
+type Control_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Control) EXPECT() *Control_Expecter {
+	return &Control_Expecter{mock: &_m.Mock}
+}
+
+// IsPaused provides a mock function with given fields: ctx, name
+func (_m *Control) IsPaused(ctx context.Context, name string) (bool, error) {
+	ret := _m.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsPaused")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, name)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Control_IsPaused_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsPaused'
+type Control_IsPaused_Call struct {
+	*mock.Call
+}
+
+// IsPaused is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+func (_e *Control_Expecter) IsPaused(ctx interface{}, name interface{}) *Control_IsPaused_Call {
+	return &Control_IsPaused_Call{Call: _e.mock.On("IsPaused", ctx, name)}
+}
+
+func (_c *Control_IsPaused_Call) Run(run func(ctx context.Context, name string)) *Control_IsPaused_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Control_IsPaused_Call) Return(_a0 bool, _a1 error) *Control_IsPaused_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Control_IsPaused_Call) RunAndReturn(run func(context.Context, string) (bool, error)) *Control_IsPaused_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IsRegistered provides a mock function with given fields: ctx, name
+func (_m *Control) IsRegistered(ctx context.Context, name string) (bool, error) {
+	ret := _m.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsRegistered")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, name)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Control_IsRegistered_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsRegistered'
+type Control_IsRegistered_Call struct {
+	*mock.Call
+}
+
+// IsRegistered is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+func (_e *Control_Expecter) IsRegistered(ctx interface{}, name interface{}) *Control_IsRegistered_Call {
+	return &Control_IsRegistered_Call{Call: _e.mock.On("IsRegistered", ctx, name)}
+}
+
+func (_c *Control_IsRegistered_Call) Run(run func(ctx context.Context, name string)) *Control_IsRegistered_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Control_IsRegistered_Call) Return(_a0 bool, _a1 error) *Control_IsRegistered_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Control_IsRegistered_Call) RunAndReturn(run func(context.Context, string) (bool, error)) *Control_IsRegistered_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Jobs provides a mock function with given fields: ctx
+func (_m *Control) Jobs(ctx context.Context) (map[string]string, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Jobs")
+	}
+
+	var r0 map[string]string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (map[string]string, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) map[string]string); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Control_Jobs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Jobs'
+type Control_Jobs_Call struct {
+	*mock.Call
+}
+
+// Jobs is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *Control_Expecter) Jobs(ctx interface{}) *Control_Jobs_Call {
+	return &Control_Jobs_Call{Call: _e.mock.On("Jobs", ctx)}
+}
+
+func (_c *Control_Jobs_Call) Run(run func(ctx context.Context)) *Control_Jobs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Control_Jobs_Call) Return(_a0 map[string]string, _a1 error) *Control_Jobs_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Control_Jobs_Call) RunAndReturn(run func(context.Context) (map[string]string, error)) *Control_Jobs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Pause provides a mock function with given fields: ctx, name
+func (_m *Control) Pause(ctx context.Context, name string) error {
+	ret := _m.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Pause")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Control_Pause_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Pause'
+type Control_Pause_Call struct {
+	*mock.Call
+}
+
+// Pause is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+func (_e *Control_Expecter) Pause(ctx interface{}, name interface{}) *Control_Pause_Call {
+	return &Control_Pause_Call{Call: _e.mock.On("Pause", ctx, name)}
+}
+
+func (_c *Control_Pause_Call) Run(run func(ctx context.Context, name string)) *Control_Pause_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Control_Pause_Call) Return(_a0 error) *Control_Pause_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Control_Pause_Call) RunAndReturn(run func(context.Context, string) error) *Control_Pause_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Resume provides a mock function with given fields: ctx, name
+func (_m *Control) Resume(ctx context.Context, name string) error {
+	ret := _m.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Resume")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Control_Resume_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Resume'
+type Control_Resume_Call struct {
+	*mock.Call
+}
+
+// Resume is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+func (_e *Control_Expecter) Resume(ctx interface{}, name interface{}) *Control_Resume_Call {
+	return &Control_Resume_Call{Call: _e.mock.On("Resume", ctx, name)}
+}
+
+func (_c *Control_Resume_Call) Run(run func(ctx context.Context, name string)) *Control_Resume_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Control_Resume_Call) Return(_a0 error) *Control_Resume_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Control_Resume_Call) RunAndReturn(run func(context.Context, string) error) *Control_Resume_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Trigger provides a mock function with given fields: ctx, name, reqID
+func (_m *Control) Trigger(ctx context.Context, name string, reqID string) error {
+	ret := _m.Called(ctx, name, reqID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Trigger")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, name, reqID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Control_Trigger_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Trigger'
+type Control_Trigger_Call struct {
+	*mock.Call
+}
+
+// Trigger is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - reqID string
+func (_e *Control_Expecter) Trigger(ctx interface{}, name interface{}, reqID interface{}) *Control_Trigger_Call {
+	return &Control_Trigger_Call{Call: _e.mock.On("Trigger", ctx, name, reqID)}
+}
+
+func (_c *Control_Trigger_Call) Run(run func(ctx context.Context, name string, reqID string)) *Control_Trigger_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Control_Trigger_Call) Return(_a0 error) *Control_Trigger_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Control_Trigger_Call) RunAndReturn(run func(context.Context, string, string) error) *Control_Trigger_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewControl creates a new instance of Control. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewControl(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Control {
+	mock := &Control{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,215 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "integration-go/internal/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// Repository is an autogenerated mock type for the Repository type
+type Repository struct {
+	mock.Mock
+}
+
+type Repository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Repository) EXPECT() *Repository_Expecter {
+	return &Repository_Expecter{mock: &_m.Mock}
+}
+
+// DeleteBefore provides a mock function with given fields: ctx, before
+func (_m *Repository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteBefore")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_DeleteBefore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteBefore'
+type Repository_DeleteBefore_Call struct {
+	*mock.Call
+}
+
+// DeleteBefore is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+func (_e *Repository_Expecter) DeleteBefore(ctx interface{}, before interface{}) *Repository_DeleteBefore_Call {
+	return &Repository_DeleteBefore_Call{Call: _e.mock.On("DeleteBefore", ctx, before)}
+}
+
+func (_c *Repository_DeleteBefore_Call) Run(run func(ctx context.Context, before time.Time)) *Repository_DeleteBefore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *Repository_DeleteBefore_Call) Return(_a0 int64, _a1 error) *Repository_DeleteBefore_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_DeleteBefore_Call) RunAndReturn(run func(context.Context, time.Time) (int64, error)) *Repository_DeleteBefore_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FetchLatest provides a mock function with given fields: ctx, job, limit
+func (_m *Repository) FetchLatest(ctx context.Context, job string, limit int) ([]entity.JobRun, error) {
+	ret := _m.Called(ctx, job, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchLatest")
+	}
+
+	var r0 []entity.JobRun
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]entity.JobRun, error)); ok {
+		return rf(ctx, job, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []entity.JobRun); ok {
+		r0 = rf(ctx, job, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.JobRun)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, job, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_FetchLatest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FetchLatest'
+type Repository_FetchLatest_Call struct {
+	*mock.Call
+}
+
+// FetchLatest is a helper method to define mock.On call
+//   - ctx context.Context
+//   - job string
+//   - limit int
+func (_e *Repository_Expecter) FetchLatest(ctx interface{}, job interface{}, limit interface{}) *Repository_FetchLatest_Call {
+	return &Repository_FetchLatest_Call{Call: _e.mock.On("FetchLatest", ctx, job, limit)}
+}
+
+func (_c *Repository_FetchLatest_Call) Run(run func(ctx context.Context, job string, limit int)) *Repository_FetchLatest_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *Repository_FetchLatest_Call) Return(_a0 []entity.JobRun, _a1 error) *Repository_FetchLatest_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_FetchLatest_Call) RunAndReturn(run func(context.Context, string, int) ([]entity.JobRun, error)) *Repository_FetchLatest_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindLastSucceeded provides a mock function with given fields: ctx, job
+func (_m *Repository) FindLastSucceeded(ctx context.Context, job string) (*entity.JobRun, error) {
+	ret := _m.Called(ctx, job)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindLastSucceeded")
+	}
+
+	var r0 *entity.JobRun
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.JobRun, error)); ok {
+		return rf(ctx, job)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.JobRun); ok {
+		r0 = rf(ctx, job)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.JobRun)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, job)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_FindLastSucceeded_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindLastSucceeded'
+type Repository_FindLastSucceeded_Call struct {
+	*mock.Call
+}
+
+// FindLastSucceeded is a helper method to define mock.On call
+//   - ctx context.Context
+//   - job string
+func (_e *Repository_Expecter) FindLastSucceeded(ctx interface{}, job interface{}) *Repository_FindLastSucceeded_Call {
+	return &Repository_FindLastSucceeded_Call{Call: _e.mock.On("FindLastSucceeded", ctx, job)}
+}
+
+func (_c *Repository_FindLastSucceeded_Call) Run(run func(ctx context.Context, job string)) *Repository_FindLastSucceeded_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Repository_FindLastSucceeded_Call) Return(_a0 *entity.JobRun, _a1 error) *Repository_FindLastSucceeded_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_FindLastSucceeded_Call) RunAndReturn(run func(context.Context, string) (*entity.JobRun, error)) *Repository_FindLastSucceeded_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewRepository creates a new instance of Repository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Repository {
+	mock := &Repository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
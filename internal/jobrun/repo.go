@@ -0,0 +1,70 @@
+package jobrun
+
+import (
+	"context"
+	"integration-go/internal/entity"
+	"integration-go/internal/pkg/postgres"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type repo struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *repo {
+	return &repo{
+		db: db,
+	}
+}
+
+func (r *repo) Create(ctx context.Context, run *entity.JobRun) error {
+	return r.db.WithContext(ctx).Create(run).Error
+}
+
+// Save records the outcome of the run. It is rejected under a stale fencing token, so a
+// replica that lost the lock of the job cannot overwrite the runs of the new holder.
+func (r *repo) Save(ctx context.Context, run *entity.JobRun) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := postgres.CheckFencingToken(ctx, tx); err != nil {
+			return err
+		}
+
+		return tx.Save(run).Error
+	})
+}
+
+// FetchLatest returns the last runs of the job, most recent first.
+func (r *repo) FetchLatest(ctx context.Context, job string, limit int) ([]entity.JobRun, error) {
+	var runs []entity.JobRun
+	err := r.db.WithContext(ctx).
+		Where("job = ?", job).
+		Order("started_at DESC").
+		Limit(limit).
+		Find(&runs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+func (r *repo) FindLastSucceeded(ctx context.Context, job string) (*entity.JobRun, error) {
+	var run entity.JobRun
+	err := r.db.WithContext(ctx).
+		Where("job = ? AND status = ?", job, entity.JobRunStatusSucceeded).
+		Order("started_at DESC").
+		First(&run).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &run, nil
+}
+
+// DeleteBefore deletes the runs started before the given time and returns their count.
+func (r *repo) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).Where("started_at < ?", before).Delete(&entity.JobRun{})
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,31 @@
+package jobrun
+
+import (
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// scheduleSamples is the number of upcoming runs whose intervals are compared, enough to
+// cover the longer gaps of schedules skipping days, e.g. weekends.
+const scheduleSamples = 10
+
+// scheduleWindow returns twice the longest interval between the upcoming runs of the cron
+// schedule, e.g. 48h for a daily job, so a job is healthy until it missed a run. It is zero
+// for an invalid schedule.
+func scheduleWindow(schedule string, now time.Time) time.Duration {
+	s, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return 0
+	}
+
+	var longest time.Duration
+	prev := s.Next(now)
+	for range scheduleSamples {
+		next := s.Next(prev)
+		longest = max(longest, next.Sub(prev))
+		prev = next
+	}
+
+	return 2 * longest
+}
@@ -0,0 +1,206 @@
+package jobrun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"integration-go/internal/entity"
+	"integration-go/internal/pkg/config"
+	"maps"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:generate mockery --with-expecter --case snake --name Repository
+type Repository interface {
+	FetchLatest(ctx context.Context, job string, limit int) ([]entity.JobRun, error)
+	FindLastSucceeded(ctx context.Context, job string) (*entity.JobRun, error)
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+//go:generate mockery --with-expecter --case snake --name Control
+type Control interface {
+	Jobs(ctx context.Context) (map[string]string, error)
+	IsRegistered(ctx context.Context, name string) (bool, error)
+	IsPaused(ctx context.Context, name string) (bool, error)
+	Pause(ctx context.Context, name string) error
+	Resume(ctx context.Context, name string) error
+	Trigger(ctx context.Context, name string, reqID string) error
+}
+
+type Service struct {
+	repo    Repository
+	control Control
+	// window is the minimum time within which a job must have succeeded to be healthy,
+	// windows overrides the window per job name.
+	window    time.Duration
+	windows   map[string]time.Duration
+	retention time.Duration
+}
+
+func NewService(repo Repository, control Control, window time.Duration, windows map[string]time.Duration, retention time.Duration) *Service {
+	return &Service{
+		repo:      repo,
+		control:   control,
+		window:    window,
+		windows:   windows,
+		retention: retention,
+	}
+}
+
+// Window returns the time within which the job must have succeeded to be healthy: its
+// override if any, otherwise the window of its schedule, never below the minimum window.
+func (s *Service) Window(job, schedule string, now time.Time) time.Duration {
+	if window, ok := s.windows[job]; ok {
+		return window
+	}
+
+	return max(s.window, scheduleWindow(schedule, now))
+}
+
+// GetJobs returns the health and the last runs of every registered job, sorted by name. A
+// job without a successful run within its window is unhealthy, including one that never
+// ran.
+func (s *Service) GetJobs(ctx context.Context, limit int) ([]entity.JobHealth, error) {
+	schedules, err := s.control.Jobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := slices.Sorted(maps.Keys(schedules))
+
+	now := time.Now()
+	healths := make([]entity.JobHealth, 0, len(jobs))
+	for _, job := range jobs {
+		health, err := s.health(ctx, job, s.Window(job, schedules[job], now), limit, now)
+		if err != nil {
+			return nil, err
+		}
+
+		healths = append(healths, *health)
+	}
+
+	return healths, nil
+}
+
+// Check reports whether every job that is not paused has succeeded within its window.
+func (s *Service) Check(ctx context.Context) ([]entity.JobHealth, bool, error) {
+	healths, err := s.GetJobs(ctx, 1)
+	if err != nil {
+		return nil, false, err
+	}
+
+	isHealthy := true
+	for _, health := range healths {
+		isHealthy = isHealthy && (health.Healthy || health.Paused)
+	}
+
+	return healths, isHealthy, nil
+}
+
+func (s *Service) health(ctx context.Context, job string, window time.Duration, limit int, now time.Time) (*entity.JobHealth, error) {
+	runs, err := s.repo.FetchLatest(ctx, job, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch runs of job %s: %w", job, err)
+	}
+
+	health := &entity.JobHealth{
+		Job:      job,
+		Window:   window.String(),
+		LastRuns: runs,
+	}
+
+	health.Paused, err = s.control.IsPaused(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+
+	lastSucceeded, err := s.repo.FindLastSucceeded(ctx, job)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to find last succeeded run of job %s: %w", job, err)
+	}
+
+	if lastSucceeded != nil {
+		health.LastSucceededAt = lastSucceeded.FinishedAt
+		health.Healthy = lastSucceeded.FinishedAt != nil && now.Sub(*lastSucceeded.FinishedAt) <= window
+	}
+
+	return health, nil
+}
+
+type TriggerResponse struct {
+	Job       string `json:"job"`
+	RequestID string `json:"request_id"`
+}
+
+type PauseResponse struct {
+	Job    string `json:"job"`
+	Paused bool   `json:"paused"`
+}
+
+// Trigger requests a single run of the job on a cron replica. The run is recorded with
+// the request ID of the caller, so it can be found in the job runs.
+func (s *Service) Trigger(ctx context.Context, name string) (*TriggerResponse, error) {
+	if err := s.checkRegistered(ctx, name); err != nil {
+		return nil, err
+	}
+
+	reqID, _ := ctx.Value(config.RequestIDKey).(string)
+	if err := s.control.Trigger(ctx, name, reqID); err != nil {
+		return nil, err
+	}
+
+	return &TriggerResponse{
+		Job:       name,
+		RequestID: reqID,
+	}, nil
+}
+
+func (s *Service) Pause(ctx context.Context, name string) (*PauseResponse, error) {
+	if err := s.checkRegistered(ctx, name); err != nil {
+		return nil, err
+	}
+
+	if err := s.control.Pause(ctx, name); err != nil {
+		return nil, err
+	}
+
+	return &PauseResponse{Job: name, Paused: true}, nil
+}
+
+func (s *Service) Resume(ctx context.Context, name string) (*PauseResponse, error) {
+	if err := s.checkRegistered(ctx, name); err != nil {
+		return nil, err
+	}
+
+	if err := s.control.Resume(ctx, name); err != nil {
+		return nil, err
+	}
+
+	return &PauseResponse{Job: name, Paused: false}, nil
+}
+
+func (s *Service) checkRegistered(ctx context.Context, name string) error {
+	ok, err := s.control.IsRegistered(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return &jobRunError{jobRunErrorJobNotFound}
+	}
+
+	return nil
+}
+
+// Prune deletes the runs older than the retention and returns their count.
+func (s *Service) Prune(ctx context.Context) (int64, error) {
+	deleted, err := s.repo.DeleteBefore(ctx, time.Now().Add(-s.retention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete job runs: %w", err)
+	}
+
+	return deleted, nil
+}
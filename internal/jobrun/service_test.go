@@ -0,0 +1,294 @@
+package jobrun
+
+import (
+	"context"
+	"fmt"
+	"integration-go/internal/entity"
+	"integration-go/internal/jobrun/mocks"
+	"integration-go/internal/pkg/config"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+var errUnexpected = fmt.Errorf("unexpected")
+
+func TestGetJobs(t *testing.T) {
+	mockRepo := mocks.NewRepository(t)
+	mockControl := mocks.NewControl(t)
+	windows := map[string]time.Duration{"job_runs_cleanup": 26 * time.Hour}
+
+	t.Run("error fetch jobs", func(t *testing.T) {
+		mockControl.EXPECT().Jobs(mock.Anything).Return(nil, errUnexpected).Once()
+
+		svc := NewService(mockRepo, mockControl, 15*time.Minute, windows, time.Hour)
+		jobs, err := svc.GetJobs(context.Background(), 10)
+		assert.Equal(t, errUnexpected, err)
+		assert.Nil(t, jobs)
+	})
+
+	t.Run("error fetch runs", func(t *testing.T) {
+		mockControl.EXPECT().Jobs(mock.Anything).Return(map[string]string{"resolver": "* * * * *"}, nil).Once()
+		mockRepo.EXPECT().FetchLatest(mock.Anything, "resolver", 10).Return(nil, errUnexpected).Once()
+
+		svc := NewService(mockRepo, mockControl, 15*time.Minute, windows, time.Hour)
+		jobs, err := svc.GetJobs(context.Background(), 10)
+		assert.Equal(t, fmt.Errorf("failed to fetch runs of job resolver: %w", errUnexpected), err)
+		assert.Nil(t, jobs)
+	})
+
+	t.Run("success get jobs", func(t *testing.T) {
+		recent := time.Now().Add(-time.Minute)
+		stale := time.Now().Add(-time.Hour)
+		lastCleanup := time.Now().Add(-25 * time.Hour)
+
+		mockControl.EXPECT().Jobs(mock.Anything).Return(map[string]string{"job_runs_cleanup": "0 3 * * *", "notifier": "*/5 * * * *", "resolver": "* * * * *"}, nil).Once()
+		mockRepo.EXPECT().FetchLatest(mock.Anything, "job_runs_cleanup", 10).
+			Return([]entity.JobRun{{ID: 1, Status: entity.JobRunStatusSucceeded, FinishedAt: &lastCleanup}}, nil).Once()
+		mockControl.EXPECT().IsPaused(mock.Anything, "job_runs_cleanup").Return(false, nil).Once()
+		mockRepo.EXPECT().FindLastSucceeded(mock.Anything, "job_runs_cleanup").
+			Return(&entity.JobRun{ID: 1, Status: entity.JobRunStatusSucceeded, FinishedAt: &lastCleanup}, nil).Once()
+		mockRepo.EXPECT().FetchLatest(mock.Anything, "notifier", 10).
+			Return([]entity.JobRun{{ID: 2, Status: entity.JobRunStatusFailed, FinishedAt: &recent}}, nil).Once()
+		mockControl.EXPECT().IsPaused(mock.Anything, "notifier").Return(false, nil).Once()
+		mockRepo.EXPECT().FindLastSucceeded(mock.Anything, "notifier").Return(nil, gorm.ErrRecordNotFound).Once()
+		mockRepo.EXPECT().FetchLatest(mock.Anything, "resolver", 10).
+			Return([]entity.JobRun{{ID: 3, Status: entity.JobRunStatusFailed, FinishedAt: &recent}}, nil).Once()
+		mockControl.EXPECT().IsPaused(mock.Anything, "resolver").Return(false, nil).Once()
+		mockRepo.EXPECT().FindLastSucceeded(mock.Anything, "resolver").
+			Return(&entity.JobRun{ID: 4, Status: entity.JobRunStatusSucceeded, FinishedAt: &stale}, nil).Once()
+
+		svc := NewService(mockRepo, mockControl, 15*time.Minute, windows, time.Hour)
+		jobs, err := svc.GetJobs(context.Background(), 10)
+		assert.Nil(t, err)
+		assert.Len(t, jobs, 3)
+
+		assert.True(t, jobs[0].Healthy)
+		assert.Equal(t, "26h0m0s", jobs[0].Window)
+		assert.Equal(t, &lastCleanup, jobs[0].LastSucceededAt)
+
+		assert.False(t, jobs[1].Healthy)
+		assert.Nil(t, jobs[1].LastSucceededAt)
+		assert.Len(t, jobs[1].LastRuns, 1)
+
+		assert.False(t, jobs[2].Healthy)
+		assert.Equal(t, "15m0s", jobs[2].Window)
+		assert.Equal(t, &stale, jobs[2].LastSucceededAt)
+	})
+}
+
+func TestCheck(t *testing.T) {
+	mockRepo := mocks.NewRepository(t)
+	mockControl := mocks.NewControl(t)
+
+	t.Run("healthy", func(t *testing.T) {
+		recent := time.Now().Add(-time.Minute)
+		mockControl.EXPECT().Jobs(mock.Anything).Return(map[string]string{"resolver": "* * * * *"}, nil).Once()
+		mockRepo.EXPECT().FetchLatest(mock.Anything, "resolver", 1).
+			Return([]entity.JobRun{{ID: 1, Status: entity.JobRunStatusSucceeded, FinishedAt: &recent}}, nil).Once()
+		mockControl.EXPECT().IsPaused(mock.Anything, "resolver").Return(false, nil).Once()
+		mockRepo.EXPECT().FindLastSucceeded(mock.Anything, "resolver").
+			Return(&entity.JobRun{ID: 1, Status: entity.JobRunStatusSucceeded, FinishedAt: &recent}, nil).Once()
+
+		svc := NewService(mockRepo, mockControl, 15*time.Minute, nil, time.Hour)
+		jobs, isHealthy, err := svc.Check(context.Background())
+		assert.Nil(t, err)
+		assert.True(t, isHealthy)
+		assert.Len(t, jobs, 1)
+	})
+
+	t.Run("unhealthy", func(t *testing.T) {
+		stale := time.Now().Add(-time.Hour)
+		mockControl.EXPECT().Jobs(mock.Anything).Return(map[string]string{"resolver": "* * * * *"}, nil).Once()
+		mockRepo.EXPECT().FetchLatest(mock.Anything, "resolver", 1).
+			Return([]entity.JobRun{{ID: 1, Status: entity.JobRunStatusSucceeded, FinishedAt: &stale}}, nil).Once()
+		mockControl.EXPECT().IsPaused(mock.Anything, "resolver").Return(false, nil).Once()
+		mockRepo.EXPECT().FindLastSucceeded(mock.Anything, "resolver").
+			Return(&entity.JobRun{ID: 1, Status: entity.JobRunStatusSucceeded, FinishedAt: &stale}, nil).Once()
+
+		svc := NewService(mockRepo, mockControl, 15*time.Minute, nil, time.Hour)
+		jobs, isHealthy, err := svc.Check(context.Background())
+		assert.Nil(t, err)
+		assert.False(t, isHealthy)
+		assert.Len(t, jobs, 1)
+	})
+}
+
+func TestCheck_ScheduleWindow(t *testing.T) {
+	mockRepo := mocks.NewRepository(t)
+	mockControl := mocks.NewControl(t)
+
+	// A daily job succeeded 2h ago, beyond the minimum window but within its schedule
+	lastCleanup := time.Now().Add(-2 * time.Hour)
+	mockControl.EXPECT().Jobs(mock.Anything).Return(map[string]string{"job_runs_cleanup": "0 3 * * *"}, nil).Once()
+	mockRepo.EXPECT().FetchLatest(mock.Anything, "job_runs_cleanup", 1).
+		Return([]entity.JobRun{{ID: 1, Status: entity.JobRunStatusSucceeded, FinishedAt: &lastCleanup}}, nil).Once()
+	mockControl.EXPECT().IsPaused(mock.Anything, "job_runs_cleanup").Return(false, nil).Once()
+	mockRepo.EXPECT().FindLastSucceeded(mock.Anything, "job_runs_cleanup").
+		Return(&entity.JobRun{ID: 1, Status: entity.JobRunStatusSucceeded, FinishedAt: &lastCleanup}, nil).Once()
+
+	svc := NewService(mockRepo, mockControl, 15*time.Minute, nil, time.Hour)
+	jobs, isHealthy, err := svc.Check(context.Background())
+	assert.Nil(t, err)
+	assert.True(t, isHealthy)
+	assert.True(t, jobs[0].Healthy)
+	assert.Equal(t, "48h0m0s", jobs[0].Window)
+}
+
+func TestService_Window(t *testing.T) {
+	svc := NewService(nil, nil, 15*time.Minute, map[string]time.Duration{"job_runs_cleanup": 26 * time.Hour}, time.Hour)
+	now := time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		job      string
+		schedule string
+		want     time.Duration
+	}{
+		{name: "override", job: "job_runs_cleanup", schedule: "0 3 * * *", want: 26 * time.Hour},
+		{name: "frequent job gets the minimum window", job: "resolver", schedule: "* * * * *", want: 15 * time.Minute},
+		{name: "hourly job", job: "report", schedule: "0 * * * *", want: 2 * time.Hour},
+		{name: "weekday job covers the weekend", job: "report", schedule: "0 9 * * 1-5", want: 6 * 24 * time.Hour},
+		{name: "invalid schedule gets the minimum window", job: "report", schedule: "invalid", want: 15 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, svc.Window(tt.job, tt.schedule, now))
+		})
+	}
+}
+
+func TestCheck_NeverRan(t *testing.T) {
+	mockRepo := mocks.NewRepository(t)
+	mockControl := mocks.NewControl(t)
+
+	// A registered job without any run, e.g. its replicas fail before recording one
+	mockControl.EXPECT().Jobs(mock.Anything).Return(map[string]string{"resolver": "* * * * *"}, nil).Once()
+	mockRepo.EXPECT().FetchLatest(mock.Anything, "resolver", 1).Return(nil, nil).Once()
+	mockControl.EXPECT().IsPaused(mock.Anything, "resolver").Return(false, nil).Once()
+	mockRepo.EXPECT().FindLastSucceeded(mock.Anything, "resolver").Return(nil, gorm.ErrRecordNotFound).Once()
+
+	svc := NewService(mockRepo, mockControl, 15*time.Minute, nil, time.Hour)
+	jobs, isHealthy, err := svc.Check(context.Background())
+	assert.Nil(t, err)
+	assert.False(t, isHealthy)
+	assert.Equal(t, []entity.JobHealth{{Job: "resolver", Window: "15m0s"}}, jobs)
+}
+
+func TestCheck_Paused(t *testing.T) {
+	mockRepo := mocks.NewRepository(t)
+	mockControl := mocks.NewControl(t)
+
+	stale := time.Now().Add(-time.Hour)
+	mockControl.EXPECT().Jobs(mock.Anything).Return(map[string]string{"resolver": "* * * * *"}, nil).Once()
+	mockRepo.EXPECT().FetchLatest(mock.Anything, "resolver", 1).
+		Return([]entity.JobRun{{ID: 1, Status: entity.JobRunStatusSucceeded, FinishedAt: &stale}}, nil).Once()
+	mockControl.EXPECT().IsPaused(mock.Anything, "resolver").Return(true, nil).Once()
+	mockRepo.EXPECT().FindLastSucceeded(mock.Anything, "resolver").
+		Return(&entity.JobRun{ID: 1, Status: entity.JobRunStatusSucceeded, FinishedAt: &stale}, nil).Once()
+
+	svc := NewService(mockRepo, mockControl, 15*time.Minute, nil, time.Hour)
+	jobs, isHealthy, err := svc.Check(context.Background())
+	assert.Nil(t, err)
+	assert.True(t, isHealthy)
+	assert.False(t, jobs[0].Healthy)
+	assert.True(t, jobs[0].Paused)
+}
+
+func TestTrigger(t *testing.T) {
+	mockRepo := mocks.NewRepository(t)
+	mockControl := mocks.NewControl(t)
+	ctx := context.WithValue(context.Background(), config.RequestIDKey, "req-1")
+
+	t.Run("error job not found", func(t *testing.T) {
+		mockControl.EXPECT().IsRegistered(mock.Anything, "unknown").Return(false, nil).Once()
+
+		svc := NewService(mockRepo, mockControl, 15*time.Minute, nil, time.Hour)
+		res, err := svc.Trigger(ctx, "unknown")
+		assert.Equal(t, &jobRunError{jobRunErrorJobNotFound}, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("error trigger job", func(t *testing.T) {
+		mockControl.EXPECT().IsRegistered(mock.Anything, "resolver").Return(true, nil).Once()
+		mockControl.EXPECT().Trigger(mock.Anything, "resolver", "req-1").Return(errUnexpected).Once()
+
+		svc := NewService(mockRepo, mockControl, 15*time.Minute, nil, time.Hour)
+		res, err := svc.Trigger(ctx, "resolver")
+		assert.Equal(t, errUnexpected, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("success trigger job", func(t *testing.T) {
+		mockControl.EXPECT().IsRegistered(mock.Anything, "resolver").Return(true, nil).Once()
+		mockControl.EXPECT().Trigger(mock.Anything, "resolver", "req-1").Return(nil).Once()
+
+		svc := NewService(mockRepo, mockControl, 15*time.Minute, nil, time.Hour)
+		res, err := svc.Trigger(ctx, "resolver")
+		assert.Nil(t, err)
+		assert.Equal(t, &TriggerResponse{Job: "resolver", RequestID: "req-1"}, res)
+	})
+}
+
+func TestPauseResume(t *testing.T) {
+	mockRepo := mocks.NewRepository(t)
+	mockControl := mocks.NewControl(t)
+
+	t.Run("error pause job not found", func(t *testing.T) {
+		mockControl.EXPECT().IsRegistered(mock.Anything, "unknown").Return(false, nil).Once()
+
+		svc := NewService(mockRepo, mockControl, 15*time.Minute, nil, time.Hour)
+		res, err := svc.Pause(context.Background(), "unknown")
+		assert.Equal(t, &jobRunError{jobRunErrorJobNotFound}, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("success pause job", func(t *testing.T) {
+		mockControl.EXPECT().IsRegistered(mock.Anything, "resolver").Return(true, nil).Once()
+		mockControl.EXPECT().Pause(mock.Anything, "resolver").Return(nil).Once()
+
+		svc := NewService(mockRepo, mockControl, 15*time.Minute, nil, time.Hour)
+		res, err := svc.Pause(context.Background(), "resolver")
+		assert.Nil(t, err)
+		assert.Equal(t, &PauseResponse{Job: "resolver", Paused: true}, res)
+	})
+
+	t.Run("success resume job", func(t *testing.T) {
+		mockControl.EXPECT().IsRegistered(mock.Anything, "resolver").Return(true, nil).Once()
+		mockControl.EXPECT().Resume(mock.Anything, "resolver").Return(nil).Once()
+
+		svc := NewService(mockRepo, mockControl, 15*time.Minute, nil, time.Hour)
+		res, err := svc.Resume(context.Background(), "resolver")
+		assert.Nil(t, err)
+		assert.Equal(t, &PauseResponse{Job: "resolver", Paused: false}, res)
+	})
+}
+
+func TestPrune(t *testing.T) {
+	mockRepo := mocks.NewRepository(t)
+	mockControl := mocks.NewControl(t)
+
+	t.Run("error delete job runs", func(t *testing.T) {
+		mockRepo.EXPECT().DeleteBefore(mock.Anything, mock.Anything).Return(0, errUnexpected).Once()
+
+		svc := NewService(mockRepo, mockControl, 15*time.Minute, nil, time.Hour)
+		deleted, err := svc.Prune(context.Background())
+		assert.Equal(t, fmt.Errorf("failed to delete job runs: %w", errUnexpected), err)
+		assert.Equal(t, int64(0), deleted)
+	})
+
+	t.Run("success delete job runs", func(t *testing.T) {
+		mockRepo.EXPECT().DeleteBefore(mock.Anything, mock.MatchedBy(func(before time.Time) bool {
+			return time.Since(before) >= time.Hour && time.Since(before) < time.Hour+time.Minute
+		})).Return(5, nil).Once()
+
+		svc := NewService(mockRepo, mockControl, 15*time.Minute, nil, time.Hour)
+		deleted, err := svc.Prune(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, int64(5), deleted)
+	})
+}
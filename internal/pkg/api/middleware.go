@@ -5,17 +5,25 @@ import (
 	"context"
 	"fmt"
 	"integration-go/internal/pkg/config"
+	"integration-go/internal/pkg/metrics"
 	"integration-go/internal/pkg/sanitizer"
+	"integration-go/internal/pkg/tracing"
 	"io"
 	"net"
 	"net/http"
 	"runtime/debug"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var sanitizerInstance = sanitizer.New()
@@ -141,6 +149,87 @@ func loggerHandler(filter func(w http.ResponseWriter, r *http.Request) bool) fun
 	}
 }
 
+// metricsHandler records the count and latency of the requests by route pattern, so paths
+// with IDs do not create a series each. The router sets the pattern on the request it
+// receives, so no middleware between them may copy the request.
+func metricsHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		ww := wrapResponseWriter(w)
+		next.ServeHTTP(ww, r)
+
+		// A handler that never writes the header responds 200
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		route := r.Pattern
+		if route == "" {
+			route = "unmatched"
+		}
+
+		labels := []string{r.Method, route, strconv.Itoa(status)}
+		metrics.HTTPRequests.WithLabelValues(labels...).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+	})
+}
+
+// tracingHandler continues the trace of the W3C traceparent header of the request, if any,
+// in a server span named after the route pattern, and adds the trace ID to the logs.
+func tracingHandler(filter func(w http.ResponseWriter, r *http.Request) bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if filter != nil && filter(w, r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := tracing.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			ctx, span := tracing.Tracer().Start(ctx, r.Method,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPRequestMethodKey.String(r.Method),
+					semconv.URLPath(r.URL.Path),
+					semconv.ClientAddress(r.RemoteAddr),
+					semconv.UserAgentOriginal(r.UserAgent()),
+				),
+			)
+			defer span.End()
+
+			if requestID, ok := ctx.Value(config.RequestIDKey).(string); ok {
+				span.SetAttributes(attribute.String("request_id", requestID))
+			}
+
+			if traceID := tracing.TraceID(ctx); traceID != "" {
+				ctx = log.Ctx(ctx).With().Str("trace_id", traceID).Logger().WithContext(ctx)
+			}
+
+			// The router sets the matched pattern on this request, read below
+			r = r.WithContext(ctx)
+
+			ww := wrapResponseWriter(w)
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			if r.Pattern != "" {
+				span.SetName(r.Pattern)
+				span.SetAttributes(semconv.HTTPRoute(r.Pattern))
+			}
+
+			span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+		})
+	}
+}
+
 func realIPHandler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if rip := realIP(r); rip != "" {
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"integration-go/internal/pkg/metrics"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/rooms/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+	mux.HandleFunc("GET /panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	h := chainMiddleware(mux, recoverHandler, metricsHandler, requestIDHandler)
+
+	tests := []struct {
+		name   string
+		path   string
+		route  string
+		status string
+	}{
+		{name: "route pattern instead of path", path: "/api/v1/rooms/42", route: "GET /api/v1/rooms/{id}", status: "404"},
+		{name: "implicit ok status", path: "/health", route: "GET /health", status: "200"},
+		{name: "unmatched route", path: "/unknown", route: "unmatched", status: "404"},
+		{name: "recovered panic", path: "/panic", route: "GET /panic", status: "500"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(http.MethodGet, tt.route, tt.status))
+
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, before+1, testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(http.MethodGet, tt.route, tt.status)))
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package resp
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError describes a field of the request body failing its validation rule.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func newFieldErrors(errs validator.ValidationErrors) []FieldError {
+	fieldErrs := make([]FieldError, 0, len(errs))
+	for _, err := range errs {
+		field := fieldName(err)
+		fieldErrs = append(fieldErrs, FieldError{
+			Field:   field,
+			Rule:    err.Tag(),
+			Message: fieldMessage(field, err),
+		})
+	}
+
+	return fieldErrs
+}
+
+// fieldName returns the path of the field without the name of the validated struct,
+// e.g. payload.room.id_str.
+func fieldName(err validator.FieldError) string {
+	namespace := err.Namespace()
+	if _, field, ok := strings.Cut(namespace, "."); ok {
+		return field
+	}
+
+	return namespace
+}
+
+func fieldMessage(field string, err validator.FieldError) string {
+	switch err.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", field)
+	case "email":
+		return fmt.Sprintf("%s must be a valid email address", field)
+	case "url":
+		return fmt.Sprintf("%s must be a valid URL", field)
+	case "oneof":
+		return fmt.Sprintf("%s must be one of [%s]", field, err.Param())
+	case "min", "max", "len":
+		return fmt.Sprintf("%s must %s", field, sizeMessage(err))
+	case "gt":
+		return fmt.Sprintf("%s must be greater than %s", field, err.Param())
+	case "gte":
+		return fmt.Sprintf("%s must be greater than or equal to %s", field, err.Param())
+	case "lt":
+		return fmt.Sprintf("%s must be less than %s", field, err.Param())
+	case "lte":
+		return fmt.Sprintf("%s must be less than or equal to %s", field, err.Param())
+	default:
+		return fmt.Sprintf("%s failed the %s rule", field, err.Tag())
+	}
+}
+
+// sizeMessage words min, max and len after the kind of the field: a length for strings,
+// a number of items for collections and a value for numbers.
+func sizeMessage(err validator.FieldError) string {
+	bound := map[string]string{"min": "at least", "max": "at most", "len": "exactly"}[err.Tag()]
+
+	switch err.Kind() {
+	case reflect.String:
+		return fmt.Sprintf("be %s %s characters long", bound, err.Param())
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return fmt.Sprintf("contain %s %s items", bound, err.Param())
+	default:
+		return fmt.Sprintf("be %s %s", bound, err.Param())
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"math"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -15,6 +16,9 @@ type Meta struct {
 	Page      int `json:"page"`
 	PageTotal int `json:"page_total"`
 	Total     int `json:"total"`
+	// NextCursor continues after the last item of the page, for endpoints supporting
+	// cursor pagination. It is empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 type DataPaginate struct {
@@ -25,6 +29,8 @@ type DataPaginate struct {
 type HTTPError struct {
 	Message   string `json:"message"`
 	RequestID string `json:"request_id"`
+	// Errors lists the failing fields of an invalid request body.
+	Errors []FieldError `json:"errors,omitempty"`
 }
 
 type Empty struct{}
@@ -37,14 +43,20 @@ func WriteJSON(w http.ResponseWriter, code int, data any) {
 }
 
 func WriteJSONWithPaginate(w http.ResponseWriter, code int, data any, total int, page int, limit int) {
+	WriteJSONWithCursor(w, code, data, total, page, limit, "")
+}
+
+// WriteJSONWithCursor writes the paginated envelope with the cursor of the next page.
+func WriteJSONWithCursor(w http.ResponseWriter, code int, data any, total int, page int, limit int, nextCursor string) {
 	w.Header().Add("Content-Type", "application/json")
 	w.WriteHeader(code)
 
 	totalPages := int(math.Ceil(float64(total) / float64(limit)))
 	meta := Meta{
-		Page:      page,
-		PageTotal: totalPages,
-		Total:     total,
+		Page:       page,
+		PageTotal:  totalPages,
+		Total:      total,
+		NextCursor: nextCursor,
 	}
 
 	jsonData, _ := json.Marshal(DataPaginate{
@@ -55,20 +67,33 @@ func WriteJSONWithPaginate(w http.ResponseWriter, code int, data any, total int,
 	w.Write(jsonData)
 }
 
+// WriteJSONFromError writes the error response of err. Errors with an HTTPStatusCode set
+// the status, and their message is sent only when they implement HTTPMessage, since the
+// text of other errors, e.g. the upstream ones, is meant for the logs. They get the
+// generic text of the status instead.
 func WriteJSONFromError(w http.ResponseWriter, err error) {
 	code := http.StatusInternalServerError
 	msg := "Something went wrong"
 
+	var fieldErrs []FieldError
+
 	var httpErr interface{ HTTPStatusCode() int }
 	var validationErrs validator.ValidationErrors
 
 	switch {
 	case errors.As(err, &httpErr):
 		code = httpErr.HTTPStatusCode()
-		msg = err.Error()
+		msg = http.StatusText(code)
+		if public, ok := httpErr.(interface{ HTTPMessage() string }); ok {
+			msg = public.HTTPMessage()
+		}
+	case errors.As(err, &validationErrs):
+		code = http.StatusBadRequest
+		msg = "Invalid request"
+		fieldErrs = newFieldErrors(validationErrs)
 	case errors.As(err, new(*json.UnmarshalTypeError)),
 		errors.As(err, new(*json.SyntaxError)),
-		errors.As(err, &validationErrs),
+		errors.As(err, new(*time.ParseError)),
 		errors.Is(err, io.EOF),
 		errors.Is(err, io.ErrUnexpectedEOF),
 		errors.Is(err, strconv.ErrSyntax),
@@ -80,6 +105,7 @@ func WriteJSONFromError(w http.ResponseWriter, err error) {
 	errResp := HTTPError{
 		Message:   msg,
 		RequestID: w.Header().Get("X-Request-Id"),
+		Errors:    fieldErrs,
 	}
 
 	resp, _ := json.Marshal(errResp)
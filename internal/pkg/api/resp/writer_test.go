@@ -3,11 +3,14 @@ package resp
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"integration-go/internal/pkg/validate"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -27,6 +30,15 @@ func (e MockHTTPError) HTTPStatusCode() int {
 	return e.code
 }
 
+// MockPublicHTTPError is a MockHTTPError whose message is sent to the client
+type MockPublicHTTPError struct {
+	MockHTTPError
+}
+
+func (e MockPublicHTTPError) HTTPMessage() string {
+	return e.message
+}
+
 func TestWriteJSON(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -150,6 +162,15 @@ func TestWriteJSONWithPaginate(t *testing.T) {
 	}
 }
 
+func TestWriteJSONWithCursor(t *testing.T) {
+	recorder := httptest.NewRecorder()
+
+	WriteJSONWithCursor(recorder, http.StatusOK, []string{"item1", "item2"}, 21, 0, 2, "eyJpZCI6Mn0")
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"data":["item1","item2"],"meta":{"page":0,"page_total":11,"total":21,"next_cursor":"eyJpZCI6Mn0"}}`, recorder.Body.String())
+}
+
 func TestWriteJSONFromError(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -161,12 +182,28 @@ func TestWriteJSONFromError(t *testing.T) {
 	}{
 		{
 			name:            "SUCCESS-HTTPError_CorrectStatusAndMessage",
-			err:             MockHTTPError{message: "validation failed", code: http.StatusBadRequest},
+			err:             MockPublicHTTPError{MockHTTPError{message: "validation failed", code: http.StatusBadRequest}},
 			requestID:       "req-123",
 			expectedCode:    http.StatusBadRequest,
 			expectedMessage: "validation failed",
 			expectedReqID:   "req-123",
 		},
+		{
+			name:            "SUCCESS-WrappedHTTPError_MessageOfTheError",
+			err:             fmt.Errorf("failed to get room: %w", MockPublicHTTPError{MockHTTPError{message: "Room not found", code: http.StatusNotFound}}),
+			requestID:       "req-124",
+			expectedCode:    http.StatusNotFound,
+			expectedMessage: "Room not found",
+			expectedReqID:   "req-124",
+		},
+		{
+			name:            "SUCCESS-HTTPErrorWithoutMessage_StatusText",
+			err:             fmt.Errorf("failed to call upstream: %w", MockHTTPError{message: "GET https://upstream/secret: 502 body", code: http.StatusBadGateway}),
+			requestID:       "req-125",
+			expectedCode:    http.StatusBadGateway,
+			expectedMessage: "Bad Gateway",
+			expectedReqID:   "req-125",
+		},
 		{
 			name:            "SUCCESS-EOFError_BadRequest",
 			err:             io.EOF,
@@ -199,6 +236,14 @@ func TestWriteJSONFromError(t *testing.T) {
 			expectedMessage: "value out of range",
 			expectedReqID:   "req-104",
 		},
+		{
+			name:            "SUCCESS-TimeParseError_BadRequest",
+			err:             &time.ParseError{Layout: time.RFC3339, Value: "yesterday", LayoutElem: "2006", ValueElem: "yesterday"},
+			requestID:       "req-105",
+			expectedCode:    http.StatusBadRequest,
+			expectedMessage: `parsing time "yesterday" as "2006-01-02T15:04:05Z07:00": cannot parse "yesterday" as "2006"`,
+			expectedReqID:   "req-105",
+		},
 		{
 			name:            "SUCCESS-GenericError_InternalServerError",
 			err:             errors.New("database connection failed"),
@@ -239,6 +284,55 @@ func TestWriteJSONFromError(t *testing.T) {
 	}
 }
 
+func TestWriteJSONFromError_ValidationErrors(t *testing.T) {
+	type room struct {
+		ID string `json:"id_str" validate:"required"`
+	}
+
+	type request struct {
+		Room    room     `json:"room"`
+		Email   string   `json:"email" validate:"email"`
+		Name    string   `json:"name" validate:"min=3"`
+		IDs     []int64  `json:"ids" validate:"max=2"`
+		Status  string   `json:"status" validate:"oneof=failed retried"`
+		Retries int      `json:"retries" validate:"gte=0"`
+		Tags    []string `json:"tags" validate:"unique"`
+	}
+
+	req := request{
+		Email:   "john",
+		Name:    "jo",
+		IDs:     []int64{1, 2, 3},
+		Status:  "done",
+		Retries: -1,
+		Tags:    []string{"a", "a"},
+	}
+
+	err := validate.Struct(&req)
+	require.Error(t, err)
+
+	recorder := httptest.NewRecorder()
+	recorder.Header().Set("X-Request-Id", "req-400")
+	WriteJSONFromError(recorder, fmt.Errorf("failed to decode request: %w", err))
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	var response HTTPError
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+
+	assert.Equal(t, "Invalid request", response.Message)
+	assert.Equal(t, "req-400", response.RequestID)
+	assert.Equal(t, []FieldError{
+		{Field: "room.id_str", Rule: "required", Message: "room.id_str is required"},
+		{Field: "email", Rule: "email", Message: "email must be a valid email address"},
+		{Field: "name", Rule: "min", Message: "name must be at least 3 characters long"},
+		{Field: "ids", Rule: "max", Message: "ids must contain at most 2 items"},
+		{Field: "status", Rule: "oneof", Message: "status must be one of [failed retried]"},
+		{Field: "retries", Rule: "gte", Message: "retries must be greater than or equal to 0"},
+		{Field: "tags", Rule: "unique", Message: "tags failed the unique rule"},
+	}, response.Errors)
+}
+
 func TestMeta(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -310,6 +404,15 @@ func TestHTTPError(t *testing.T) {
 			httpErr:  HTTPError{Message: "validation failed", RequestID: "req-123"},
 			expected: `{"message":"validation failed","request_id":"req-123"}`,
 		},
+		{
+			name: "SUCCESS-HTTPErrorWithFieldErrors_CorrectSerialization",
+			httpErr: HTTPError{
+				Message:   "Invalid request",
+				RequestID: "req-123",
+				Errors:    []FieldError{{Field: "room_id", Rule: "required", Message: "room_id is required"}},
+			},
+			expected: `{"message":"Invalid request","request_id":"req-123","errors":[{"field":"room_id","rule":"required","message":"room_id is required"}]}`,
+		},
 		{
 			name:     "SUCCESS-HTTPErrorEmptyRequestID_CorrectSerialization",
 			httpErr:  HTTPError{Message: "error occurred", RequestID: ""},
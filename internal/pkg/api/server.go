@@ -4,14 +4,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"integration-go/internal/allocation"
+	"integration-go/internal/deadletter"
 	"integration-go/internal/health"
-	"integration-go/internal/room"
+	"integration-go/internal/jobrun"
 	"integration-go/internal/pkg/auth"
 	"integration-go/internal/pkg/client"
 	"integration-go/internal/pkg/config"
+	"integration-go/internal/pkg/cron"
+	"integration-go/internal/pkg/idempotency"
+	"integration-go/internal/pkg/metrics"
 	"integration-go/internal/pkg/postgres"
 	"integration-go/internal/pkg/qismo"
+	"integration-go/internal/pkg/queue"
 	"integration-go/internal/pkg/redis"
+	"integration-go/internal/pkg/tracing"
+	"integration-go/internal/room"
 	"net/http"
 	"os"
 	"os/signal"
@@ -22,42 +30,99 @@ import (
 )
 
 func NewServer() *Server {
-	cfg := config.Load()
+	cfg := config.LoadAPI()
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, "api")
+	if err != nil {
+		log.Fatal().Msgf("unable to set up tracing: %s", err.Error())
+	}
 
 	db := postgres.NewGORM(cfg.Database)
-	postgres.Migrate(db)
+	postgres.CheckSchema(db)
 
 	rdb := redis.New(cfg.Redis.URL)
 
-	client := client.New()
+	client := client.NewFromConfig(cfg.Client)
 	// client.DebugMode = true
 
-	qismo := qismo.New(client, cfg.Qiscus.Omnichannel.URL, cfg.Qiscus.AppID, cfg.Qiscus.SecretKey)
+	omni := qismo.New(client, cfg.Qiscus.Omnichannel.URL, cfg.Qiscus.SDKURL, cfg.Qiscus.AppID, cfg.Qiscus.SecretKey)
+	omni.SetRateLimit(cfg.Qiscus.RateLimit, cfg.Qiscus.RateBurst)
+
+	queueRepo := queue.NewRepository(db, cfg.Worker.MaxAttempts)
 
 	// Room
 	roomRepo := room.NewRepository(db)
-	roomSvc := room.NewService(roomRepo, qismo)
+	roomSvc := room.NewService(roomRepo, omni, queueRepo)
 	roomHandler := room.NewHttpHandler(roomSvc)
 
+	// Allocation
+	allocationRepo := allocation.NewRepository(db, rdb)
+	allocationStrategy, err := allocation.NewStrategy(cfg.Allocation.Strategy, cfg.Allocation.Divisions, allocationRepo, omni)
+	if err != nil {
+		log.Fatal().Msgf("unable to create allocation strategy: %s", err.Error())
+	}
+
+	allocationSvc := allocation.NewService(allocationRepo, roomRepo, omni, queueRepo, allocationStrategy, cfg.Allocation.MaxCustomers)
+	allocationHandler := allocation.NewHttpHandler(allocationSvc)
+
 	// Auth
 	authMidd := auth.NewMiddleware(cfg.App.SecretKey)
+	webhookMidd := auth.NewWebhookMiddleware(cfg.Webhook.Secrets, cfg.Webhook.Tolerance)
+
+	// Idempotency
+	idempotencyMidd := idempotency.NewMiddleware(rdb, cfg.Webhook.DedupTTL)
+
+	// Dead-letter
+	deadLetterRepo := deadletter.NewRepository(db, cfg.Worker.MaxAttempts)
+	deadLetterSvc := deadletter.NewService(deadLetterRepo)
+	deadLetterHandler := deadletter.NewHttpHandler(deadLetterSvc)
+
+	// Job runs
+	jobRunRepo := jobrun.NewRepository(db)
+	jobRunSvc := jobrun.NewService(jobRunRepo, cron.NewControl(rdb), cfg.Cron.HealthWindow, cfg.Cron.HealthWindows, cfg.Cron.RunRetention)
+	jobRunHandler := jobrun.NewHttpHandler(jobRunSvc)
 
 	// Health
 	healthRepo := health.NewRepository(db, rdb)
 	healthSvc := health.NewService(healthRepo)
 	healthHandler := health.NewHttpHandler(healthSvc)
 
+	// Omnichannel webhooks
+	webhookRouter := qismo.NewWebhookRouter()
+	qismo.HandleWebhook(webhookRouter, qismo.WebhookTypeNewSession, roomSvc.EnqueueCreateRoom)
+	qismo.HandleWebhook(webhookRouter, qismo.WebhookTypeMarkAsResolved, roomSvc.MarkAsResolved)
+	qismo.HandleWebhook(webhookRouter, qismo.WebhookTypeAgentAllocation, allocationSvc.EnqueueAllocateAgent)
+
 	r := http.NewServeMux()
 	r.Handle("GET /", http.HandlerFunc(rootHandler))
 	r.Handle("GET /health", http.HandlerFunc(healthHandler.Check))
-	r.Handle("POST /wh/qiscus/omnichannel/new-session", http.HandlerFunc(roomHandler.WebhookQismoNewSession))
+	r.Handle("GET /health/jobs", http.HandlerFunc(jobRunHandler.Check))
+	r.Handle("GET /metrics", metrics.Handler())
+	r.Handle("POST /wh/qiscus/omnichannel", webhookMidd.Verify(idempotencyMidd.Deduplicate(webhookRouter)))
+	r.Handle("POST /wh/qiscus/omnichannel/new-session", webhookMidd.Verify(idempotencyMidd.Deduplicate(http.HandlerFunc(roomHandler.WebhookQismoNewSession))))
+	r.Handle("POST /wh/qiscus/omnichannel/agent-allocation", webhookMidd.Verify(idempotencyMidd.Deduplicate(http.HandlerFunc(allocationHandler.WebhookQismoAgentAllocation))))
+	r.Handle("GET /api/v1/rooms", authMidd.StaticToken(http.HandlerFunc(roomHandler.GetRooms)))
 	r.Handle("GET /api/v1/rooms/{id}", authMidd.StaticToken(http.HandlerFunc(roomHandler.GetRoomByID)))
-
-	return &Server{router: r}
+	r.Handle("GET /api/v1/rooms/{id}/events", authMidd.StaticToken(http.HandlerFunc(roomHandler.GetRoomEvents)))
+	r.Handle("GET /api/v1/failed-events", authMidd.StaticToken(http.HandlerFunc(deadLetterHandler.GetFailedEvents)))
+	r.Handle("GET /api/v1/failed-events/{id}", authMidd.StaticToken(http.HandlerFunc(deadLetterHandler.GetFailedEventByID)))
+	r.Handle("POST /api/v1/failed-events/{id}/retry", authMidd.StaticToken(http.HandlerFunc(deadLetterHandler.Retry)))
+	r.Handle("POST /api/v1/failed-events/retry", authMidd.StaticToken(http.HandlerFunc(deadLetterHandler.BulkRetry)))
+	r.Handle("GET /api/v1/jobs", authMidd.StaticToken(http.HandlerFunc(jobRunHandler.GetJobs)))
+	r.Handle("POST /api/v1/jobs/{name}/trigger", authMidd.StaticToken(http.HandlerFunc(jobRunHandler.Trigger)))
+	r.Handle("POST /api/v1/jobs/{name}/pause", authMidd.StaticToken(http.HandlerFunc(jobRunHandler.Pause)))
+	r.Handle("POST /api/v1/jobs/{name}/resume", authMidd.StaticToken(http.HandlerFunc(jobRunHandler.Resume)))
+
+	return &Server{
+		router:          r,
+		shutdownTracing: shutdownTracing,
+	}
 }
 
 type Server struct {
 	router *http.ServeMux
+	// shutdownTracing flushes the pending spans.
+	shutdownTracing func(context.Context) error
 }
 
 // Run method of the Server struct runs the HTTP server on the specified port. It initializes
@@ -65,10 +130,19 @@ type Server struct {
 func (s *Server) Run(port int) {
 	addr := fmt.Sprintf(":%d", port)
 
+	// Probes and scrapes are neither logged nor traced
+	skip := func(w http.ResponseWriter, r *http.Request) bool {
+		return r.URL.Path == "/health" || r.URL.Path == "/metrics"
+	}
+
 	h := chainMiddleware(
 		s.router,
+		// Right around the router, since middlewares replacing the request
+		// hide the route pattern from the outer ones
 		recoverHandler,
-		loggerHandler(func(w http.ResponseWriter, r *http.Request) bool { return r.URL.Path == "/health" }),
+		metricsHandler,
+		loggerHandler(skip),
+		tracingHandler(skip),
 		realIPHandler,
 		requestIDHandler,
 		// corsHandler,
@@ -96,6 +170,10 @@ func (s *Server) Run(port int) {
 		if err := httpSrv.Shutdown(ctx); err != nil {
 			log.Fatal().Err(err).Msg("could not gracefully shutdown the server")
 		}
+
+		if err := s.shutdownTracing(ctx); err != nil {
+			log.Error().Msgf("failed to flush traces: %s", err.Error())
+		}
 		close(done)
 	}()
 
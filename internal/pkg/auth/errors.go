@@ -8,21 +8,44 @@ type authError struct {
 
 const (
 	authErrorUnauthorized = iota
+	authErrorInvalidSignature
+	authErrorExpiredTimestamp
+	authErrorMissingTimestamp
+	authErrorInvalidTimestamp
+	authErrorBodyTooLarge
 )
 
 func (e *authError) Error() string {
 	switch e.code {
 	case authErrorUnauthorized:
 		return "Unauthorized"
+	case authErrorInvalidSignature:
+		return "Invalid webhook signature"
+	case authErrorExpiredTimestamp:
+		return "Webhook timestamp is expired"
+	case authErrorMissingTimestamp:
+		return "Webhook timestamp is missing"
+	case authErrorInvalidTimestamp:
+		return "Webhook timestamp is invalid"
+	case authErrorBodyTooLarge:
+		return "Webhook body is too large"
 	default:
 		return "Unknown error code"
 	}
 }
 
+// HTTPMessage is the message sent to the client, which is the message of the error.
+func (e *authError) HTTPMessage() string {
+	return e.Error()
+}
+
 func (e *authError) HTTPStatusCode() int {
 	switch e.code {
-	case authErrorUnauthorized:
+	case authErrorUnauthorized, authErrorInvalidSignature, authErrorExpiredTimestamp,
+		authErrorMissingTimestamp, authErrorInvalidTimestamp:
 		return http.StatusUnauthorized
+	case authErrorBodyTooLarge:
+		return http.StatusRequestEntityTooLarge
 	default:
 		return http.StatusInternalServerError
 	}
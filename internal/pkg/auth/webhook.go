@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"integration-go/internal/pkg/api/resp"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	WebhookSignatureHeader = "X-Qiscus-Signature"
+	WebhookTimestampHeader = "X-Qiscus-Timestamp"
+	WebhookSecretHeader    = "X-Qiscus-Webhook-Secret"
+
+	// MaxWebhookBodySize bounds the body read to verify a webhook, well above the size of
+	// the Qiscus payloads.
+	MaxWebhookBodySize = 1 << 20
+)
+
+type webhookMiddleware struct {
+	secrets   [][]byte
+	tolerance time.Duration
+	now       func() time.Time
+}
+
+// NewWebhookMiddleware creates a middleware that authenticates incoming webhooks
+// against any of the given secrets. Multiple secrets are accepted at the same time
+// so a secret can be rotated without rejecting deliveries signed with the old one.
+func NewWebhookMiddleware(secrets []string, tolerance time.Duration) *webhookMiddleware {
+	keys := make([][]byte, 0, len(secrets))
+	for _, secret := range secrets {
+		secret = strings.TrimSpace(secret)
+		if secret == "" {
+			continue
+		}
+
+		keys = append(keys, []byte(secret))
+	}
+
+	return &webhookMiddleware{
+		secrets:   keys,
+		tolerance: tolerance,
+		now:       time.Now,
+	}
+}
+
+// Verify accepts a request whose X-Qiscus-Timestamp is a unix timestamp within the
+// tolerance, when one of these checks passes:
+//   - X-Qiscus-Signature holds hex(HMAC-SHA256(secret, timestamp + "." + body)), optionally
+//     prefixed with "sha256=".
+//   - X-Qiscus-Webhook-Secret equals one of the secrets, for senders that can only set static headers.
+//
+// Bodies larger than MaxWebhookBodySize are rejected before any check.
+func (m *webhookMiddleware) Verify(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body []byte
+		if r.Body != nil {
+			var err error
+			body, err = io.ReadAll(http.MaxBytesReader(w, r.Body, MaxWebhookBodySize))
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					err = &authError{authErrorBodyTooLarge}
+				}
+
+				resp.WriteJSONFromError(w, err)
+				return
+			}
+
+			// Restore the io.ReadCloser so the next handler can decode the payload
+			r.Body = io.NopCloser(bytes.NewBuffer(body))
+		}
+
+		signature := r.Header.Get(WebhookSignatureHeader)
+		secret := r.Header.Get(WebhookSecretHeader)
+		if signature == "" && secret == "" {
+			resp.WriteJSONFromError(w, &authError{authErrorUnauthorized})
+			return
+		}
+
+		// Both checks require a recent timestamp, so a captured delivery is rejected once the
+		// tolerance has passed, and is deduplicated until then
+		timestamp := r.Header.Get(WebhookTimestampHeader)
+		if timestamp == "" {
+			resp.WriteJSONFromError(w, &authError{authErrorMissingTimestamp})
+			return
+		}
+
+		unix, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			resp.WriteJSONFromError(w, &authError{authErrorInvalidTimestamp})
+			return
+		}
+
+		if !m.validTimestamp(unix) {
+			resp.WriteJSONFromError(w, &authError{authErrorExpiredTimestamp})
+			return
+		}
+
+		if signature != "" {
+			if !m.validSignature(signature, timestamp, body) {
+				resp.WriteJSONFromError(w, &authError{authErrorInvalidSignature})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !m.validSecret(secret) {
+			resp.WriteJSONFromError(w, &authError{authErrorUnauthorized})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (m *webhookMiddleware) validTimestamp(unix int64) bool {
+	diff := m.now().Sub(time.Unix(unix, 0))
+	if diff < 0 {
+		diff = -diff
+	}
+
+	return diff <= m.tolerance
+}
+
+func (m *webhookMiddleware) validSignature(signature, timestamp string, body []byte) bool {
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return false
+	}
+
+	for _, secret := range m.secrets {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(timestamp))
+		mac.Write([]byte("."))
+		mac.Write(body)
+
+		if hmac.Equal(got, mac.Sum(nil)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *webhookMiddleware) validSecret(secret string) bool {
+	for _, s := range m.secrets {
+		if subtle.ConstantTimeCompare([]byte(secret), s) == 1 {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,193 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sign(secret, timestamp, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestWebhookMiddleware_Verify(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ts := strconv.FormatInt(now.Unix(), 10)
+	staleTs := strconv.FormatInt(now.Add(-10*time.Minute).Unix(), 10)
+	body := `{"webhook_type":"new_session"}`
+
+	tests := []struct {
+		name            string
+		headers         map[string]string
+		expectedCode    int
+		expectedMessage string
+	}{
+		{
+			name: "valid signature with current secret",
+			headers: map[string]string{
+				WebhookSignatureHeader: sign("secret-new", ts, body),
+				WebhookTimestampHeader: ts,
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "valid signature with rotated secret and prefix",
+			headers: map[string]string{
+				WebhookSignatureHeader: "sha256=" + sign("secret-old", ts, body),
+				WebhookTimestampHeader: ts,
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "signature with unknown secret",
+			headers: map[string]string{
+				WebhookSignatureHeader: sign("another-secret", ts, body),
+				WebhookTimestampHeader: ts,
+			},
+			expectedCode:    http.StatusUnauthorized,
+			expectedMessage: "Invalid webhook signature",
+		},
+		{
+			name: "signature over different timestamp",
+			headers: map[string]string{
+				WebhookSignatureHeader: sign("secret-new", staleTs, body),
+				WebhookTimestampHeader: ts,
+			},
+			expectedCode:    http.StatusUnauthorized,
+			expectedMessage: "Invalid webhook signature",
+		},
+		{
+			name: "stale timestamp",
+			headers: map[string]string{
+				WebhookSignatureHeader: sign("secret-new", staleTs, body),
+				WebhookTimestampHeader: staleTs,
+			},
+			expectedCode:    http.StatusUnauthorized,
+			expectedMessage: "Webhook timestamp is expired",
+		},
+		{
+			name: "missing timestamp",
+			headers: map[string]string{
+				WebhookSignatureHeader: sign("secret-new", ts, body),
+			},
+			expectedCode:    http.StatusUnauthorized,
+			expectedMessage: "Webhook timestamp is missing",
+		},
+		{
+			name: "invalid timestamp",
+			headers: map[string]string{
+				WebhookSignatureHeader: sign("secret-new", "yesterday", body),
+				WebhookTimestampHeader: "yesterday",
+			},
+			expectedCode:    http.StatusUnauthorized,
+			expectedMessage: "Webhook timestamp is invalid",
+		},
+		{
+			name: "valid shared secret",
+			headers: map[string]string{
+				WebhookSecretHeader:    "secret-old",
+				WebhookTimestampHeader: ts,
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "shared secret without timestamp",
+			headers: map[string]string{
+				WebhookSecretHeader: "secret-old",
+			},
+			expectedCode:    http.StatusUnauthorized,
+			expectedMessage: "Webhook timestamp is missing",
+		},
+		{
+			name: "shared secret with stale timestamp",
+			headers: map[string]string{
+				WebhookSecretHeader:    "secret-old",
+				WebhookTimestampHeader: staleTs,
+			},
+			expectedCode:    http.StatusUnauthorized,
+			expectedMessage: "Webhook timestamp is expired",
+		},
+		{
+			name: "invalid shared secret",
+			headers: map[string]string{
+				WebhookSecretHeader:    "wrong",
+				WebhookTimestampHeader: ts,
+			},
+			expectedCode:    http.StatusUnauthorized,
+			expectedMessage: "Unauthorized",
+		},
+		{
+			name:            "no credentials",
+			headers:         map[string]string{},
+			expectedCode:    http.StatusUnauthorized,
+			expectedMessage: "Unauthorized",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewWebhookMiddleware([]string{"secret-new", " secret-old ", ""}, 5*time.Minute)
+			m.now = func() time.Time { return now }
+
+			var gotBody string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				gotBody = string(b)
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/wh", strings.NewReader(body))
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			recorder := httptest.NewRecorder()
+			m.Verify(next).ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.expectedCode, recorder.Code)
+			if tt.expectedCode == http.StatusOK {
+				assert.Equal(t, body, gotBody)
+				return
+			}
+
+			var response map[string]string
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedMessage, response["message"])
+		})
+	}
+}
+
+func TestWebhookMiddleware_Verify_BodyTooLarge(t *testing.T) {
+	m := NewWebhookMiddleware([]string{"secret"}, 5*time.Minute)
+
+	called := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/wh", strings.NewReader(strings.Repeat("a", MaxWebhookBodySize+1)))
+	req.Header.Set(WebhookSecretHeader, "secret")
+
+	recorder := httptest.NewRecorder()
+	m.Verify(next).ServeHTTP(recorder, req)
+
+	assert.False(t, called)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+
+	var response map[string]string
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, "Webhook body is too large", response["message"])
+}
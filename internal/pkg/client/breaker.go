@@ -0,0 +1,131 @@
+package client
+
+import (
+	"integration-go/internal/pkg/metrics"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerHalfOpen
+	breakerOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerHalfOpen:
+		return "half-open"
+	case breakerOpen:
+		return "open"
+	default:
+		return "closed"
+	}
+}
+
+// outcome is what an attempt tells about the health of its host. An attempt abandoned by
+// its caller tells nothing.
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota
+	outcomeFailure
+	outcomeAbandoned
+)
+
+// breaker fails the calls to a host fast once threshold consecutive attempts failed. After
+// the cooldown it lets a single probe through, half-open, closing again if the probe
+// succeeds and reopening otherwise.
+type breaker struct {
+	host      string
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newBreaker(host string, policy Policy) *breaker {
+	return &breaker{
+		host:      host,
+		threshold: policy.BreakerThreshold,
+		cooldown:  policy.BreakerCooldown,
+		now:       time.Now,
+	}
+}
+
+// allow tells whether an attempt may be sent. Every allowed attempt must be followed by
+// record.
+func (b *breaker) allow() bool {
+	if b.threshold <= 0 {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+
+		b.setState(breakerHalfOpen)
+	case breakerHalfOpen:
+		if b.probing {
+			return false
+		}
+	default:
+		return true
+	}
+
+	b.probing = true
+	return true
+}
+
+func (b *breaker) record(o outcome) {
+	if b.threshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+
+	switch o {
+	case outcomeSuccess:
+		b.failures = 0
+		b.setState(breakerClosed)
+	case outcomeFailure:
+		b.failures++
+		if b.state == breakerHalfOpen || b.failures >= b.threshold {
+			b.openedAt = b.now()
+			b.setState(breakerOpen)
+		}
+	}
+}
+
+func (b *breaker) setState(state breakerState) {
+	if b.state == state {
+		return
+	}
+
+	log.Warn().
+		Str("host", b.host).
+		Str("from", b.state.String()).
+		Str("to", state.String()).
+		Int("failures", b.failures).
+		Msg("circuit breaker state changed")
+
+	b.state = state
+	metrics.ClientBreakerState.WithLabelValues(b.host).Set(float64(state))
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"integration-go/internal/pkg/metrics"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreaker(t *testing.T) {
+	now := time.Now()
+	b := newBreaker("breaker.test", Policy{BreakerThreshold: 2, BreakerCooldown: time.Minute})
+	b.now = func() time.Time { return now }
+
+	state := func() float64 {
+		return testutil.ToFloat64(metrics.ClientBreakerState.WithLabelValues("breaker.test"))
+	}
+
+	// A success resets the consecutive failures
+	assert.True(t, b.allow())
+	b.record(outcomeFailure)
+	assert.True(t, b.allow())
+	b.record(outcomeSuccess)
+	assert.True(t, b.allow())
+	b.record(outcomeFailure)
+	assert.Equal(t, breakerClosed, b.state)
+
+	// Abandoned attempts are not failures
+	assert.True(t, b.allow())
+	b.record(outcomeAbandoned)
+	assert.Equal(t, breakerClosed, b.state)
+
+	assert.True(t, b.allow())
+	b.record(outcomeFailure)
+	assert.Equal(t, breakerOpen, b.state)
+	assert.Equal(t, float64(2), state())
+	assert.False(t, b.allow())
+
+	// After the cooldown a single probe is let through
+	now = now.Add(time.Minute)
+	assert.True(t, b.allow())
+	assert.Equal(t, float64(1), state())
+	assert.False(t, b.allow())
+
+	// A failed probe reopens it for another cooldown
+	b.record(outcomeFailure)
+	assert.Equal(t, breakerOpen, b.state)
+	assert.False(t, b.allow())
+
+	now = now.Add(time.Minute)
+	assert.True(t, b.allow())
+	b.record(outcomeSuccess)
+	assert.Equal(t, breakerClosed, b.state)
+	assert.Equal(t, float64(0), state())
+	assert.True(t, b.allow())
+}
+
+func TestBreaker_Disabled(t *testing.T) {
+	b := newBreaker("disabled.test", Policy{BreakerThreshold: 0})
+
+	for range 10 {
+		assert.True(t, b.allow())
+		b.record(outcomeFailure)
+	}
+
+	assert.Equal(t, breakerClosed, b.state)
+}
@@ -4,15 +4,24 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"integration-go/internal/pkg/config"
+	"integration-go/internal/pkg/metrics"
 	"integration-go/internal/pkg/sanitizer"
+	"integration-go/internal/pkg/tracing"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/hashicorp/go-retryablehttp"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var sanitizerInstance = sanitizer.New()
@@ -20,67 +29,158 @@ var sanitizerInstance = sanitizer.New()
 type Client struct {
 	HTTPClient *http.Client
 	DebugMode  bool
+	// Policy applies to the hosts without an entry in HostPolicies, keyed by host name
+	// without port. Both must be set before the first call.
+	Policy       Policy
+	HostPolicies map[string]Policy
+
+	mu       sync.Mutex
+	breakers map[string]*breaker
 }
 
 var (
 	defaultDebugMode  = false
-	defaultHTTPClient = newHTTPClient()
+	defaultHTTPClient = &http.Client{}
 )
 
-func newHTTPClient() *http.Client {
-	retryClient := retryablehttp.NewClient()
-	retryClient.RetryMax = 3
-	retryClient.Logger = nil
-	retryClient.HTTPClient.Timeout = 20 * time.Second
-	retryClient.ErrorHandler = retryablehttp.PassthroughErrorHandler
-	return retryClient.StandardClient()
+// observe records the outbound call, with an error status when no response was received
+// and a breaker_open status when it was not sent.
+func observe(req *http.Request, status string, latency time.Duration) {
+	metrics.ClientRequests.WithLabelValues(req.URL.Host, req.Method, status).Inc()
+	metrics.ClientRequestDuration.WithLabelValues(req.URL.Host, req.Method, status).Observe(latency.Seconds())
 }
 
+// New returns a client following DefaultPolicy for every host.
 func New() *Client {
 	return &Client{
 		HTTPClient: defaultHTTPClient,
 		DebugMode:  defaultDebugMode,
+		Policy:     DefaultPolicy(),
+	}
+}
+
+// NewFromConfig returns a client following the policy of cfg, with its per host overrides.
+func NewFromConfig(cfg config.Client) *Client {
+	c := New()
+	c.Policy, c.HostPolicies = policiesFromConfig(cfg)
+	return c
+}
+
+func (c *Client) policy(hostname string) Policy {
+	if p, ok := c.HostPolicies[hostname]; ok {
+		return p
+	}
+
+	return c.Policy
+}
+
+// breaker returns the breaker of host, created with the policy of its first call.
+func (c *Client) breaker(host string, policy Policy) *breaker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.breakers == nil {
+		c.breakers = make(map[string]*breaker)
+	}
+
+	b, ok := c.breakers[host]
+	if !ok {
+		b = newBreaker(host, policy)
+		c.breakers[host] = b
 	}
+
+	return b
 }
 
-func (c *Client) Call(ctx context.Context, method, url string, body io.Reader, headers map[string]string, response any) error {
-	req, err := http.NewRequestWithContext(ctx, strings.ToUpper(method), url, body)
+// Call sends the request in a client span, forwarding the trace context and the request ID
+// of ctx in the traceparent and X-Request-Id headers. Failed attempts are retried as the
+// policy of the host allows, and the call fails fast with ErrBreakerOpen while the host is
+// failing.
+func (c *Client) Call(ctx context.Context, method, url string, body io.Reader, headers map[string]string, response any) (err error) {
+	method = strings.ToUpper(method)
+
+	ctx, span := tracing.Tracer().Start(ctx, method, trace.WithSpanKind(trace.SpanKindClient))
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err == nil && req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		err = fmt.Errorf("unsupported protocol scheme %q", req.URL.Scheme)
+	}
+
 	if err != nil {
 		return &Error{
+			Kind:     ErrorKindRequest,
 			Message:  fmt.Sprintf("unable to create new request: %s", err.Error()),
 			RawError: err,
 		}
 	}
 
+	span.SetName(fmt.Sprintf("%s %s", method, req.URL.Host))
+	span.SetAttributes(
+		semconv.HTTPRequestMethodKey.String(method),
+		semconv.ServerAddress(req.URL.Hostname()),
+		semconv.URLFull(req.URL.Redacted()),
+	)
+
 	var reqBody []byte
-	if req.Body != nil {
-		reqBody, _ = io.ReadAll(req.Body)
-		req.Body = io.NopCloser(bytes.NewBuffer(reqBody))
+	if body != nil {
+		if reqBody, err = io.ReadAll(body); err != nil {
+			return &Error{
+				Kind:     ErrorKindRequest,
+				Message:  fmt.Sprintf("unable to read request body: %s", err.Error()),
+				RawError: err,
+			}
+		}
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 
+	if requestID, ok := ctx.Value(config.RequestIDKey).(string); ok && requestID != "" {
+		req.Header.Set("X-Request-Id", requestID)
+	}
+
 	for key, value := range headers {
 		req.Header.Set(key, value)
 	}
 
+	tracing.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	policy := c.policy(req.URL.Hostname())
 	start := time.Now()
 
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
+	resp, responseBody, err := c.do(ctx, req, reqBody, policy, c.breaker(req.URL.Host, policy))
+	latency := time.Since(start)
+
+	if errors.Is(err, ErrBreakerOpen) {
+		observe(req, "breaker_open", latency)
 		return &Error{
+			Kind:     ErrorKindBreakerOpen,
+			Message:  fmt.Sprintf("unable to call %s", req.URL.Host),
+			RawError: err,
+		}
+	}
+
+	if resp == nil {
+		observe(req, "error", latency)
+		return &Error{
+			Kind:     ErrorKindNetwork,
 			Message:  fmt.Sprintf("unable to sends an http request: %s", err.Error()),
 			RawError: err,
 		}
 	}
 
-	defer resp.Body.Close()
-	latency := time.Since(start)
+	observe(req, strconv.Itoa(resp.StatusCode), latency)
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
 
-	responseBody, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return &Error{
+			Kind:       ErrorKindNetwork,
 			Message:    fmt.Sprintf("unable to read response body: %s", err.Error()),
 			StatusCode: resp.StatusCode,
 			RawError:   err,
@@ -98,6 +198,16 @@ func (c *Client) Call(ctx context.Context, method, url string, body io.Reader, h
 			Msg("outbound request")
 	}
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return &Error{
+			Kind:           ErrorKindRateLimited,
+			Message:        fmt.Sprintf("%s %s was rate limited", resp.Request.Method, resp.Request.URL),
+			StatusCode:     resp.StatusCode,
+			RawError:       parseRateLimit(resp.Header, time.Now()),
+			RawAPIResponse: responseBody,
+		}
+	}
+
 	if resp.StatusCode >= 400 {
 		rawErr := fmt.Errorf("%s %s returned error %d response: %s",
 			resp.Request.Method,
@@ -107,6 +217,7 @@ func (c *Client) Call(ctx context.Context, method, url string, body io.Reader, h
 		)
 
 		return &Error{
+			Kind:           ErrorKindHTTP,
 			Message:        "http client error",
 			StatusCode:     resp.StatusCode,
 			RawError:       rawErr,
@@ -117,6 +228,7 @@ func (c *Client) Call(ctx context.Context, method, url string, body io.Reader, h
 	if response != nil {
 		if err = json.Unmarshal(responseBody, response); err != nil {
 			return &Error{
+				Kind:           ErrorKindDecode,
 				Message:        fmt.Sprintf("unable to unmarshaling body response: %s", err.Error()),
 				StatusCode:     resp.StatusCode,
 				RawError:       err,
@@ -127,3 +239,91 @@ func (c *Client) Call(ctx context.Context, method, url string, body io.Reader, h
 
 	return nil
 }
+
+// do sends the attempts of req that policy and b allow, returning the last response with
+// its body read. A nil response means no response was received. Retries wait for the
+// backoff, or the Retry-After or rate limit reset of the response when longer, and are not
+// made when that wait exceeds RetryAfterMax or would outlast the deadline of ctx.
+func (c *Client) do(ctx context.Context, req *http.Request, reqBody []byte, policy Policy, b *breaker) (resp *http.Response, body []byte, err error) {
+	for retry := 0; ; retry++ {
+		if !b.allow() {
+			return nil, nil, ErrBreakerOpen
+		}
+
+		resp, body, err = c.attempt(ctx, req, reqBody, policy.Timeout)
+		b.record(outcomeOf(ctx, resp, err))
+
+		var statusCode int
+		if resp != nil {
+			statusCode = resp.StatusCode
+		}
+
+		if retry >= policy.MaxRetries || ctx.Err() != nil || !policy.retryable(req.Method, statusCode, err) {
+			return resp, body, err
+		}
+
+		// A 429 tells when to retry with Retry-After or with the rate limit reset
+		wait := policy.backoff(retry + 1)
+		if resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.Header.Get("Retry-After") != "") {
+			retryAfter := parseRateLimit(resp.Header, time.Now()).RetryAfter
+			if retryAfter > policy.RetryAfterMax {
+				return resp, body, err
+			}
+
+			wait = max(wait, retryAfter)
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return resp, body, err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return resp, body, err
+		case <-timer.C:
+		}
+
+		metrics.ClientRetries.WithLabelValues(req.URL.Host, req.Method).Inc()
+	}
+}
+
+// attempt sends a copy of req bounded by timeout, zero meaning unbounded.
+func (c *Client) attempt(ctx context.Context, req *http.Request, reqBody []byte, timeout time.Duration) (*http.Response, []byte, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	r := req.Clone(ctx)
+	if reqBody != nil {
+		r.Body = io.NopCloser(bytes.NewReader(reqBody))
+		r.ContentLength = int64(len(reqBody))
+	}
+
+	resp, err := c.HTTPClient.Do(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	return resp, body, err
+}
+
+// outcomeOf tells how an attempt reflects on its host: network errors and 5xx responses
+// are failures, while an attempt whose caller gave up tells nothing.
+func outcomeOf(ctx context.Context, resp *http.Response, err error) outcome {
+	if ctx.Err() != nil {
+		return outcomeAbandoned
+	}
+
+	if err != nil || resp.StatusCode >= http.StatusInternalServerError {
+		return outcomeFailure
+	}
+
+	return outcomeSuccess
+}
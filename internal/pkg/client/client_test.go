@@ -2,7 +2,10 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"integration-go/internal/pkg/config"
+	"integration-go/internal/pkg/metrics"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -10,8 +13,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 type TestResponse struct {
@@ -124,6 +133,253 @@ func TestClient_Call_RequestError(t *testing.T) {
 	client := New()
 	err := client.Call(context.Background(), "GET", "invalid-url", nil, nil, nil)
 	assert.Error(t, err)
+
+	var clientErr *Error
+	require.ErrorAs(t, err, &clientErr)
+	assert.Equal(t, ErrorKindRequest, clientErr.Kind)
+}
+
+func TestClient_Call_ErrorKind(t *testing.T) {
+	tests := []struct {
+		name         string
+		handler      http.HandlerFunc
+		expectedKind ErrorKind
+	}{
+		{
+			name: "http",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			expectedKind: ErrorKindHTTP,
+		},
+		{
+			name: "decode",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`invalid json`))
+			},
+			expectedKind: ErrorKindDecode,
+		},
+		{
+			name: "network",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(50 * time.Millisecond)
+			},
+			expectedKind: ErrorKindNetwork,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			client := New()
+			client.Policy.Timeout = 10 * time.Millisecond
+			client.Policy.MaxRetries = 0
+
+			var response TestResponse
+			err := client.Call(context.Background(), "GET", server.URL, nil, nil, &response)
+
+			var clientErr *Error
+			require.ErrorAs(t, err, &clientErr)
+			assert.Equal(t, tt.expectedKind, clientErr.Kind)
+			assert.False(t, errors.Is(err, ErrBreakerOpen))
+		})
+	}
+}
+
+func TestClient_Call_Retry(t *testing.T) {
+	tests := []struct {
+		name             string
+		method           string
+		status           int
+		expectedAttempts int
+	}{
+		{name: "GET on retryable status", method: "GET", status: http.StatusServiceUnavailable, expectedAttempts: 3},
+		{name: "PUT on retryable status", method: "PUT", status: http.StatusBadGateway, expectedAttempts: 3},
+		{name: "GET on client error", method: "GET", status: http.StatusBadRequest, expectedAttempts: 1},
+		{name: "POST on retryable status", method: "POST", status: http.StatusServiceUnavailable, expectedAttempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int
+			var bodies []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				body, _ := io.ReadAll(r.Body)
+				bodies = append(bodies, string(body))
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			client := New()
+			client.Policy.MaxRetries = 2
+			client.Policy.RetryWaitMin = time.Millisecond
+			client.Policy.RetryWaitMax = time.Millisecond
+
+			err := client.Call(context.Background(), tt.method, server.URL, strings.NewReader(`{"data":"test"}`), nil, nil)
+			require.Error(t, err)
+
+			var clientErr *Error
+			require.ErrorAs(t, err, &clientErr)
+			assert.Equal(t, ErrorKindHTTP, clientErr.Kind)
+			assert.Equal(t, tt.status, clientErr.StatusCode)
+			assert.Equal(t, tt.expectedAttempts, attempts)
+
+			// Every attempt sends the whole body
+			for _, body := range bodies {
+				assert.JSONEq(t, `{"data":"test"}`, body)
+			}
+		})
+	}
+}
+
+func TestClient_Call_RateLimited(t *testing.T) {
+	tests := []struct {
+		name               string
+		retryAfter         string
+		reset              string
+		timeout            time.Duration
+		expectedAttempts   int
+		expectedRetryAfter time.Duration
+	}{
+		{name: "retried after the wait", retryAfter: "0", expectedAttempts: 3},
+		{name: "wait above the maximum", retryAfter: "120", expectedAttempts: 1, expectedRetryAfter: 2 * time.Minute},
+		{name: "wait beyond the deadline", retryAfter: "30", timeout: time.Second, expectedAttempts: 1, expectedRetryAfter: 30 * time.Second},
+		{name: "wait until the reset above the maximum", reset: "120", expectedAttempts: 1, expectedRetryAfter: 2 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				if tt.reset != "" {
+					w.Header().Set("X-RateLimit-Reset", tt.reset)
+				}
+				w.Header().Set("X-RateLimit-Limit", "60")
+				w.Header().Set("X-RateLimit-Remaining", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+			}))
+			defer server.Close()
+
+			client := New()
+			client.Policy.MaxRetries = 2
+			client.Policy.RetryWaitMin = time.Millisecond
+			client.Policy.RetryWaitMax = time.Millisecond
+
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			// A POST is retried too, since the request was rejected before being processed
+			start := time.Now()
+			err := client.Call(ctx, "POST", server.URL, strings.NewReader(`{}`), nil, nil)
+			assert.Less(t, time.Since(start), time.Second)
+
+			var clientErr *Error
+			require.ErrorAs(t, err, &clientErr)
+			assert.Equal(t, ErrorKindRateLimited, clientErr.Kind)
+			assert.Equal(t, http.StatusTooManyRequests, clientErr.StatusCode)
+			assert.Equal(t, tt.expectedAttempts, attempts)
+
+			var rateLimitErr *RateLimitError
+			require.ErrorAs(t, err, &rateLimitErr)
+			assert.Equal(t, tt.expectedRetryAfter, rateLimitErr.RetryAfter)
+			assert.Equal(t, 60, rateLimitErr.Limit)
+			assert.Equal(t, 0, rateLimitErr.Remaining)
+		})
+	}
+}
+
+func TestClient_Call_RetryAfter(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := New()
+	client.Policy.RetryWaitMin = time.Millisecond
+
+	start := time.Now()
+	err := client.Call(context.Background(), "GET", server.URL, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+}
+
+func TestClient_Call_HostPolicy(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := New()
+	client.HostPolicies = map[string]Policy{"127.0.0.1": {MaxRetries: 0}}
+
+	err := client.Call(context.Background(), "GET", server.URL, nil, nil, nil)
+	require.Error(t, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestClient_Call_BreakerOpen(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	client := New()
+	client.Policy.MaxRetries = 0
+	client.Policy.BreakerThreshold = 2
+	client.Policy.BreakerCooldown = time.Minute
+
+	for range 2 {
+		err := client.Call(context.Background(), "GET", server.URL, nil, nil, nil)
+
+		var clientErr *Error
+		require.ErrorAs(t, err, &clientErr)
+		assert.Equal(t, ErrorKindHTTP, clientErr.Kind)
+	}
+
+	err := client.Call(context.Background(), "GET", server.URL, nil, nil, nil)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrBreakerOpen)
+
+	var clientErr *Error
+	require.ErrorAs(t, err, &clientErr)
+	assert.Equal(t, ErrorKindBreakerOpen, clientErr.Kind)
+	assert.Equal(t, 2, attempts)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.ClientRequests.WithLabelValues(host, "GET", "breaker_open")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(metrics.ClientBreakerState.WithLabelValues(host)))
+
+	// Another client has its own breakers
+	other := New()
+	other.Policy.MaxRetries = 0
+
+	err = other.Call(context.Background(), "GET", server.URL, nil, nil, nil)
+	assert.NotErrorIs(t, err, ErrBreakerOpen)
 }
 
 func TestClient_Call_ContextCanceled(t *testing.T) {
@@ -142,6 +398,66 @@ func TestClient_Call_ContextCanceled(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestClient_Call_Metrics(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	err := New().Call(context.Background(), "GET", server.URL, nil, nil, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.ClientRequests.WithLabelValues(host, "GET", "204")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.ClientRetries.WithLabelValues(host, "GET")))
+
+	// A call without response is recorded with an error status
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = New().Call(ctx, "GET", server.URL, nil, nil, nil)
+	require.Error(t, err)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.ClientRequests.WithLabelValues(host, "GET", "error")))
+}
+
+func TestClient_Call_Propagation(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	})
+
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	ctx := context.WithValue(context.Background(), config.RequestIDKey, "req-123")
+	err := New().Call(ctx, "post", server.URL, nil, nil, nil)
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "POST "+strings.TrimPrefix(server.URL, "http://"), spans[0].Name())
+
+	assert.Equal(t, "req-123", header.Get("X-Request-Id"))
+	assert.Equal(t, fmt.Sprintf("00-%s-%s-01", spans[0].SpanContext().TraceID(), spans[0].SpanContext().SpanID()), header.Get("Traceparent"))
+}
+
 func TestError_Implementation(t *testing.T) {
 	err := &Error{
 		Message:        "test error",
@@ -1,8 +1,34 @@
 package client
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrBreakerOpen is the RawError of the calls rejected because the circuit breaker of
+// their host is open.
+var ErrBreakerOpen = errors.New("circuit breaker is open")
+
+// ErrorKind tells at which step a call failed.
+type ErrorKind string
+
+const (
+	// ErrorKindRequest is a request that could not be built, e.g. from an invalid URL.
+	ErrorKindRequest ErrorKind = "request"
+	// ErrorKindBreakerOpen is a call not sent because the host is failing.
+	ErrorKindBreakerOpen ErrorKind = "breaker_open"
+	// ErrorKindNetwork is a call without a complete response, e.g. on a timeout.
+	ErrorKindNetwork ErrorKind = "network"
+	// ErrorKindRateLimited is a 429 response, with a *RateLimitError as RawError.
+	ErrorKindRateLimited ErrorKind = "rate_limited"
+	// ErrorKindHTTP is any other response with a status code of 400 or above.
+	ErrorKindHTTP ErrorKind = "http"
+	// ErrorKindDecode is a response body that is not the expected JSON.
+	ErrorKindDecode ErrorKind = "decode"
+)
 
 type Error struct {
+	Kind           ErrorKind
 	Message        string
 	StatusCode     int
 	RawError       error
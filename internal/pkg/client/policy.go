@@ -0,0 +1,110 @@
+package client
+
+import (
+	"integration-go/internal/pkg/config"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// Policy is how calls to a host are attempted: how long an attempt may take, which
+// failed attempts are retried and when the circuit breaker of the host opens.
+type Policy struct {
+	Timeout    time.Duration
+	MaxRetries int
+	// RetryWaitMin is the wait before the first retry, doubled on every retry up to
+	// RetryWaitMax.
+	RetryWaitMin time.Duration
+	RetryWaitMax time.Duration
+	// RetryAfterMax is the longest Retry-After waited for, a response asking for more is
+	// returned right away.
+	RetryAfterMax time.Duration
+	// RetryMethods are the methods safe to send twice, RetryStatusCodes the responses
+	// retried on top of network errors. A 429 response is retried whatever the method,
+	// since the request was rejected before being processed.
+	RetryMethods     []string
+	RetryStatusCodes []int
+	// BreakerThreshold consecutive failed attempts open the breaker for BreakerCooldown,
+	// zero disables it.
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+// DefaultPolicy retries idempotent methods on network errors and 5xx responses other
+// than 501, and every method on 429 responses.
+func DefaultPolicy() Policy {
+	return Policy{
+		Timeout:          20 * time.Second,
+		MaxRetries:       3,
+		RetryWaitMin:     500 * time.Millisecond,
+		RetryWaitMax:     5 * time.Second,
+		RetryAfterMax:    time.Minute,
+		RetryMethods:     []string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions},
+		RetryStatusCodes: []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+		BreakerThreshold: 5,
+		BreakerCooldown:  30 * time.Second,
+	}
+}
+
+// policiesFromConfig returns the default policy and the policies of the hosts with an
+// overridden timeout or retry budget.
+func policiesFromConfig(cfg config.Client) (Policy, map[string]Policy) {
+	policy := Policy{
+		Timeout:          cfg.Timeout,
+		MaxRetries:       cfg.MaxRetries,
+		RetryWaitMin:     cfg.RetryWaitMin,
+		RetryWaitMax:     cfg.RetryWaitMax,
+		RetryAfterMax:    cfg.RetryAfterMax,
+		RetryMethods:     cfg.RetryMethods,
+		RetryStatusCodes: cfg.RetryStatusCodes,
+		BreakerThreshold: cfg.BreakerThreshold,
+		BreakerCooldown:  cfg.BreakerCooldown,
+	}
+
+	hosts := make(map[string]Policy)
+	for host, timeout := range cfg.HostTimeouts {
+		p := policy
+		p.Timeout = timeout
+		hosts[host] = p
+	}
+
+	for host, maxRetries := range cfg.HostMaxRetries {
+		p, ok := hosts[host]
+		if !ok {
+			p = policy
+		}
+
+		p.MaxRetries = maxRetries
+		hosts[host] = p
+	}
+
+	return policy, hosts
+}
+
+// retryable tells whether an attempt that received statusCode, or failed with err, may be
+// sent again.
+func (p Policy) retryable(method string, statusCode int, err error) bool {
+	if statusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	if !slices.Contains(p.RetryMethods, method) {
+		return false
+	}
+
+	if err != nil {
+		return true
+	}
+
+	return slices.Contains(p.RetryStatusCodes, statusCode)
+}
+
+// backoff returns the wait before the given retry, the first one being retry 1.
+func (p Policy) backoff(retry int) time.Duration {
+	wait := p.RetryWaitMin
+	for i := 1; i < retry && wait < p.RetryWaitMax; i++ {
+		wait *= 2
+	}
+
+	return min(wait, p.RetryWaitMax)
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"errors"
+	"integration-go/internal/pkg/config"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy_Retryable(t *testing.T) {
+	policy := DefaultPolicy()
+
+	tests := []struct {
+		name       string
+		method     string
+		statusCode int
+		err        error
+		expected   bool
+	}{
+		{name: "GET with retryable status", method: http.MethodGet, statusCode: http.StatusServiceUnavailable, expected: true},
+		{name: "GET with network error", method: http.MethodGet, err: errors.New("connection refused"), expected: true},
+		{name: "GET with client error", method: http.MethodGet, statusCode: http.StatusNotFound, expected: false},
+		{name: "GET with not implemented", method: http.MethodGet, statusCode: http.StatusNotImplemented, expected: false},
+		{name: "POST with retryable status", method: http.MethodPost, statusCode: http.StatusServiceUnavailable, expected: false},
+		{name: "POST with network error", method: http.MethodPost, err: errors.New("connection refused"), expected: false},
+		{name: "POST with too many requests", method: http.MethodPost, statusCode: http.StatusTooManyRequests, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, policy.retryable(tt.method, tt.statusCode, tt.err))
+		})
+	}
+}
+
+func TestPolicy_Backoff(t *testing.T) {
+	policy := Policy{RetryWaitMin: time.Second, RetryWaitMax: 5 * time.Second}
+
+	assert.Equal(t, time.Second, policy.backoff(1))
+	assert.Equal(t, 2*time.Second, policy.backoff(2))
+	assert.Equal(t, 4*time.Second, policy.backoff(3))
+	assert.Equal(t, 5*time.Second, policy.backoff(4))
+	assert.Equal(t, 5*time.Second, policy.backoff(10))
+}
+
+func TestPoliciesFromConfig(t *testing.T) {
+	cfg := config.Client{
+		Timeout:          20 * time.Second,
+		MaxRetries:       3,
+		HostTimeouts:     map[string]time.Duration{"slow.test": time.Minute, "both.test": 5 * time.Second},
+		HostMaxRetries:   map[string]int{"both.test": 1, "flaky.test": 5},
+		RetryMethods:     []string{http.MethodGet},
+		RetryStatusCodes: []int{http.StatusServiceUnavailable},
+		RetryWaitMin:     time.Second,
+		RetryWaitMax:     time.Minute,
+		BreakerThreshold: 10,
+		BreakerCooldown:  time.Minute,
+	}
+
+	policy, hosts := policiesFromConfig(cfg)
+
+	assert.Equal(t, Policy{
+		Timeout:          20 * time.Second,
+		MaxRetries:       3,
+		RetryWaitMin:     time.Second,
+		RetryWaitMax:     time.Minute,
+		RetryMethods:     []string{http.MethodGet},
+		RetryStatusCodes: []int{http.StatusServiceUnavailable},
+		BreakerThreshold: 10,
+		BreakerCooldown:  time.Minute,
+	}, policy)
+
+	assert.Len(t, hosts, 3)
+	assert.Equal(t, time.Minute, hosts["slow.test"].Timeout)
+	assert.Equal(t, 3, hosts["slow.test"].MaxRetries)
+	assert.Equal(t, 5*time.Second, hosts["both.test"].Timeout)
+	assert.Equal(t, 1, hosts["both.test"].MaxRetries)
+	assert.Equal(t, 20*time.Second, hosts["flaky.test"].Timeout)
+	assert.Equal(t, 5, hosts["flaky.test"].MaxRetries)
+	assert.Equal(t, 10, hosts["flaky.test"].BreakerThreshold)
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimitError is the RawError of a call answered with 429 Too Many Requests once the
+// retries allowed by the policy are exhausted, with what the response told about the quota.
+type RateLimitError struct {
+	// RetryAfter is how long to wait before calling again, zero when not told.
+	RetryAfter time.Duration
+	// Limit and Remaining are the quota of the current window and what is left of it, -1
+	// when not told. Reset is when the window ends, zero when not told.
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("rate limited, retry after %s", e.RetryAfter)
+	}
+
+	return "rate limited"
+}
+
+// parseRateLimit reads the Retry-After header, either in seconds or as an HTTP date, and
+// the X-RateLimit-* or RateLimit-* headers. A reset below a year of seconds is taken as a
+// delay, above as a Unix time.
+func parseRateLimit(header http.Header, now time.Time) *RateLimitError {
+	e := &RateLimitError{
+		Limit:     headerInt(header, "Limit"),
+		Remaining: headerInt(header, "Remaining"),
+	}
+
+	if reset := headerInt(header, "Reset"); reset >= 0 {
+		if reset < 365*24*60*60 {
+			e.Reset = now.Add(time.Duration(reset) * time.Second)
+		} else {
+			e.Reset = time.Unix(int64(reset), 0)
+		}
+	}
+
+	if value := header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil {
+			e.RetryAfter = time.Duration(seconds) * time.Second
+		} else if date, err := http.ParseTime(value); err == nil {
+			e.RetryAfter = date.Sub(now)
+		}
+	} else if e.Remaining == 0 && !e.Reset.IsZero() {
+		e.RetryAfter = e.Reset.Sub(now)
+	}
+
+	e.RetryAfter = max(e.RetryAfter, 0)
+	return e
+}
+
+// headerInt returns the X-RateLimit-<name> or RateLimit-<name> header, -1 when missing or
+// invalid.
+func headerInt(header http.Header, name string) int {
+	for _, key := range []string{"X-RateLimit-" + name, "RateLimit-" + name} {
+		if value, err := strconv.Atoi(header.Get(key)); err == nil && value >= 0 {
+			return value
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		header   map[string]string
+		expected *RateLimitError
+	}{
+		{
+			name:     "no headers",
+			expected: &RateLimitError{Limit: -1, Remaining: -1},
+		},
+		{
+			name:     "retry after seconds",
+			header:   map[string]string{"Retry-After": "3"},
+			expected: &RateLimitError{RetryAfter: 3 * time.Second, Limit: -1, Remaining: -1},
+		},
+		{
+			name:     "retry after date",
+			header:   map[string]string{"Retry-After": now.Add(time.Minute).Format(http.TimeFormat)},
+			expected: &RateLimitError{RetryAfter: time.Minute, Limit: -1, Remaining: -1},
+		},
+		{
+			name:     "retry after date in the past",
+			header:   map[string]string{"Retry-After": now.Add(-time.Minute).Format(http.TimeFormat)},
+			expected: &RateLimitError{Limit: -1, Remaining: -1},
+		},
+		{
+			name: "rate limit headers with reset delay",
+			header: map[string]string{
+				"X-RateLimit-Limit":     "100",
+				"X-RateLimit-Remaining": "0",
+				"X-RateLimit-Reset":     "10",
+			},
+			expected: &RateLimitError{RetryAfter: 10 * time.Second, Limit: 100, Remaining: 0, Reset: now.Add(10 * time.Second)},
+		},
+		{
+			name: "rate limit headers with reset time",
+			header: map[string]string{
+				"RateLimit-Limit":     "100",
+				"RateLimit-Remaining": "0",
+				"RateLimit-Reset":     "1735787065",
+			},
+			expected: &RateLimitError{RetryAfter: 20 * time.Second, Limit: 100, Remaining: 0, Reset: time.Unix(1735787065, 0)},
+		},
+		{
+			name: "retry after takes precedence over reset",
+			header: map[string]string{
+				"Retry-After":           "1",
+				"X-RateLimit-Remaining": "0",
+				"X-RateLimit-Reset":     "10",
+			},
+			expected: &RateLimitError{RetryAfter: time.Second, Limit: -1, Remaining: 0, Reset: now.Add(10 * time.Second)},
+		},
+		{
+			name:     "invalid values",
+			header:   map[string]string{"Retry-After": "soon", "X-RateLimit-Limit": "many"},
+			expected: &RateLimitError{Limit: -1, Remaining: -1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for k, v := range tt.header {
+				header.Set(k, v)
+			}
+
+			assert.Equal(t, tt.expected, parseRateLimit(header, now))
+		})
+	}
+}
+
+func TestRateLimitError_Error(t *testing.T) {
+	assert.Equal(t, "rate limited, retry after 3s", (&RateLimitError{RetryAfter: 3 * time.Second}).Error())
+	assert.Equal(t, "rate limited", (&RateLimitError{}).Error())
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/caarlos0/env/v9"
 	"github.com/rs/zerolog/log"
@@ -16,11 +17,35 @@ func Load() *Config {
 	return &c
 }
 
+// LoadAPI loads the configuration of the API server, which requires the settings only the
+// API uses on top of Config.
+func LoadAPI() *API {
+	var c API
+	if err := env.Parse(&c); err != nil {
+		log.Fatal().Msgf("unable to parse env: %s", err.Error())
+	}
+
+	return &c
+}
+
+// API is the configuration of the API server. The worker and cron load Config only, so
+// they start without the webhook secrets.
+type API struct {
+	Config
+	Webhook Webhook
+}
+
 type Config struct {
-	App      App
-	Database Database
-	Redis    Redis
-	Qiscus   Qiscus
+	App        App
+	Database   Database
+	Redis      Redis
+	Qiscus     Qiscus
+	Worker     Worker
+	Allocation Allocation
+	Resolver   Resolver
+	Cron       Cron
+	Tracing    Tracing
+	Client     Client
 }
 
 type App struct {
@@ -46,11 +71,125 @@ type Redis struct {
 }
 
 type Qiscus struct {
-	AppID       string `env:"QISCUS_APP_ID,required"`
-	SecretKey   string `env:"QISCUS_SECRET_KEY,required"`
+	AppID     string `env:"QISCUS_APP_ID,required"`
+	SecretKey string `env:"QISCUS_SECRET_KEY,required"`
+	SDKURL    string `env:"QISCUS_SDK_URL" envDefault:"https://api.qiscus.com"`
+	// RateLimit caps the Qiscus API requests per second of a process, zero means unlimited.
+	RateLimit   float64 `env:"QISCUS_RATE_LIMIT" envDefault:"0"`
+	RateBurst   int     `env:"QISCUS_RATE_BURST" envDefault:"10"`
 	Omnichannel Omnichannel
 }
 
 type Omnichannel struct {
 	URL string `env:"QISCUS_OMNICHANNEL_URL,required"`
 }
+
+type Webhook struct {
+	// Secrets accepts several comma separated secrets so they can be rotated
+	// without downtime.
+	Secrets   []string      `env:"QISCUS_WEBHOOK_SECRETS,required"`
+	Tolerance time.Duration `env:"QISCUS_WEBHOOK_TOLERANCE" envDefault:"5m"`
+	DedupTTL  time.Duration `env:"QISCUS_WEBHOOK_DEDUP_TTL" envDefault:"24h"`
+}
+
+type Worker struct {
+	Concurrency  int           `env:"WORKER_CONCURRENCY" envDefault:"4"`
+	MaxAttempts  int           `env:"WORKER_MAX_ATTEMPTS" envDefault:"5"`
+	PollInterval time.Duration `env:"WORKER_POLL_INTERVAL" envDefault:"1s"`
+	Backoff      time.Duration `env:"WORKER_BACKOFF" envDefault:"10s"`
+	LockTimeout  time.Duration `env:"WORKER_LOCK_TIMEOUT" envDefault:"5m"`
+	// JobTimeout bounds a single job run. It must be shorter than LockTimeout, so a job
+	// is finished before another worker can claim it again.
+	JobTimeout time.Duration `env:"WORKER_JOB_TIMEOUT" envDefault:"4m"`
+}
+
+type Allocation struct {
+	Strategy string `env:"ALLOCATION_STRATEGY" envDefault:"least_load"`
+	// MaxCustomers skips agents already handling that many customers, zero means unlimited.
+	MaxCustomers int `env:"ALLOCATION_MAX_CUSTOMERS" envDefault:"0"`
+	// Divisions maps a channel source to the division whose agents handle it,
+	// e.g. "wa:12,telegram:15".
+	Divisions map[string]int64 `env:"ALLOCATION_DIVISIONS" envKeyValSeparator:":"`
+}
+
+type Resolver struct {
+	// Mode is either "created", to resolve rooms open for longer than the timeout, or
+	// "idle", to resolve rooms whose customer has not sent a message within the timeout.
+	Mode    string        `env:"RESOLVER_MODE" envDefault:"created"`
+	Timeout time.Duration `env:"RESOLVER_TIMEOUT" envDefault:"10m"`
+	// ChannelTimeouts overrides the timeout per channel source, e.g. "wa:30m,telegram:1h".
+	ChannelTimeouts map[string]time.Duration `env:"RESOLVER_CHANNEL_TIMEOUTS" envKeyValSeparator:":"`
+	// TagTimeouts overrides the timeout per room tag, e.g. "vip:2h". It takes precedence
+	// over ChannelTimeouts.
+	TagTimeouts map[string]time.Duration `env:"RESOLVER_TAG_TIMEOUTS" envKeyValSeparator:":"`
+	// ClosingMessage is sent as the bot, from ClosingMessageSender, before resolving a room.
+	ClosingMessage       string `env:"RESOLVER_CLOSING_MESSAGE"`
+	ClosingMessageSender string `env:"RESOLVER_CLOSING_MESSAGE_SENDER"`
+	// BatchSize is the number of rooms loaded at once, MaxRoomsPerRun caps the rooms
+	// evaluated in a single run so one tick cannot run forever.
+	BatchSize      int `env:"RESOLVER_BATCH_SIZE" envDefault:"100"`
+	MaxRoomsPerRun int `env:"RESOLVER_MAX_ROOMS_PER_RUN" envDefault:"1000"`
+	// Concurrency is the number of rooms resolved in parallel. RateLimit caps the
+	// Omnichannel API requests per second of the resolver, zero means unlimited. Its
+	// requests also wait for Qiscus.RateLimit, which they share with the rest of the process.
+	Concurrency int           `env:"RESOLVER_CONCURRENCY" envDefault:"4"`
+	RateLimit   float64       `env:"RESOLVER_RATE_LIMIT" envDefault:"10"`
+	RateBurst   int           `env:"RESOLVER_RATE_BURST" envDefault:"10"`
+	RunTimeout  time.Duration `env:"RESOLVER_RUN_TIMEOUT" envDefault:"50s"`
+}
+
+type Cron struct {
+	// LockTTL is the lease of the lock a replica holds while running a job. A replica that
+	// dies releases its jobs to the other replicas after at most LockTTL.
+	LockTTL time.Duration `env:"CRON_LOCK_TTL" envDefault:"30s"`
+	// Schedules overrides the cron expression per job name, separated by semicolons since
+	// expressions may contain commas, e.g. "resolver:*/5 * * * *;cleanup:0 3 * * *".
+	Schedules map[string]string `env:"CRON_SCHEDULES" envSeparator:";" envKeyValSeparator:":"`
+	// HealthWindow is the minimum time within which a job must have succeeded to be
+	// healthy, jobs running less often get twice the interval of their schedule.
+	// HealthWindows overrides the window per job name, e.g. "job_runs_cleanup:26h".
+	HealthWindow  time.Duration            `env:"CRON_HEALTH_WINDOW" envDefault:"15m"`
+	HealthWindows map[string]time.Duration `env:"CRON_HEALTH_WINDOWS" envKeyValSeparator:":"`
+	// RunRetention is how long the runs of the jobs are kept.
+	RunRetention time.Duration `env:"CRON_RUN_RETENTION" envDefault:"168h"`
+	// TriggerPollInterval is how often replicas check for manually triggered jobs.
+	TriggerPollInterval time.Duration `env:"CRON_TRIGGER_POLL_INTERVAL" envDefault:"5s"`
+	// ShutdownTimeout is how long in-flight runs are waited for on shutdown before their
+	// context is canceled. It should stay below the termination grace period of the pod.
+	ShutdownTimeout time.Duration `env:"CRON_SHUTDOWN_TIMEOUT" envDefault:"20s"`
+	// MetricsPort is the port serving the Prometheus metrics of the cron process on
+	// /metrics, zero disables it.
+	MetricsPort int `env:"CRON_METRICS_PORT" envDefault:"9090"`
+}
+
+type Tracing struct {
+	// Exporter is where spans are sent: "otlp", "stdout" or "none". The OTLP endpoint is
+	// configured with the standard OTEL_EXPORTER_OTLP_ENDPOINT variable.
+	Exporter    string  `env:"TRACING_EXPORTER" envDefault:"none"`
+	ServiceName string  `env:"TRACING_SERVICE_NAME" envDefault:"integration-go"`
+	SampleRatio float64 `env:"TRACING_SAMPLE_RATIO" envDefault:"1"`
+}
+
+type Client struct {
+	// Timeout bounds a single attempt of an outbound call and MaxRetries is the number of
+	// attempts made after a failed one. HostTimeouts and HostMaxRetries override them per
+	// host name, e.g. "multichannel.qiscus.com:10s".
+	Timeout        time.Duration            `env:"CLIENT_TIMEOUT" envDefault:"20s"`
+	MaxRetries     int                      `env:"CLIENT_MAX_RETRIES" envDefault:"3"`
+	HostTimeouts   map[string]time.Duration `env:"CLIENT_HOST_TIMEOUTS" envKeyValSeparator:":"`
+	HostMaxRetries map[string]int           `env:"CLIENT_HOST_MAX_RETRIES" envKeyValSeparator:":"`
+	// RetryMethods are the methods safe to send twice. Attempts of other methods, such as
+	// POST, are never retried. RetryStatusCodes are the responses retried, on top of
+	// network errors.
+	RetryMethods     []string      `env:"CLIENT_RETRY_METHODS" envDefault:"GET,HEAD,PUT,DELETE,OPTIONS"`
+	RetryStatusCodes []int         `env:"CLIENT_RETRY_STATUS_CODES" envDefault:"500,502,503,504"`
+	RetryWaitMin     time.Duration `env:"CLIENT_RETRY_WAIT_MIN" envDefault:"500ms"`
+	RetryWaitMax     time.Duration `env:"CLIENT_RETRY_WAIT_MAX" envDefault:"5s"`
+	// RetryAfterMax is the longest Retry-After of a 429 or 503 response waited for before
+	// retrying, a longer one fails the call right away.
+	RetryAfterMax time.Duration `env:"CLIENT_RETRY_AFTER_MAX" envDefault:"1m"`
+	// BreakerThreshold consecutive failed attempts to a host open its circuit breaker, which
+	// fails the calls to that host fast for BreakerCooldown, zero disables it.
+	BreakerThreshold int           `env:"CLIENT_BREAKER_THRESHOLD" envDefault:"5"`
+	BreakerCooldown  time.Duration `env:"CLIENT_BREAKER_COOLDOWN" envDefault:"30s"`
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/caarlos0/env/v9"
 	"github.com/stretchr/testify/assert"
@@ -21,6 +22,11 @@ func TestLoad(t *testing.T) {
 		"QISCUS_APP_ID":          "test-app-id",
 		"QISCUS_SECRET_KEY":      "test-qiscus-secret",
 		"QISCUS_OMNICHANNEL_URL": "https://test.qiscus.com",
+		"ALLOCATION_DIVISIONS":   "wa:12,telegram:15",
+		"RESOLVER_TAG_TIMEOUTS":  "vip:2h",
+		"CRON_SCHEDULES":         "resolver:*/5 * * * *;cleanup:0 1,13 * * *",
+		"CRON_HEALTH_WINDOWS":    "job_runs_cleanup:26h",
+		"CLIENT_HOST_TIMEOUTS":   "api.qiscus.com:5s",
 	}
 
 	for k, v := range envVars {
@@ -39,7 +45,89 @@ func TestLoad(t *testing.T) {
 	assert.Equal(t, "redis://localhost:6379", config.Redis.URL)
 	assert.Equal(t, "test-app-id", config.Qiscus.AppID)
 	assert.Equal(t, "test-qiscus-secret", config.Qiscus.SecretKey)
+	assert.Equal(t, "https://api.qiscus.com", config.Qiscus.SDKURL)
+	assert.Equal(t, 0.0, config.Qiscus.RateLimit)
+	assert.Equal(t, 10, config.Qiscus.RateBurst)
 	assert.Equal(t, "https://test.qiscus.com", config.Qiscus.Omnichannel.URL)
+	assert.Equal(t, 4, config.Worker.Concurrency)
+	assert.Equal(t, 5, config.Worker.MaxAttempts)
+	assert.Equal(t, time.Second, config.Worker.PollInterval)
+	assert.Equal(t, 10*time.Second, config.Worker.Backoff)
+	assert.Equal(t, 5*time.Minute, config.Worker.LockTimeout)
+	assert.Equal(t, 4*time.Minute, config.Worker.JobTimeout)
+	assert.Equal(t, "least_load", config.Allocation.Strategy)
+	assert.Equal(t, 0, config.Allocation.MaxCustomers)
+	assert.Equal(t, map[string]int64{"wa": 12, "telegram": 15}, config.Allocation.Divisions)
+	assert.Equal(t, "created", config.Resolver.Mode)
+	assert.Equal(t, 10*time.Minute, config.Resolver.Timeout)
+	assert.Empty(t, config.Resolver.ChannelTimeouts)
+	assert.Equal(t, map[string]time.Duration{"vip": 2 * time.Hour}, config.Resolver.TagTimeouts)
+	assert.Empty(t, config.Resolver.ClosingMessage)
+	assert.Equal(t, 100, config.Resolver.BatchSize)
+	assert.Equal(t, 1000, config.Resolver.MaxRoomsPerRun)
+	assert.Equal(t, 4, config.Resolver.Concurrency)
+	assert.Equal(t, float64(10), config.Resolver.RateLimit)
+	assert.Equal(t, 10, config.Resolver.RateBurst)
+	assert.Equal(t, 50*time.Second, config.Resolver.RunTimeout)
+	assert.Equal(t, 30*time.Second, config.Cron.LockTTL)
+	assert.Equal(t, map[string]string{"resolver": "*/5 * * * *", "cleanup": "0 1,13 * * *"}, config.Cron.Schedules)
+	assert.Equal(t, 15*time.Minute, config.Cron.HealthWindow)
+	assert.Equal(t, map[string]time.Duration{"job_runs_cleanup": 26 * time.Hour}, config.Cron.HealthWindows)
+	assert.Equal(t, 168*time.Hour, config.Cron.RunRetention)
+	assert.Equal(t, 5*time.Second, config.Cron.TriggerPollInterval)
+	assert.Equal(t, 20*time.Second, config.Cron.ShutdownTimeout)
+	assert.Equal(t, 9090, config.Cron.MetricsPort)
+	assert.Equal(t, "none", config.Tracing.Exporter)
+	assert.Equal(t, "integration-go", config.Tracing.ServiceName)
+	assert.Equal(t, 1.0, config.Tracing.SampleRatio)
+	assert.Equal(t, 20*time.Second, config.Client.Timeout)
+	assert.Equal(t, 3, config.Client.MaxRetries)
+	assert.Equal(t, map[string]time.Duration{"api.qiscus.com": 5 * time.Second}, config.Client.HostTimeouts)
+	assert.Empty(t, config.Client.HostMaxRetries)
+	assert.Equal(t, []string{"GET", "HEAD", "PUT", "DELETE", "OPTIONS"}, config.Client.RetryMethods)
+	assert.Equal(t, []int{500, 502, 503, 504}, config.Client.RetryStatusCodes)
+	assert.Equal(t, 500*time.Millisecond, config.Client.RetryWaitMin)
+	assert.Equal(t, 5*time.Second, config.Client.RetryWaitMax)
+	assert.Equal(t, time.Minute, config.Client.RetryAfterMax)
+	assert.Equal(t, 5, config.Client.BreakerThreshold)
+	assert.Equal(t, 30*time.Second, config.Client.BreakerCooldown)
+}
+
+func TestLoadAPI(t *testing.T) {
+	envVars := map[string]string{
+		"APP_SECRET_KEY":         "test-secret",
+		"DATABASE_HOST":          "localhost",
+		"DATABASE_PORT":          "5432",
+		"DATABASE_USER":          "testuser",
+		"DATABASE_PASSWORD":      "testpass",
+		"DATABASE_NAME":          "testdb",
+		"DATABASE_LOG_LEVEL":     "debug",
+		"REDIS_URL":              "redis://localhost:6379",
+		"QISCUS_APP_ID":          "test-app-id",
+		"QISCUS_SECRET_KEY":      "test-qiscus-secret",
+		"QISCUS_OMNICHANNEL_URL": "https://test.qiscus.com",
+	}
+
+	for k, v := range envVars {
+		t.Setenv(k, v)
+	}
+
+	// Only the API server requires the webhook secrets
+	t.Setenv("QISCUS_WEBHOOK_SECRETS", "")
+	os.Unsetenv("QISCUS_WEBHOOK_SECRETS")
+
+	var c API
+	assert.Error(t, env.Parse(&c))
+
+	t.Setenv("QISCUS_WEBHOOK_SECRETS", "secret-new,secret-old")
+
+	config := LoadAPI()
+
+	assert.Equal(t, "test-secret", config.App.SecretKey)
+	assert.Equal(t, "https://test.qiscus.com", config.Qiscus.Omnichannel.URL)
+	assert.Equal(t, []string{"secret-new", "secret-old"}, config.Webhook.Secrets)
+	assert.Equal(t, 5*time.Minute, config.Webhook.Tolerance)
+	assert.Equal(t, 24*time.Hour, config.Webhook.DedupTTL)
 }
 
 func TestDatabase_DataSourceName(t *testing.T) {
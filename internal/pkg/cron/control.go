@@ -0,0 +1,130 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	jobsKey   = "cron:jobs"
+	pausedKey = "cron:paused"
+)
+
+// triggerTTL bounds how long a trigger waits for a cron replica, so a trigger made while
+// the cron is down does not run hours later.
+const triggerTTL = 10 * time.Minute
+
+// Control holds the state operators change at runtime in Redis, shared by every cron
+// replica and the API server: the registered jobs, the paused jobs and the pending
+// manual triggers.
+type Control struct {
+	rdb *redis.Client
+}
+
+func NewControl(rdb *redis.Client) *Control {
+	return &Control{
+		rdb: rdb,
+	}
+}
+
+func triggerKey(name string) string {
+	return fmt.Sprintf("cron:trigger:%s", name)
+}
+
+// Announce publishes the jobs registered in the cron server, so the API server can tell
+// registered jobs apart. It replaces the jobs announced before, so a job removed from the
+// cron server is no longer reported once a replica of the new build starts.
+func (c *Control) Announce(ctx context.Context, entries []Entry) error {
+	values := make(map[string]any, len(entries))
+	for _, entry := range entries {
+		values[entry.Job.Name()] = entry.Schedule
+	}
+
+	_, err := c.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, jobsKey)
+		if len(values) > 0 {
+			pipe.HSet(ctx, jobsKey, values)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to announce jobs: %w", err)
+	}
+
+	return nil
+}
+
+// Jobs returns the effective schedule of every registered job, by job name.
+func (c *Control) Jobs(ctx context.Context) (map[string]string, error) {
+	jobs, err := c.rdb.HGetAll(ctx, jobsKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+func (c *Control) IsRegistered(ctx context.Context, name string) (bool, error) {
+	ok, err := c.rdb.HExists(ctx, jobsKey, name).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check job: %w", err)
+	}
+
+	return ok, nil
+}
+
+func (c *Control) Pause(ctx context.Context, name string) error {
+	err := c.rdb.HSet(ctx, pausedKey, name, time.Now().UTC().Format(time.RFC3339)).Err()
+	if err != nil {
+		return fmt.Errorf("failed to pause job: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Control) Resume(ctx context.Context, name string) error {
+	if err := c.rdb.HDel(ctx, pausedKey, name).Err(); err != nil {
+		return fmt.Errorf("failed to resume job: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Control) IsPaused(ctx context.Context, name string) (bool, error) {
+	ok, err := c.rdb.HExists(ctx, pausedKey, name).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check paused job: %w", err)
+	}
+
+	return ok, nil
+}
+
+// Trigger requests a single run of the job, picked up by one cron replica. Triggering a
+// job that is already triggered replaces the pending request.
+func (c *Control) Trigger(ctx context.Context, name string, reqID string) error {
+	if err := c.rdb.Set(ctx, triggerKey(name), reqID, triggerTTL).Err(); err != nil {
+		return fmt.Errorf("failed to trigger job: %w", err)
+	}
+
+	return nil
+}
+
+// TakeTrigger returns the request ID of the pending trigger of the job and removes it, or
+// an empty string when the job is not triggered. Only one replica takes a trigger.
+func (c *Control) TakeTrigger(ctx context.Context, name string) (string, error) {
+	reqID, err := c.rdb.GetDel(ctx, triggerKey(name)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to take trigger: %w", err)
+	}
+
+	return reqID, nil
+}
@@ -0,0 +1,101 @@
+package cron
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestControl(t *testing.T) (*Control, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	return NewControl(rdb), mr
+}
+
+func TestControl_Announce(t *testing.T) {
+	control, _ := newTestControl(t)
+	ctx := context.Background()
+
+	err := control.Announce(ctx, []Entry{{Job: &testJob{name: "resolver"}, Schedule: "* * * * *"}})
+	require.NoError(t, err)
+
+	ok, err := control.IsRegistered(ctx, "resolver")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = control.IsRegistered(ctx, "cleanup")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// A new build replaces the jobs of the previous one
+	err = control.Announce(ctx, []Entry{
+		{Job: &testJob{name: "resolver"}, Schedule: "* * * * *"},
+		{Job: &testJob{name: "cleanup"}, Schedule: "0 * * * *"},
+	})
+	require.NoError(t, err)
+
+	jobs, err := control.Jobs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"cleanup": "0 * * * *", "resolver": "* * * * *"}, jobs)
+
+	err = control.Announce(ctx, []Entry{{Job: &testJob{name: "cleanup"}, Schedule: "0 * * * *"}})
+	require.NoError(t, err)
+
+	jobs, err = control.Jobs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"cleanup": "0 * * * *"}, jobs)
+}
+
+func TestControl_PauseResume(t *testing.T) {
+	control, _ := newTestControl(t)
+	ctx := context.Background()
+
+	require.NoError(t, control.Pause(ctx, "resolver"))
+
+	paused, err := control.IsPaused(ctx, "resolver")
+	require.NoError(t, err)
+	assert.True(t, paused)
+
+	paused, err = control.IsPaused(ctx, "cleanup")
+	require.NoError(t, err)
+	assert.False(t, paused)
+
+	require.NoError(t, control.Resume(ctx, "resolver"))
+
+	paused, err = control.IsPaused(ctx, "resolver")
+	require.NoError(t, err)
+	assert.False(t, paused)
+}
+
+func TestControl_Trigger(t *testing.T) {
+	control, mr := newTestControl(t)
+	ctx := context.Background()
+
+	reqID, err := control.TakeTrigger(ctx, "resolver")
+	require.NoError(t, err)
+	assert.Empty(t, reqID)
+
+	require.NoError(t, control.Trigger(ctx, "resolver", "req-1"))
+
+	// Only one replica takes the trigger
+	reqID, err = control.TakeTrigger(ctx, "resolver")
+	require.NoError(t, err)
+	assert.Equal(t, "req-1", reqID)
+
+	reqID, err = control.TakeTrigger(ctx, "resolver")
+	require.NoError(t, err)
+	assert.Empty(t, reqID)
+
+	// A trigger nobody takes expires
+	require.NoError(t, control.Trigger(ctx, "resolver", "req-2"))
+	mr.FastForward(triggerTTL + time.Second)
+
+	reqID, err = control.TakeTrigger(ctx, "resolver")
+	require.NoError(t, err)
+	assert.Empty(t, reqID)
+}
@@ -0,0 +1,87 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Job is a scheduled job. Schedule is a standard 5 field cron expression, e.g. "*/5 * * * *",
+// and can be overridden per job name with CRON_SCHEDULES. A zero Timeout means the run is
+// only bounded by the job itself. Run returns summary counts, e.g. the processed items,
+// recorded with the run.
+type Job interface {
+	Name() string
+	Schedule() string
+	Timeout() time.Duration
+	Run(ctx context.Context) (map[string]int, error)
+}
+
+// Registry holds the jobs modules register in the cron server, in registration order.
+type Registry struct {
+	jobs      []Job
+	schedules map[string]string
+}
+
+func NewRegistry(schedules map[string]string) *Registry {
+	return &Registry{
+		schedules: schedules,
+	}
+}
+
+// Register adds the job, replacing a previously registered job with the same name.
+func (r *Registry) Register(job Job) {
+	for i, registered := range r.jobs {
+		if registered.Name() == job.Name() {
+			r.jobs[i] = job
+			return
+		}
+	}
+
+	r.jobs = append(r.jobs, job)
+}
+
+// Job returns the registered job with the given name.
+func (r *Registry) Job(name string) (Job, bool) {
+	for _, job := range r.jobs {
+		if job.Name() == name {
+			return job, true
+		}
+	}
+
+	return nil, false
+}
+
+// Entry is a registered job with its effective schedule.
+type Entry struct {
+	Job      Job
+	Schedule string
+	Next     time.Time
+}
+
+// Entries returns the registered jobs with their effective schedule and next run after now.
+// It fails on the first invalid cron expression.
+func (r *Registry) Entries(now time.Time) ([]Entry, error) {
+	entries := make([]Entry, 0, len(r.jobs))
+	for _, job := range r.jobs {
+		expr := job.Schedule()
+		if override, ok := r.schedules[job.Name()]; ok {
+			expr = override
+		}
+
+		schedule, err := cron.ParseStandard(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q of job %s: %w", expr, job.Name(), err)
+		}
+
+		entries = append(entries, Entry{
+			Job:      job,
+			Schedule: expr,
+			Next:     schedule.Next(now),
+		})
+	}
+
+	return entries, nil
+}
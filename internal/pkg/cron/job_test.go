@@ -0,0 +1,54 @@
+package cron
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testJob struct {
+	name     string
+	schedule string
+	summary  map[string]int
+	err      error
+}
+
+func (j *testJob) Name() string           { return j.name }
+func (j *testJob) Schedule() string       { return j.schedule }
+func (j *testJob) Timeout() time.Duration { return time.Minute }
+func (j *testJob) Run(ctx context.Context) (map[string]int, error) {
+	return j.summary, j.err
+}
+
+func TestRegistry_Entries(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 0, 30, 0, time.UTC)
+
+	registry := NewRegistry(map[string]string{"cleanup": "0 3 * * *"})
+	registry.Register(&testJob{name: "resolver", schedule: "* * * * *"})
+	registry.Register(&testJob{name: "cleanup", schedule: "0 * * * *"})
+	registry.Register(&testJob{name: "resolver", schedule: "*/5 * * * *"})
+
+	entries, err := registry.Entries(now)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, "resolver", entries[0].Job.Name())
+	assert.Equal(t, "*/5 * * * *", entries[0].Schedule)
+	assert.Equal(t, time.Date(2024, 1, 1, 10, 5, 0, 0, time.UTC), entries[0].Next)
+
+	assert.Equal(t, "cleanup", entries[1].Job.Name())
+	assert.Equal(t, "0 3 * * *", entries[1].Schedule)
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC), entries[1].Next)
+}
+
+func TestRegistry_Entries_InvalidSchedule(t *testing.T) {
+	registry := NewRegistry(map[string]string{"resolver": "every minute"})
+	registry.Register(&testJob{name: "resolver", schedule: "* * * * *"})
+
+	entries, err := registry.Entries(time.Now())
+	assert.ErrorContains(t, err, `invalid schedule "every minute" of job resolver`)
+	assert.Nil(t, entries)
+}
@@ -0,0 +1,177 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"integration-go/internal/pkg/postgres"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+var ErrLockNotAcquired = errors.New("lock is held by another replica")
+
+// acquireScript sets the lock when it is free and increments the fencing token of the job,
+// so every holder gets a token greater than the previous ones. The token never falls below
+// the current time in milliseconds, so it keeps growing even when the counter is evicted
+// or Redis loses its data.
+var acquireScript = redis.NewScript(`
+if not redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return 0
+end
+local token = redis.call("INCR", KEYS[2])
+local now = redis.call("TIME")
+local floor = tonumber(now[1]) * 1000 + math.floor(tonumber(now[2]) / 1000)
+if token < floor then
+	token = floor
+	redis.call("SET", KEYS[2], token)
+end
+return token`)
+
+// refreshScript extends the lease only when the lock is still held by the caller and no
+// newer holder has been fenced in.
+var refreshScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] and redis.call("GET", KEYS[2]) == ARGV[3] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// Locker makes sure a scheduled job runs on a single replica at a time.
+type Locker struct {
+	rdb *redis.Client
+	ttl time.Duration
+}
+
+func NewLocker(rdb *redis.Client, ttl time.Duration) *Locker {
+	return &Locker{
+		rdb: rdb,
+		ttl: ttl,
+	}
+}
+
+// Lock is a lease on a job. Token is the fencing token of the lease, strictly greater
+// than the token of any previous holder of the same job.
+type Lock struct {
+	locker *Locker
+	key    string
+	fence  string
+	owner  string
+	Token  int64
+}
+
+func lockKey(name string) string {
+	return fmt.Sprintf("cron:lock:%s", name)
+}
+
+// Acquire takes the lock of the job, or returns ErrLockNotAcquired when another replica
+// holds it.
+func (l *Locker) Acquire(ctx context.Context, name string) (*Lock, error) {
+	key := lockKey(name)
+	lock := &Lock{
+		locker: l,
+		key:    key,
+		fence:  key + ":fence",
+		owner:  uuid.New().String(),
+	}
+
+	token, err := acquireScript.Run(ctx, l.rdb, []string{lock.key, lock.fence}, lock.owner, l.ttl.Milliseconds()).Int64()
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+
+	if token == 0 {
+		return nil, ErrLockNotAcquired
+	}
+
+	lock.Token = token
+	return lock, nil
+}
+
+// Refresh extends the lease, or returns ErrLockNotAcquired when the lock has been lost,
+// e.g. it expired while the process was paused and another replica took it.
+func (lk *Lock) Refresh(ctx context.Context) error {
+	ok, err := refreshScript.Run(ctx, lk.locker.rdb, []string{lk.key, lk.fence}, lk.owner, lk.locker.ttl.Milliseconds(), lk.Token).Int64()
+	if err != nil {
+		return fmt.Errorf("failed to refresh lock: %w", err)
+	}
+
+	if ok == 0 {
+		return ErrLockNotAcquired
+	}
+
+	return nil
+}
+
+func (lk *Lock) Release(ctx context.Context) error {
+	err := releaseScript.Run(ctx, lk.locker.rdb, []string{lk.key}, lk.owner).Err()
+	if err != nil {
+		return fmt.Errorf("failed to release lock: %w", err)
+	}
+
+	return nil
+}
+
+// Run calls fn while holding the lock of the job, and skips it when another replica holds
+// the lock. The lease is renewed every third of its TTL; when it cannot be renewed the
+// context passed to fn is canceled, so fn stops before another replica takes over. The
+// context also carries the fencing token, so the writes of fn checking it with
+// postgres.CheckFencingToken are rejected once a newer holder has written.
+func (l *Locker) Run(ctx context.Context, name string, fn func(ctx context.Context) error) error {
+	lock, err := l.Acquire(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := lock.Release(context.Background()); err != nil {
+			log.Ctx(ctx).Error().Msg(err.Error())
+		}
+	}()
+
+	// The lease is renewed apart from the job, so a job reaching its own timeout is not
+	// mistaken for a lost lock
+	renewCtx := context.WithoutCancel(ctx)
+
+	ctx, cancel := context.WithCancel(postgres.WithFencingToken(ctx, lock.key, lock.Token))
+	defer cancel()
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		ticker := time.NewTicker(l.ttl / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := l.refresh(renewCtx, lock); err != nil {
+					log.Ctx(renewCtx).Error().Str("job", name).Msgf("lost cron lock: %s", err.Error())
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	return fn(ctx)
+}
+
+// refresh renews the lease, giving up once the lease would have expired anyway.
+func (l *Locker) refresh(ctx context.Context, lock *Lock) error {
+	ctx, cancel := context.WithTimeout(ctx, l.ttl/3)
+	defer cancel()
+
+	return lock.Refresh(ctx)
+}
@@ -0,0 +1,153 @@
+package cron
+
+import (
+	"context"
+	"integration-go/internal/pkg/postgres"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLocker(t *testing.T, ttl time.Duration) (*Locker, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	return NewLocker(rdb, ttl), mr
+}
+
+func TestLocker_Acquire(t *testing.T) {
+	locker, mr := newTestLocker(t, time.Minute)
+	ctx := context.Background()
+
+	first, err := locker.Acquire(ctx, "resolver")
+	require.NoError(t, err)
+	assert.Positive(t, first.Token)
+
+	_, err = locker.Acquire(ctx, "resolver")
+	assert.ErrorIs(t, err, ErrLockNotAcquired)
+
+	// Another job has its own lock
+	_, err = locker.Acquire(ctx, "cleanup")
+	require.NoError(t, err)
+
+	// The lease expires when the holder dies, and the next holder gets a greater token
+	mr.FastForward(2 * time.Minute)
+
+	second, err := locker.Acquire(ctx, "resolver")
+	require.NoError(t, err)
+	assert.Greater(t, second.Token, first.Token)
+
+	// The previous holder can neither renew nor release the lock of the new holder
+	assert.ErrorIs(t, first.Refresh(ctx), ErrLockNotAcquired)
+	assert.NoError(t, first.Release(ctx))
+	assert.True(t, mr.Exists("cron:lock:resolver"))
+
+	assert.NoError(t, second.Refresh(ctx))
+	assert.NoError(t, second.Release(ctx))
+	assert.False(t, mr.Exists("cron:lock:resolver"))
+}
+
+func TestLocker_Acquire_FencingTokenAfterDataLoss(t *testing.T) {
+	locker, mr := newTestLocker(t, time.Minute)
+	ctx := context.Background()
+
+	first, err := locker.Acquire(ctx, "resolver")
+	require.NoError(t, err)
+	require.NoError(t, first.Release(ctx))
+
+	// The counter is lost, e.g. evicted, the next token still grows
+	mr.Del("cron:lock:resolver:fence")
+	time.Sleep(2 * time.Millisecond)
+
+	second, err := locker.Acquire(ctx, "resolver")
+	require.NoError(t, err)
+	assert.Greater(t, second.Token, first.Token)
+}
+
+func TestLock_Refresh_Fenced(t *testing.T) {
+	locker, mr := newTestLocker(t, time.Minute)
+	ctx := context.Background()
+
+	lock, err := locker.Acquire(ctx, "resolver")
+	require.NoError(t, err)
+
+	// A newer holder was fenced in while the lease still reads as ours
+	mr.Set("cron:lock:resolver:fence", strconv.FormatInt(lock.Token+1, 10))
+
+	assert.ErrorIs(t, lock.Refresh(ctx), ErrLockNotAcquired)
+}
+
+func TestLocker_Run(t *testing.T) {
+	t.Run("run while holding the lock", func(t *testing.T) {
+		locker, mr := newTestLocker(t, time.Minute)
+
+		err := locker.Run(context.Background(), "resolver", func(ctx context.Context) error {
+			token, ok := postgres.FencingTokenFrom(ctx)
+			assert.True(t, ok)
+			assert.Equal(t, "cron:lock:resolver", token.Name)
+			assert.Positive(t, token.Token)
+			assert.True(t, mr.Exists("cron:lock:resolver"))
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.False(t, mr.Exists("cron:lock:resolver"))
+	})
+
+	t.Run("skip when another replica holds the lock", func(t *testing.T) {
+		locker, _ := newTestLocker(t, time.Minute)
+
+		_, err := locker.Acquire(context.Background(), "resolver")
+		require.NoError(t, err)
+
+		called := false
+		err = locker.Run(context.Background(), "resolver", func(ctx context.Context) error {
+			called = true
+			return nil
+		})
+
+		assert.ErrorIs(t, err, ErrLockNotAcquired)
+		assert.False(t, called)
+	})
+
+	t.Run("cancel when the lock is lost", func(t *testing.T) {
+		locker, mr := newTestLocker(t, 30*time.Millisecond)
+
+		err := locker.Run(context.Background(), "resolver", func(ctx context.Context) error {
+			// Another replica takes over the lock
+			mr.Set("cron:lock:resolver", "other")
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Second):
+				return nil
+			}
+		})
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("keep the lock when the job times out", func(t *testing.T) {
+		locker, mr := newTestLocker(t, 30*time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+
+		err := locker.Run(ctx, "resolver", func(ctx context.Context) error {
+			<-ctx.Done()
+			mr.SetTTL("cron:lock:resolver", time.Millisecond)
+
+			// The lease is still renewed while the job returns
+			time.Sleep(50 * time.Millisecond)
+			assert.Equal(t, 30*time.Millisecond, mr.TTL("cron:lock:resolver"))
+			return ctx.Err()
+		})
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
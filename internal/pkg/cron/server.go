@@ -2,53 +2,394 @@ package cron
 
 import (
 	"context"
-	"integration-go/internal/resolver"
-	"integration-go/internal/room"
+	"errors"
+	"fmt"
+	"integration-go/internal/deadletter"
+	"integration-go/internal/entity"
+	"integration-go/internal/jobrun"
 	"integration-go/internal/pkg/client"
 	"integration-go/internal/pkg/config"
+	"integration-go/internal/pkg/metrics"
 	"integration-go/internal/pkg/postgres"
 	"integration-go/internal/pkg/qismo"
+	"integration-go/internal/pkg/redis"
+	"integration-go/internal/pkg/tracing"
+	"integration-go/internal/resolver"
+	"integration-go/internal/room"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"text/tabwriter"
 	"time"
 
 	"github.com/go-co-op/gocron"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func NewServer() *Server {
 	cfg := config.Load()
 
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, "cron")
+	if err != nil {
+		log.Fatal().Msgf("unable to set up tracing: %s", err.Error())
+	}
+
 	db := postgres.NewGORM(cfg.Database)
+	postgres.CheckSchema(db)
+	rdb := redis.New(cfg.Redis.URL)
+
+	client := client.NewFromConfig(cfg.Client)
+	qismo := qismo.New(client, cfg.Qiscus.Omnichannel.URL, cfg.Qiscus.SDKURL, cfg.Qiscus.AppID, cfg.Qiscus.SecretKey)
+	qismo.SetRateLimit(cfg.Qiscus.RateLimit, cfg.Qiscus.RateBurst)
 
-	client := client.New()
-	qismo := qismo.New(client, cfg.Qiscus.Omnichannel.URL, cfg.Qiscus.AppID, cfg.Qiscus.SecretKey)
+	registry := NewRegistry(cfg.Cron.Schedules)
 
+	// Resolver
 	roomRepo := room.NewRepository(db)
-	resolverSvc := resolver.NewService(roomRepo, qismo)
+	deadLetterRepo := deadletter.NewRepository(db, cfg.Worker.MaxAttempts)
+	resolverPolicy, err := resolver.NewPolicy(cfg.Resolver)
+	if err != nil {
+		log.Fatal().Msgf("unable to create resolver policy: %s", err.Error())
+	}
+
+	resolverSvc := resolver.NewService(roomRepo, qismo, deadLetterRepo, resolver.NewRepository(rdb), resolverPolicy)
+	registry.Register(resolver.NewCronJob(resolverSvc))
+
+	// Job runs
+	jobRunRepo := jobrun.NewRepository(db)
+	control := NewControl(rdb)
+	jobRunSvc := jobrun.NewService(jobRunRepo, control, cfg.Cron.HealthWindow, cfg.Cron.HealthWindows, cfg.Cron.RunRetention)
+	registry.Register(jobrun.NewCronJob(jobRunSvc))
+
+	ctx, cancel := context.WithCancel(context.Background())
 
 	return &Server{
-		svc: resolverSvc,
+		registry: registry,
+		locker:   NewLocker(rdb, cfg.Cron.LockTTL),
+		control:  control,
+		recorder: jobRunRepo,
+		ctx:      ctx,
+		cancel:   cancel,
+
+		shutdownTracing:     shutdownTracing,
+		triggerPollInterval: cfg.Cron.TriggerPollInterval,
+		shutdownTimeout:     cfg.Cron.ShutdownTimeout,
+		metricsPort:         cfg.Cron.MetricsPort,
 	}
 }
 
+// shutdownCancelGrace is how long the shutdown waits for the jobs to return once their
+// context is canceled.
+const shutdownCancelGrace = 5 * time.Second
+
+// Recorder persists the runs of the jobs.
+type Recorder interface {
+	Create(ctx context.Context, run *entity.JobRun) error
+	Save(ctx context.Context, run *entity.JobRun) error
+}
+
 type Server struct {
-	svc *resolver.Service
+	registry *Registry
+	locker   *Locker
+	control  *Control
+	recorder Recorder
+
+	// ctx is the parent of the context of every run, canceled when the shutdown times out.
+	ctx    context.Context
+	cancel context.CancelFunc
+	// triggered tracks the manually triggered runs, which are not run by the scheduler.
+	triggered sync.WaitGroup
+
+	triggerPollInterval time.Duration
+	shutdownTimeout     time.Duration
+	// metricsPort is the port of the metrics listener, zero disables it.
+	metricsPort int
+	// shutdownTracing flushes the pending spans.
+	shutdownTracing func(context.Context) error
 }
 
-// Run starts the cron job and schedules it to execute every minute.
+// Run schedules every registered job and blocks until SIGINT or SIGTERM is received and
+// the in-flight runs have finished. A tick of a job is skipped while the job is paused,
+// its previous run is still in progress, or another replica runs it.
 func (c *Server) Run() {
-	log.Info().Msg("cron is started")
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	entries, err := c.registry.Entries(time.Now())
+	if err != nil {
+		log.Fatal().Msgf("unable to schedule cron jobs: %s", err.Error())
+	}
+
+	if err := c.control.Announce(context.Background(), entries); err != nil {
+		log.Fatal().Msgf("unable to announce cron jobs: %s", err.Error())
+	}
 
 	s := gocron.NewScheduler(time.UTC)
-	s.Every(60).Second().Do(func() {
-		reqID := uuid.New().String()
-		ctx := log.With().Str("request_id", reqID).Logger().WithContext(context.Background())
+	s.SingletonModeAll()
 
-		err := c.svc.ResolvedOmnichannelRoom(ctx)
+	for _, entry := range entries {
+		_, err := s.Cron(entry.Schedule).Tag(entry.Job.Name()).Do(c.runJob, entry.Job)
 		if err != nil {
-			log.Ctx(ctx).Error().Msgf("error handle resolved room: %s", err.Error())
+			log.Fatal().Msgf("unable to schedule cron job %s: %s", entry.Job.Name(), err.Error())
+		}
+
+		log.Info().Str("job", entry.Job.Name()).Str("schedule", entry.Schedule).Msg("cron job scheduled")
+	}
+
+	_, err = s.Every(c.triggerPollInterval).Do(c.pollTriggers, entries)
+	if err != nil {
+		log.Fatal().Msgf("unable to schedule cron triggers: %s", err.Error())
+	}
+
+	metricsSrv := c.serveMetrics()
+
+	s.StartAsync()
+	log.Info().Msg("cron is started")
+
+	<-ctx.Done()
+	log.Info().Msg("cron is shuting down...")
+
+	c.shutdown(s)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownCancelGrace)
+	defer cancel()
+
+	if err := c.shutdownTracing(shutdownCtx); err != nil {
+		log.Error().Msgf("failed to flush traces: %s", err.Error())
+	}
+
+	// The metrics stay available until the last runs are recorded
+	if metricsSrv != nil {
+		if err := metricsSrv.Shutdown(shutdownCtx); err != nil {
+			log.Error().Msgf("failed to shut down metrics listener: %s", err.Error())
+		}
+	}
+
+	log.Info().Msg("cron stopped")
+}
+
+// serveMetrics starts the metrics listener in the background, unless it is disabled. A
+// listener that cannot start is logged, the jobs still run.
+func (c *Server) serveMetrics() *http.Server {
+	if c.metricsPort == 0 {
+		return nil
+	}
+
+	srv := metrics.NewServer(c.metricsPort)
+	go func() {
+		log.Info().Msgf("cron metrics serving on port %d", c.metricsPort)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error().Msgf("failed to serve cron metrics: %s", err.Error())
 		}
+	}()
+
+	return srv
+}
+
+// shutdown stops scheduling new runs and waits for the in-flight runs. Runs still going
+// after the shutdown timeout have their context canceled, and are given a short grace to
+// stop before the process exits anyway.
+func (c *Server) shutdown(s *gocron.Scheduler) {
+	stopped := make(chan struct{})
+	go func() {
+		// Stop unschedules the jobs first, then waits for the runs in flight
+		s.Stop()
+		c.triggered.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return
+	case <-time.After(c.shutdownTimeout):
+	}
+
+	log.Warn().Msgf("cron jobs still running after %s, canceling them", c.shutdownTimeout)
+	c.cancel()
+
+	select {
+	case <-stopped:
+	case <-time.After(shutdownCancelGrace):
+		log.Error().Msg("cron jobs did not stop after being canceled")
+	}
+}
+
+// runJob runs a scheduled tick of the job, unless the job is paused. When the pause state
+// cannot be read the tick runs, so a Redis hiccup does not stop the jobs.
+func (c *Server) runJob(job Job) {
+	paused, err := c.control.IsPaused(context.Background(), job.Name())
+	if err != nil {
+		log.Error().Str("job", job.Name()).Msg(err.Error())
+	}
+
+	if paused {
+		log.Debug().Str("job", job.Name()).Msg("skip cron job, paused")
+		metrics.CronJobSkips.WithLabelValues(job.Name(), "paused").Inc()
+		return
+	}
+
+	c.execute(job, uuid.New().String())
+}
+
+// pollTriggers runs the jobs manually triggered since the last poll. Triggered runs ignore
+// the pause, and run in the background so a long job does not delay the others.
+func (c *Server) pollTriggers(entries []Entry) {
+	ctx := context.Background()
+	for _, entry := range entries {
+		reqID, err := c.control.TakeTrigger(ctx, entry.Job.Name())
+		if err != nil {
+			log.Error().Str("job", entry.Job.Name()).Msg(err.Error())
+			continue
+		}
+
+		if reqID == "" {
+			continue
+		}
+
+		log.Info().Str("request_id", reqID).Str("job", entry.Job.Name()).Msg("cron job triggered")
+
+		c.triggered.Add(1)
+		go func(job Job) {
+			defer c.triggered.Done()
+			c.execute(job, reqID)
+		}(entry.Job)
+	}
+}
+
+// execute runs the job once under its lock, with the given request ID.
+func (c *Server) execute(job Job, reqID string) error {
+	ctx := context.WithValue(c.ctx, config.RequestIDKey, reqID)
+	ctx = log.With().Str("request_id", reqID).Str("job", job.Name()).Logger().WithContext(ctx)
+
+	if timeout := job.Timeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	err := c.locker.Run(ctx, job.Name(), func(ctx context.Context) error {
+		return c.run(ctx, job, reqID)
 	})
+	if errors.Is(err, ErrLockNotAcquired) {
+		log.Ctx(ctx).Debug().Msg("skip cron job, held by another replica")
+		metrics.CronJobSkips.WithLabelValues(job.Name(), "locked").Inc()
+		return err
+	}
+
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("error run cron job: %s", err.Error())
+	}
+
+	return err
+}
+
+// run runs the job in the root span of a new trace and records the run. A run that cannot
+// be recorded is still run.
+func (c *Server) run(ctx context.Context, job Job, reqID string) error {
+	ctx, span := tracing.Tracer().Start(ctx, "cron "+job.Name(),
+		trace.WithNewRoot(),
+		trace.WithAttributes(
+			attribute.String("job", job.Name()),
+			attribute.String("request_id", reqID),
+		),
+	)
+	defer span.End()
+
+	if traceID := tracing.TraceID(ctx); traceID != "" {
+		ctx = log.Ctx(ctx).With().Str("trace_id", traceID).Logger().WithContext(ctx)
+	}
+
+	run := &entity.JobRun{
+		Job:       job.Name(),
+		RequestID: reqID,
+		TraceID:   tracing.TraceID(ctx),
+		Status:    entity.JobRunStatusRunning,
+		StartedAt: time.Now(),
+	}
+
+	if err := c.recorder.Create(ctx, run); err != nil {
+		log.Ctx(ctx).Error().Msgf("failed to record job run: %s", err.Error())
+	}
+
+	summary, err := job.Run(ctx)
+	run.Finish(summary, err, time.Now())
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	metrics.CronJobRuns.WithLabelValues(job.Name(), string(run.Status)).Inc()
+	metrics.CronJobDuration.WithLabelValues(job.Name(), string(run.Status)).Observe(run.FinishedAt.Sub(run.StartedAt).Seconds())
+	if run.Status == entity.JobRunStatusSucceeded {
+		metrics.CronJobLastSuccess.WithLabelValues(job.Name()).Set(float64(run.FinishedAt.Unix()))
+	}
+
+	log.Ctx(ctx).Info().
+		Str("status", string(run.Status)).
+		Int64("duration_ms", run.DurationMs).
+		Interface("summary", summary).
+		Msg("cron job finished")
+
+	// The run may have timed out, its outcome is still recorded
+	if err := c.recorder.Save(context.WithoutCancel(ctx), run); err != nil {
+		log.Ctx(ctx).Error().Msgf("failed to record job run: %s", err.Error())
+	}
+
+	return err
+}
+
+// Trigger runs the registered job once in this process, ignoring the pause. It fails with
+// ErrLockNotAcquired when the job is already running.
+func (c *Server) Trigger(name string) error {
+	job, ok := c.registry.Job(name)
+	if !ok {
+		return fmt.Errorf("job %s is not registered", name)
+	}
+
+	return c.execute(job, uuid.New().String())
+}
+
+// Pause stops the scheduled ticks of the registered job on every replica until it is
+// resumed. A run in progress is not interrupted.
+func (c *Server) Pause(ctx context.Context, name string) error {
+	if _, ok := c.registry.Job(name); !ok {
+		return fmt.Errorf("job %s is not registered", name)
+	}
+
+	return c.control.Pause(ctx, name)
+}
+
+func (c *Server) Resume(ctx context.Context, name string) error {
+	if _, ok := c.registry.Job(name); !ok {
+		return fmt.Errorf("job %s is not registered", name)
+	}
+
+	return c.control.Resume(ctx, name)
+}
+
+// List prints the registered jobs with their schedule, next run time and pause state.
+func (c *Server) List(w io.Writer) error {
+	entries, err := c.registry.Entries(time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tSCHEDULE\tTIMEOUT\tNEXT RUN\tPAUSED")
+	for _, entry := range entries {
+		paused, err := c.control.IsPaused(context.Background(), entry.Job.Name())
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%t\n", entry.Job.Name(), entry.Schedule, entry.Job.Timeout(), entry.Next.Format(time.RFC3339), paused)
+	}
 
-	s.StartBlocking()
+	return tw.Flush()
 }
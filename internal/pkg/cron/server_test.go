@@ -0,0 +1,200 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"integration-go/internal/entity"
+	"integration-go/internal/pkg/metrics"
+	"testing"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+type testRecorder struct {
+	created []entity.JobRun
+	saved   []entity.JobRun
+	err     error
+}
+
+func (r *testRecorder) Create(ctx context.Context, run *entity.JobRun) error {
+	r.created = append(r.created, *run)
+	return r.err
+}
+
+func (r *testRecorder) Save(ctx context.Context, run *entity.JobRun) error {
+	r.saved = append(r.saved, *run)
+	return r.err
+}
+
+func TestServer_Run(t *testing.T) {
+	t.Run("record succeeded run", func(t *testing.T) {
+		recorder := &testRecorder{}
+		server := &Server{recorder: recorder}
+		job := &testJob{name: "resolver", summary: map[string]int{"resolved": 2}}
+		runs := testutil.ToFloat64(metrics.CronJobRuns.WithLabelValues("resolver", "succeeded"))
+
+		err := server.run(context.Background(), job, "req-1")
+		require.NoError(t, err)
+
+		assert.Equal(t, runs+1, testutil.ToFloat64(metrics.CronJobRuns.WithLabelValues("resolver", "succeeded")))
+		assert.Positive(t, testutil.ToFloat64(metrics.CronJobLastSuccess.WithLabelValues("resolver")))
+
+		require.Len(t, recorder.created, 1)
+		assert.Equal(t, "resolver", recorder.created[0].Job)
+		assert.Equal(t, "req-1", recorder.created[0].RequestID)
+		assert.Equal(t, entity.JobRunStatusRunning, recorder.created[0].Status)
+
+		require.Len(t, recorder.saved, 1)
+		assert.Equal(t, entity.JobRunStatusSucceeded, recorder.saved[0].Status)
+		assert.NotNil(t, recorder.saved[0].FinishedAt)
+		assert.JSONEq(t, `{"resolved":2}`, string(recorder.saved[0].Summary))
+		assert.Empty(t, recorder.saved[0].Error)
+	})
+
+	t.Run("record failed run", func(t *testing.T) {
+		recorder := &testRecorder{}
+		server := &Server{recorder: recorder}
+		job := &testJob{name: "resolver", err: errors.New("failed to fetch rooms")}
+		runs := testutil.ToFloat64(metrics.CronJobRuns.WithLabelValues("resolver", "failed"))
+
+		err := server.run(context.Background(), job, "req-1")
+		assert.EqualError(t, err, "failed to fetch rooms")
+		assert.Equal(t, runs+1, testutil.ToFloat64(metrics.CronJobRuns.WithLabelValues("resolver", "failed")))
+
+		require.Len(t, recorder.saved, 1)
+		assert.Equal(t, entity.JobRunStatusFailed, recorder.saved[0].Status)
+		assert.Equal(t, "failed to fetch rooms", recorder.saved[0].Error)
+		assert.Nil(t, recorder.saved[0].Summary)
+	})
+
+	t.Run("run in root span", func(t *testing.T) {
+		spanRecorder := tracetest.NewSpanRecorder()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder))
+		otel.SetTracerProvider(provider)
+		t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+		recorder := &testRecorder{}
+		server := &Server{recorder: recorder}
+		job := &testJob{name: "resolver", err: errors.New("failed to fetch rooms")}
+
+		// A run never joins the trace of the context it was started from
+		ctx, parent := provider.Tracer("test").Start(context.Background(), "scheduler")
+		defer parent.End()
+
+		err := server.run(ctx, job, "req-1")
+		assert.Error(t, err)
+
+		spans := spanRecorder.Ended()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "cron resolver", spans[0].Name())
+		assert.False(t, spans[0].Parent().IsValid())
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+
+		require.Len(t, recorder.saved, 1)
+		assert.Equal(t, spans[0].SpanContext().TraceID().String(), recorder.saved[0].TraceID)
+	})
+
+	t.Run("run job when recording fails", func(t *testing.T) {
+		recorder := &testRecorder{err: errors.New("connection refused")}
+		server := &Server{recorder: recorder}
+		job := &testJob{name: "resolver"}
+
+		err := server.run(context.Background(), job, "req-1")
+		assert.NoError(t, err)
+		assert.Len(t, recorder.saved, 1)
+	})
+}
+
+func TestServer_RunJob_Paused(t *testing.T) {
+	control, _ := newTestControl(t)
+	recorder := &testRecorder{}
+	server := &Server{control: control, recorder: recorder}
+	job := &testJob{name: "resolver"}
+
+	require.NoError(t, control.Pause(context.Background(), "resolver"))
+	skips := testutil.ToFloat64(metrics.CronJobSkips.WithLabelValues("resolver", "paused"))
+
+	server.runJob(job)
+	assert.Empty(t, recorder.created)
+	assert.Equal(t, skips+1, testutil.ToFloat64(metrics.CronJobSkips.WithLabelValues("resolver", "paused")))
+}
+
+type blockingJob struct {
+	testJob
+	duration time.Duration
+	canceled chan bool
+}
+
+// Run waits for duration, or until ctx is canceled, and reports which happened first.
+func (j *blockingJob) Run(ctx context.Context) (map[string]int, error) {
+	select {
+	case <-time.After(j.duration):
+		j.canceled <- false
+	case <-ctx.Done():
+		j.canceled <- true
+	}
+
+	return nil, ctx.Err()
+}
+
+func newTestServer(t *testing.T, shutdownTimeout time.Duration) *Server {
+	locker, mr := newTestLocker(t, time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Server{
+		locker:          locker,
+		control:         NewControl(redis.NewClient(&redis.Options{Addr: mr.Addr()})),
+		recorder:        &testRecorder{},
+		ctx:             ctx,
+		cancel:          cancel,
+		shutdownTimeout: shutdownTimeout,
+	}
+}
+
+func TestServer_Shutdown(t *testing.T) {
+	t.Run("wait for in-flight run", func(t *testing.T) {
+		server := newTestServer(t, time.Second)
+		job := &blockingJob{testJob: testJob{name: "resolver"}, duration: 100 * time.Millisecond, canceled: make(chan bool, 1)}
+
+		s := gocron.NewScheduler(time.UTC)
+		_, err := s.Every(time.Hour).StartImmediately().Do(server.runJob, job)
+		require.NoError(t, err)
+		s.StartAsync()
+		time.Sleep(20 * time.Millisecond)
+
+		server.shutdown(s)
+		assert.False(t, <-job.canceled)
+		assert.NoError(t, server.ctx.Err())
+	})
+
+	t.Run("cancel run after shutdown timeout", func(t *testing.T) {
+		server := newTestServer(t, 50*time.Millisecond)
+		job := &blockingJob{testJob: testJob{name: "resolver"}, duration: time.Minute, canceled: make(chan bool, 1)}
+
+		server.triggered.Add(1)
+		go func() {
+			defer server.triggered.Done()
+			server.execute(job, "req-1")
+		}()
+		time.Sleep(20 * time.Millisecond)
+
+		s := gocron.NewScheduler(time.UTC)
+		s.StartAsync()
+
+		start := time.Now()
+		server.shutdown(s)
+		assert.True(t, <-job.canceled)
+		assert.Less(t, time.Since(start), shutdownCancelGrace)
+	})
+}
@@ -0,0 +1,204 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"integration-go/internal/pkg/api/resp"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	keyPrefix     = "webhook:delivery:"
+	pendingMarker = "pending"
+	// pendingTTL is how long the marker of a delivery in progress outlives the replica
+	// processing it. The marker is refreshed every third of it while the handler runs, so
+	// a slow delivery keeps its marker and a crashed replica releases it quickly.
+	pendingTTL     = 30 * time.Second
+	ReplayedHeader = "Idempotent-Replayed"
+)
+
+// outcome is the response of the first successful delivery, stored in redis
+// so it can be replayed to retries of the same delivery.
+type outcome struct {
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
+
+type middleware struct {
+	rdb        *redis.Client
+	ttl        time.Duration
+	pendingTTL time.Duration
+}
+
+func NewMiddleware(rdb *redis.Client, ttl time.Duration) *middleware {
+	return &middleware{
+		rdb:        rdb,
+		ttl:        ttl,
+		pendingTTL: pendingTTL,
+	}
+}
+
+// Deduplicate makes a webhook handler idempotent. Every delivery is identified by a key
+// derived from its method, path, query and raw body, so a retried delivery maps to the same key.
+// The first successful outcome is replayed to duplicates, while failed deliveries are
+// forgotten so the sender's retry is processed again. When redis is unavailable the
+// request is passed through and the repository is expected to absorb duplicates.
+func (m *middleware) Deduplicate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var body []byte
+		if r.Body != nil {
+			var err error
+			body, err = io.ReadAll(r.Body)
+			if err != nil {
+				resp.WriteJSONFromError(w, err)
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewBuffer(body))
+		}
+
+		key := DeliveryKey(r.Method, r.URL.RequestURI(), body)
+
+		acquired, err := m.rdb.SetNX(ctx, key, pendingMarker, m.pendingTTL).Result()
+		if err != nil {
+			log.Ctx(ctx).Warn().Msgf("failed to acquire delivery key, skipping deduplication: %s", err.Error())
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !acquired {
+			m.replay(w, r, key)
+			return
+		}
+
+		rec := &recorder{ResponseWriter: w, statusCode: http.StatusOK}
+		stop := m.keepPending(ctx, key)
+		next.ServeHTTP(rec, r)
+		stop()
+
+		if rec.statusCode < 200 || rec.statusCode >= 300 {
+			if err := m.rdb.Del(ctx, key).Err(); err != nil {
+				log.Ctx(ctx).Warn().Msgf("failed to release delivery key: %s", err.Error())
+			}
+			return
+		}
+
+		data, _ := json.Marshal(outcome{
+			StatusCode:  rec.statusCode,
+			ContentType: rec.Header().Get("Content-Type"),
+			Body:        rec.body.Bytes(),
+		})
+
+		if err := m.rdb.Set(ctx, key, data, m.ttl).Err(); err != nil {
+			log.Ctx(ctx).Warn().Msgf("failed to store delivery outcome: %s", err.Error())
+		}
+	})
+}
+
+// keepPending refreshes the pending marker of key until the returned function is called.
+// The function waits for the last refresh, so the marker is not refreshed after being
+// replaced by the outcome.
+func (m *middleware) keepPending(ctx context.Context, key string) func() {
+	// The handler may keep running after the sender gave up
+	ctx = context.WithoutCancel(ctx)
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(m.pendingTTL / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := m.rdb.Expire(ctx, key, m.pendingTTL).Err(); err != nil {
+					log.Ctx(ctx).Warn().Msgf("failed to refresh delivery key: %s", err.Error())
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+func (m *middleware) replay(w http.ResponseWriter, r *http.Request, key string) {
+	ctx := r.Context()
+
+	val, err := m.rdb.Get(ctx, key).Bytes()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		log.Ctx(ctx).Warn().Msgf("failed to get delivery outcome: %s", err.Error())
+	}
+
+	var out outcome
+	if err != nil || string(val) == pendingMarker || json.Unmarshal(val, &out) != nil {
+		// The original delivery is still being processed, ask the sender to retry later
+		resp.WriteJSON(w, http.StatusConflict, resp.HTTPError{
+			Message:   "Delivery is being processed",
+			RequestID: w.Header().Get("X-Request-Id"),
+		})
+		return
+	}
+
+	log.Ctx(ctx).Info().Str("delivery_key", key).Msg("duplicate webhook delivery")
+
+	if out.ContentType != "" {
+		w.Header().Set("Content-Type", out.ContentType)
+	}
+	w.Header().Set(ReplayedHeader, "true")
+	w.WriteHeader(out.StatusCode)
+	w.Write(out.Body)
+}
+
+// DeliveryKey derives the redis key that identifies a single webhook delivery. target is
+// the request URI, i.e. the path and the query.
+func DeliveryKey(method, target string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte(" "))
+	h.Write([]byte(target))
+	h.Write([]byte("\n"))
+	h.Write(body)
+
+	return keyPrefix + hex.EncodeToString(h.Sum(nil))
+}
+
+type recorder struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *recorder) WriteHeader(code int) {
+	if !r.wroteHeader {
+		r.statusCode = code
+		r.wroteHeader = true
+		r.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,157 @@
+package idempotency
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestMiddleware(t *testing.T) (*middleware, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	return NewMiddleware(rdb, time.Hour), mr
+}
+
+func send(h http.Handler, body string) *httptest.ResponseRecorder {
+	return sendTo(h, "/wh/qiscus/omnichannel/new-session", body)
+}
+
+func sendTo(h http.Handler, target string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestMiddleware_Deduplicate(t *testing.T) {
+	t.Run("duplicate delivery replays original outcome", func(t *testing.T) {
+		m, _ := newTestMiddleware(t)
+
+		calls := 0
+		h := m.Deduplicate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`"ok"`))
+		}))
+
+		first := send(h, `{"id":1}`)
+		second := send(h, `{"id":1}`)
+
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, http.StatusOK, second.Code)
+		assert.Equal(t, first.Body.String(), second.Body.String())
+		assert.Equal(t, "application/json", second.Header().Get("Content-Type"))
+		assert.Equal(t, "true", second.Header().Get(ReplayedHeader))
+	})
+
+	t.Run("different deliveries are processed", func(t *testing.T) {
+		m, _ := newTestMiddleware(t)
+
+		calls := 0
+		h := m.Deduplicate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		send(h, `{"id":1}`)
+		send(h, `{"id":2}`)
+
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("deliveries with different queries are processed", func(t *testing.T) {
+		m, _ := newTestMiddleware(t)
+
+		calls := 0
+		h := m.Deduplicate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		sendTo(h, "/wh/qiscus/omnichannel/new-session?channel=wa", `{"id":1}`)
+		sendTo(h, "/wh/qiscus/omnichannel/new-session?channel=telegram", `{"id":1}`)
+		sendTo(h, "/wh/qiscus/omnichannel/new-session?channel=wa", `{"id":1}`)
+
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("slow delivery keeps its pending marker", func(t *testing.T) {
+		m, mr := newTestMiddleware(t)
+		m.pendingTTL = 30 * time.Millisecond
+		key := DeliveryKey(http.MethodPost, "/wh/qiscus/omnichannel/new-session", []byte(`{"id":1}`))
+
+		h := m.Deduplicate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Runs for several pending TTLs, the marker is refreshed meanwhile
+			for range 5 {
+				time.Sleep(20 * time.Millisecond)
+				mr.FastForward(20 * time.Millisecond)
+				assert.True(t, mr.Exists(key))
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		send(h, `{"id":1}`)
+
+		// The outcome is stored with its own TTL, not shortened by a late refresh
+		assert.Equal(t, time.Hour, mr.TTL(key))
+	})
+
+	t.Run("failed delivery is processed again", func(t *testing.T) {
+		m, _ := newTestMiddleware(t)
+
+		calls := 0
+		h := m.Deduplicate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if calls == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		first := send(h, `{"id":1}`)
+		second := send(h, `{"id":1}`)
+
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, http.StatusInternalServerError, first.Code)
+		assert.Equal(t, http.StatusOK, second.Code)
+	})
+
+	t.Run("in-flight delivery returns conflict", func(t *testing.T) {
+		m, mr := newTestMiddleware(t)
+		mr.Set(DeliveryKey(http.MethodPost, "/wh/qiscus/omnichannel/new-session", []byte(`{"id":1}`)), pendingMarker)
+
+		calls := 0
+		h := m.Deduplicate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+		}))
+
+		res := send(h, `{"id":1}`)
+
+		assert.Equal(t, 0, calls)
+		assert.Equal(t, http.StatusConflict, res.Code)
+	})
+
+	t.Run("redis down passes through", func(t *testing.T) {
+		m, mr := newTestMiddleware(t)
+		mr.Close()
+
+		calls := 0
+		h := m.Deduplicate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		send(h, `{"id":1}`)
+		send(h, `{"id":1}`)
+
+		assert.Equal(t, 2, calls)
+	})
+}
@@ -0,0 +1,109 @@
+// Package metrics holds the Prometheus collectors of the application, registered in their
+// own registry and exposed in the Prometheus text format by Handler.
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds every collector of the application, plus the Go runtime and process
+// collectors.
+var Registry = newRegistry()
+
+var factory = promauto.With(Registry)
+
+func newRegistry() *prometheus.Registry {
+	r := prometheus.NewRegistry()
+	r.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return r
+}
+
+var (
+	HTTPRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests received, by method, route pattern and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Latency of the HTTP requests received, by method, route pattern and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	ClientRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "client_requests_total",
+		Help: "Outbound HTTP calls, by host, method and status code, or error when no response was received.",
+	}, []string{"host", "method", "status"})
+
+	ClientRequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "client_request_duration_seconds",
+		Help:    "Latency of the outbound HTTP calls including retries, by host, method and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"host", "method", "status"})
+
+	ClientRetries = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "client_retries_total",
+		Help: "Outbound HTTP attempts retried after a failed attempt, by host and method.",
+	}, []string{"host", "method"})
+
+	ClientBreakerState = factory.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "client_breaker_state",
+		Help: "State of the circuit breaker of an outbound host: 0 closed, 1 half-open, 2 open.",
+	}, []string{"host"})
+
+	DBQueryDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Latency of the database queries, by operation, table and status.",
+		Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"operation", "table", "status"})
+
+	CronJobRuns = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "cron_job_runs_total",
+		Help: "Cron job runs, by job and outcome.",
+	}, []string{"job", "status"})
+
+	CronJobDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "cron_job_duration_seconds",
+		Help:    "Duration of the cron job runs, by job and outcome.",
+		Buckets: []float64{.1, .5, 1, 5, 10, 30, 60, 120, 300, 600},
+	}, []string{"job", "status"})
+
+	CronJobSkips = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "cron_job_skips_total",
+		Help: "Scheduled cron job ticks not run, by job and reason: paused or locked by another replica.",
+	}, []string{"job", "reason"})
+
+	CronJobLastSuccess = factory.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cron_job_last_success_timestamp_seconds",
+		Help: "Unix time of the last successful run of the cron job in this process.",
+	}, []string{"job"})
+)
+
+// Handler serves the collectors of Registry in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// NewServer returns an HTTP server exposing Handler on /metrics, for processes without an
+// API server.
+func NewServer(port int) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", Handler())
+
+	return &http.Server{
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+}
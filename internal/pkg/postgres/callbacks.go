@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// registerCallbacks runs before and after every create, query, update, delete, row and raw
+// statement, under callback names prefixed by the name of the plugin. after receives the
+// operation, e.g. "query".
+func registerCallbacks(db *gorm.DB, plugin string, before func(*gorm.DB), after func(operation string) func(*gorm.DB)) error {
+	cb := db.Callback()
+	name := func(when, operation string) string {
+		return plugin + ":" + when + "_" + operation
+	}
+
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register(name("before", "create"), before),
+		cb.Create().After("gorm:create").Register(name("after", "create"), after("create")),
+		cb.Query().Before("gorm:query").Register(name("before", "query"), before),
+		cb.Query().After("gorm:query").Register(name("after", "query"), after("query")),
+		cb.Update().Before("gorm:update").Register(name("before", "update"), before),
+		cb.Update().After("gorm:update").Register(name("after", "update"), after("update")),
+		cb.Delete().Before("gorm:delete").Register(name("before", "delete"), before),
+		cb.Delete().After("gorm:delete").Register(name("after", "delete"), after("delete")),
+		cb.Row().Before("gorm:row").Register(name("before", "row"), before),
+		cb.Row().After("gorm:row").Register(name("after", "row"), after("row")),
+		cb.Raw().Before("gorm:raw").Register(name("before", "raw"), before),
+		cb.Raw().After("gorm:raw").Register(name("after", "raw"), after("raw")),
+	)
+}
+
+// isError reports whether the statement failed. A record not found is not a failure.
+func isError(db *gorm.DB) bool {
+	return db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound)
+}
+
+// tableName returns the table of the statement, raw statements have none.
+func tableName(db *gorm.DB) string {
+	if db.Statement.Table == "" {
+		return "unknown"
+	}
+
+	return db.Statement.Table
+}
@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type fencingTokenKey struct{}
+
+var ErrStaleFencingToken = errors.New("fencing token is stale, the lock is held by a newer holder")
+
+// FencingToken is the token of a lock holder, strictly greater than the token of any
+// previous holder of the lock with the same name.
+type FencingToken struct {
+	Name  string
+	Token int64
+}
+
+// WithFencingToken returns a context whose writes going through CheckFencingToken are
+// rejected once a holder with a greater token of the same lock has written.
+func WithFencingToken(ctx context.Context, name string, token int64) context.Context {
+	return context.WithValue(ctx, fencingTokenKey{}, FencingToken{Name: name, Token: token})
+}
+
+// FencingTokenFrom returns the fencing token of the context, if any.
+func FencingTokenFrom(ctx context.Context) (FencingToken, bool) {
+	token, ok := ctx.Value(fencingTokenKey{}).(FencingToken)
+	return token, ok
+}
+
+// CheckFencingToken records the fencing token of ctx as the latest token of its lock, or
+// returns ErrStaleFencingToken when a greater token has been recorded. It is called in
+// the transaction of the write it guards, and holds the row of the lock until the
+// transaction ends, so a newer holder cannot write in between. Writes without a token,
+// e.g. from the API, are not checked.
+func CheckFencingToken(ctx context.Context, tx *gorm.DB) error {
+	token, ok := FencingTokenFrom(ctx)
+	if !ok {
+		return nil
+	}
+
+	res := tx.Exec(`INSERT INTO fencing_tokens (name, token, updated_at) VALUES (?, ?, now())
+ON CONFLICT (name) DO UPDATE SET token = EXCLUDED.token, updated_at = EXCLUDED.updated_at
+WHERE fencing_tokens.token <= EXCLUDED.token`, token.Name, token.Token)
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return ErrStaleFencingToken
+	}
+
+	return nil
+}
@@ -18,6 +18,14 @@ func NewGORM(c config.Database) *gorm.DB {
 		log.Fatal().Msgf("failed to opening db conn: %s", err.Error())
 	}
 
+	if err := db.Use(NewMetrics()); err != nil {
+		log.Fatal().Msgf("failed to register db metrics: %s", err.Error())
+	}
+
+	if err := db.Use(NewTracing()); err != nil {
+		log.Fatal().Msgf("failed to register db tracing: %s", err.Error())
+	}
+
 	sqlDB, err := db.DB()
 	if err != nil {
 		log.Fatal().Msgf("failed to get db object: %s", err.Error())
@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"integration-go/internal/pkg/metrics"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const metricsStartKey = "metrics:start"
+
+// Metrics is a GORM plugin recording the latency of every query in
+// metrics.DBQueryDuration. A record not found is not counted as an error.
+type Metrics struct{}
+
+func NewMetrics() *Metrics {
+	return &Metrics{}
+}
+
+func (m *Metrics) Name() string {
+	return "metrics"
+}
+
+func (m *Metrics) Initialize(db *gorm.DB) error {
+	return registerCallbacks(db, m.Name(), m.before, m.after)
+}
+
+func (m *Metrics) before(db *gorm.DB) {
+	db.InstanceSet(metricsStartKey, time.Now())
+}
+
+func (m *Metrics) after(operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		v, ok := db.InstanceGet(metricsStartKey)
+		if !ok {
+			return
+		}
+
+		start, ok := v.(time.Time)
+		if !ok {
+			return
+		}
+
+		status := "ok"
+		if isError(db) {
+			status = "error"
+		}
+
+		metrics.DBQueryDuration.WithLabelValues(operation, tableName(db), status).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"integration-go/internal/pkg/metrics"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type metricsTestRow struct {
+	ID   int64
+	Name string
+}
+
+func sampleCount(t *testing.T, operation string) uint64 {
+	observer := metrics.DBQueryDuration.WithLabelValues(operation, "metrics_test_rows", "ok")
+
+	var m dto.Metric
+	require.NoError(t, observer.(prometheus.Metric).Write(&m))
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestMetrics(t *testing.T) {
+	// A dry run builds the statements and runs the callbacks without a database
+	db, err := gorm.Open(postgres.Open("host=localhost dbname=test"), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(NewMetrics()))
+
+	queries, creates := sampleCount(t, "query"), sampleCount(t, "create")
+
+	var rows []metricsTestRow
+	require.NoError(t, db.Find(&rows).Error)
+	require.NoError(t, db.Create(&metricsTestRow{Name: "test"}).Error)
+
+	assert.Equal(t, queries+1, sampleCount(t, "query"))
+	assert.Equal(t, creates+1, sampleCount(t, "create"))
+}
@@ -1,15 +1,406 @@
 package postgres
 
 import (
-	"integration-go/internal/entity"
+	"cmp"
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
 )
 
-func Migrate(db *gorm.DB) {
-	err := db.AutoMigrate(&entity.Room{})
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// MigrationsDir is where migrations are created, relative to the repository root.
+const MigrationsDir = "internal/pkg/postgres/migrations"
+
+// advisoryLockID identifies the migration lock among the advisory locks of the database,
+// so concurrent migrate commands run one after the other.
+const advisoryLockID int64 = 4842117301
+
+var ErrSchemaOutdated = errors.New("database schema is outdated")
+
+var (
+	migrationFileRegexp = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+	migrationNameRegexp = regexp.MustCompile(`^[a-z0-9_]+$`)
+)
+
+// Migration is a versioned schema change, read from a <version>_<name>.up.sql file and its
+// <version>_<name>.down.sql counterpart.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+}
+
+// loadMigrations reads the migrations of fsys, ordered by version. Every migration must
+// have both its up and down file.
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	files := make(map[int64]map[string]bool)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		match := migrationFileRegexp.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %s", entry.Name())
+		}
+
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		name, direction := match[2], match[3]
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+			files[version] = make(map[string]bool)
+		}
+
+		if migration.Name != name {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s", version, migration.Name, name)
+		}
+
+		files[version][direction] = true
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for version, migration := range byVersion {
+		for _, direction := range []string{"up", "down"} {
+			if !files[version][direction] {
+				return nil, fmt.Errorf("migration %d_%s has no %s file", version, migration.Name, direction)
+			}
+		}
+
+		migrations = append(migrations, *migration)
+	}
+
+	slices.SortFunc(migrations, func(a, b Migration) int { return cmp.Compare(a.Version, b.Version) })
+	return migrations, nil
+}
+
+// pendingMigrations returns the migrations not applied yet, in order. It includes a
+// migration older than the latest applied one, e.g. merged from another branch.
+func pendingMigrations(migrations []Migration, applied map[int64]time.Time) []Migration {
+	var pending []Migration
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+
+	return pending
+}
+
+// Migrator applies the embedded migrations and tracks them in the schema_migrations table.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := fs.Sub(migrationsFS, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations, err := loadMigrations(sub)
 	if err != nil {
-		log.Fatal().Msgf("failed to run migration: %s", err.Error())
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         sqlDB,
+		migrations: migrations,
+	}, nil
+}
+
+// CheckSchema stops the process when the database misses migrations of this build. A
+// database ahead of the build, e.g. during a rolling deploy, is only reported.
+func CheckSchema(db *gorm.DB) {
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		log.Fatal().Msgf("failed to load migrations: %s", err.Error())
+	}
+
+	if err := migrator.Check(context.Background()); err != nil {
+		log.Fatal().Msgf("failed to check database schema, run `integration-go migrate up`: %s", err.Error())
+	}
+}
+
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func (m *Migrator) applied(ctx context.Context, q querier) (map[int64]time.Time, error) {
+	rows, err := q.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]time.Time)
+	for rows.Next() {
+		var version int64
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to fetch applied migrations: %w", err)
+		}
+
+		applied[version] = appliedAt
+	}
+
+	return applied, rows.Err()
+}
+
+// hasTable reports whether schema_migrations exists, so reading the status does not
+// create it.
+func (m *Migrator) hasTable(ctx context.Context) (bool, error) {
+	var exists bool
+	err := m.db.QueryRowContext(ctx, "SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check schema_migrations: %w", err)
+	}
+
+	return exists, nil
+}
+
+// withLock runs fn on a single connection holding the migration advisory lock.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", advisoryLockID); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
 	}
+
+	defer func() {
+		_, err := conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", advisoryLockID)
+		if err != nil {
+			log.Error().Msgf("failed to release migration lock: %s", err.Error())
+		}
+	}()
+
+	_, err = conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version bigint PRIMARY KEY,
+		name text NOT NULL,
+		applied_at timestamptz NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+// Up applies the pending migrations in order, each in its own transaction, and returns
+// the applied ones. A zero steps applies every pending migration.
+func (m *Migrator) Up(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		pending := pendingMigrations(m.migrations, applied)
+		if steps > 0 && steps < len(pending) {
+			pending = pending[:steps]
+		}
+
+		for _, migration := range pending {
+			err := m.exec(ctx, conn, migration.Up,
+				"INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", migration.Version, migration.Name)
+			if err != nil {
+				return fmt.Errorf("failed to apply migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			done = append(done, migration)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// Down rolls back the last steps applied migrations, latest first, and returns them.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		versions := make([]int64, 0, len(applied))
+		for version := range applied {
+			versions = append(versions, version)
+		}
+		slices.Sort(versions)
+		slices.Reverse(versions)
+
+		for _, version := range versions[:min(steps, len(versions))] {
+			i := slices.IndexFunc(m.migrations, func(migration Migration) bool { return migration.Version == version })
+			if i < 0 {
+				return fmt.Errorf("migration %d is applied but unknown to this build", version)
+			}
+
+			migration := m.migrations[i]
+			err := m.exec(ctx, conn, migration.Down, "DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+			if err != nil {
+				return fmt.Errorf("failed to roll back migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			done = append(done, migration)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// exec runs the migration script and records it in a single transaction.
+func (m *Migrator) exec(ctx context.Context, conn *sql.Conn, script string, record string, args ...any) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Status returns every migration of this build with the time it was applied, if any.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.appliedIfAny(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			status.AppliedAt = &appliedAt
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// Check returns ErrSchemaOutdated when a migration of this build is not applied.
+func (m *Migrator) Check(ctx context.Context) error {
+	applied, err := m.appliedIfAny(ctx)
+	if err != nil {
+		return err
+	}
+
+	if pending := pendingMigrations(m.migrations, applied); len(pending) > 0 {
+		return fmt.Errorf("%w: %d pending migrations, from %d_%s", ErrSchemaOutdated, len(pending), pending[0].Version, pending[0].Name)
+	}
+
+	for version := range applied {
+		if !slices.ContainsFunc(m.migrations, func(migration Migration) bool { return migration.Version == version }) {
+			log.Warn().Int64("version", version).Msg("database has a migration unknown to this build")
+		}
+	}
+
+	return nil
+}
+
+func (m *Migrator) appliedIfAny(ctx context.Context) (map[int64]time.Time, error) {
+	exists, err := m.hasTable(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return map[int64]time.Time{}, nil
+	}
+
+	return m.applied(ctx, m.db)
+}
+
+// CreateMigration writes empty up and down files for a new migration in dir, numbered
+// after the latest migration there, and returns their paths.
+func CreateMigration(dir string, name string) ([]string, error) {
+	if !migrationNameRegexp.MatchString(name) {
+		return nil, fmt.Errorf("invalid migration name %q, use lowercase letters, digits and underscores", name)
+	}
+
+	migrations, err := loadMigrations(os.DirFS(dir))
+	if err != nil {
+		return nil, err
+	}
+
+	var version int64 = 1
+	if len(migrations) > 0 {
+		version = migrations[len(migrations)-1].Version + 1
+	}
+
+	paths := make([]string, 0, 2)
+	for _, direction := range []string{"up", "down"} {
+		path := filepath.Join(dir, fmt.Sprintf("%06d_%s.%s.sql", version, name, direction))
+		if err := os.WriteFile(path, []byte(fmt.Sprintf("-- %s %s\n", name, direction)), 0o644); err != nil {
+			return nil, fmt.Errorf("failed to create migration: %w", err)
+		}
+
+		paths = append(paths, path)
+	}
+
+	return paths, nil
 }
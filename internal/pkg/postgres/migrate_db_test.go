@@ -0,0 +1,55 @@
+package postgres_test
+
+import (
+	"context"
+	"integration-go/internal/pkg/postgres"
+	"integration-go/internal/pkg/postgres/pgtest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrator_Up_FromAutoMigrateBaseline(t *testing.T) {
+	db := pgtest.Open(t)
+
+	// Schema and data as left by the AutoMigrate baseline.
+	require.NoError(t, db.Exec(`CREATE TABLE rooms (
+		id bigserial PRIMARY KEY,
+		multichannel_room_id text,
+		created_at timestamptz,
+		updated_at timestamptz
+	)`).Error)
+	require.NoError(t, db.Exec(`CREATE INDEX idx_rooms_multichannel_room_id ON rooms (multichannel_room_id)`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO rooms (multichannel_room_id, created_at, updated_at) VALUES
+		('room-1', now(), now()),
+		('room-2', now(), now()),
+		('room-1', now(), now())`).Error)
+
+	migrator, err := postgres.NewMigrator(db)
+	require.NoError(t, err)
+
+	_, err = migrator.Up(context.Background(), 0)
+	require.NoError(t, err)
+	require.NoError(t, migrator.Check(context.Background()))
+
+	type row struct {
+		ID                 int64
+		MultichannelRoomID string
+		Status             string
+	}
+
+	var rows []row
+	require.NoError(t, db.Raw(`SELECT id, multichannel_room_id, status FROM rooms ORDER BY id`).Scan(&rows).Error)
+	assert.Equal(t, []row{
+		{ID: 1, MultichannelRoomID: "room-1", Status: "new"},
+		{ID: 2, MultichannelRoomID: "room-2", Status: "new"},
+	}, rows)
+
+	err = db.Exec(`INSERT INTO rooms (multichannel_room_id) VALUES ('room-1')`).Error
+	assert.ErrorContains(t, err, "uidx_rooms_multichannel_room_id")
+
+	var indexes int64
+	require.NoError(t, db.Raw(`SELECT count(*) FROM pg_indexes WHERE schemaname = current_schema() AND indexname = 'idx_rooms_multichannel_room_id'`).Scan(&indexes).Error)
+	assert.Zero(t, indexes)
+}
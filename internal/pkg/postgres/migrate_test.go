@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	t.Run("success load migrations in order", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"000002_add_rooms_channel.up.sql":   {Data: []byte("ALTER TABLE rooms ADD COLUMN channel text;")},
+			"000002_add_rooms_channel.down.sql": {Data: []byte("ALTER TABLE rooms DROP COLUMN channel;")},
+			"000001_init.up.sql":                {Data: []byte("CREATE TABLE rooms (id bigserial);")},
+			"000001_init.down.sql":              {Data: []byte("DROP TABLE rooms;")},
+		}
+
+		migrations, err := loadMigrations(fsys)
+		require.NoError(t, err)
+		assert.Equal(t, []Migration{
+			{Version: 1, Name: "init", Up: "CREATE TABLE rooms (id bigserial);", Down: "DROP TABLE rooms;"},
+			{Version: 2, Name: "add_rooms_channel", Up: "ALTER TABLE rooms ADD COLUMN channel text;", Down: "ALTER TABLE rooms DROP COLUMN channel;"},
+		}, migrations)
+	})
+
+	t.Run("error invalid file name", func(t *testing.T) {
+		fsys := fstest.MapFS{"init.sql": {Data: []byte("")}}
+
+		_, err := loadMigrations(fsys)
+		assert.EqualError(t, err, "invalid migration file name init.sql")
+	})
+
+	t.Run("error missing down file", func(t *testing.T) {
+		fsys := fstest.MapFS{"000001_init.up.sql": {Data: []byte("")}}
+
+		_, err := loadMigrations(fsys)
+		assert.EqualError(t, err, "migration 1_init has no down file")
+	})
+
+	t.Run("error duplicate version", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"000001_init.up.sql":    {Data: []byte("")},
+			"000001_init.down.sql":  {Data: []byte("")},
+			"000001_other.up.sql":   {Data: []byte("")},
+			"000001_other.down.sql": {Data: []byte("")},
+		}
+
+		_, err := loadMigrations(fsys)
+		assert.EqualError(t, err, "duplicate migration version 1: init and other")
+	})
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	sub, err := fs.Sub(migrationsFS, "migrations")
+	require.NoError(t, err)
+
+	migrations, err := loadMigrations(sub)
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	assert.Equal(t, int64(1), migrations[0].Version)
+}
+
+func TestPendingMigrations(t *testing.T) {
+	migrations := []Migration{{Version: 1}, {Version: 2}, {Version: 3}}
+
+	// Version 2 was merged after version 3 was applied
+	pending := pendingMigrations(migrations, map[int64]time.Time{1: time.Now(), 3: time.Now()})
+	assert.Equal(t, []Migration{{Version: 2}}, pending)
+
+	assert.Empty(t, pendingMigrations(migrations, map[int64]time.Time{1: time.Now(), 2: time.Now(), 3: time.Now()}))
+	assert.Len(t, pendingMigrations(migrations, map[int64]time.Time{}), 3)
+}
+
+func TestCreateMigration(t *testing.T) {
+	dir := t.TempDir()
+
+	paths, err := CreateMigration(dir, "init")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "000001_init.up.sql"),
+		filepath.Join(dir, "000001_init.down.sql"),
+	}, paths)
+
+	paths, err = CreateMigration(dir, "add_rooms_channel")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "000002_add_rooms_channel.up.sql"), paths[0])
+
+	content, err := os.ReadFile(paths[0])
+	require.NoError(t, err)
+	assert.Equal(t, "-- add_rooms_channel up\n", string(content))
+
+	_, err = CreateMigration(dir, "Add Rooms")
+	assert.EqualError(t, err, `invalid migration name "Add Rooms", use lowercase letters, digits and underscores`)
+}
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS job_runs;
+DROP TABLE IF EXISTS assignments;
+DROP TABLE IF EXISTS dead_jobs;
+DROP TABLE IF EXISTS jobs;
+DROP TABLE IF EXISTS room_events;
+DROP TABLE IF EXISTS rooms;
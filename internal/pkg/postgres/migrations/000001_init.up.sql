@@ -0,0 +1,179 @@
+-- Tables previously created by AutoMigrate. IF NOT EXISTS and ADD COLUMN IF NOT EXISTS
+-- let databases created by AutoMigrate adopt this migration as their first version:
+-- the baseline only had rooms (id, multichannel_room_id, created_at, updated_at) with
+-- a non-unique index, and later builds added columns and tables one at a time.
+
+CREATE TABLE IF NOT EXISTS rooms (
+    id bigserial PRIMARY KEY,
+    multichannel_room_id text,
+    status text DEFAULT 'new',
+    assigned_at timestamptz,
+    resolved_at timestamptz,
+    resolved_by text,
+    failed_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+
+ALTER TABLE rooms
+    ADD COLUMN IF NOT EXISTS multichannel_room_id text,
+    ADD COLUMN IF NOT EXISTS status text DEFAULT 'new',
+    ADD COLUMN IF NOT EXISTS assigned_at timestamptz,
+    ADD COLUMN IF NOT EXISTS resolved_at timestamptz,
+    ADD COLUMN IF NOT EXISTS resolved_by text,
+    ADD COLUMN IF NOT EXISTS failed_at timestamptz,
+    ADD COLUMN IF NOT EXISTS created_at timestamptz,
+    ADD COLUMN IF NOT EXISTS updated_at timestamptz,
+    ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
+
+CREATE TABLE IF NOT EXISTS room_events (
+    id bigserial PRIMARY KEY,
+    room_id bigint,
+    multichannel_room_id text,
+    from_status text,
+    to_status text,
+    actor text,
+    note text,
+    created_at timestamptz
+);
+
+ALTER TABLE room_events
+    ADD COLUMN IF NOT EXISTS room_id bigint,
+    ADD COLUMN IF NOT EXISTS multichannel_room_id text,
+    ADD COLUMN IF NOT EXISTS from_status text,
+    ADD COLUMN IF NOT EXISTS to_status text,
+    ADD COLUMN IF NOT EXISTS actor text,
+    ADD COLUMN IF NOT EXISTS note text,
+    ADD COLUMN IF NOT EXISTS created_at timestamptz;
+
+CREATE INDEX IF NOT EXISTS idx_room_events_room_id ON room_events (room_id);
+CREATE INDEX IF NOT EXISTS idx_room_events_multichannel_room_id ON room_events (multichannel_room_id);
+
+-- The baseline allowed several rows per Multichannel room. Keep the oldest row, move
+-- the events of the others onto it and delete them so the unique index can be built.
+UPDATE room_events e
+SET room_id = k.id
+FROM rooms d
+JOIN (SELECT multichannel_room_id, MIN(id) AS id FROM rooms GROUP BY multichannel_room_id) k
+    ON k.multichannel_room_id = d.multichannel_room_id
+WHERE e.room_id = d.id AND d.id <> k.id;
+
+DELETE FROM rooms d
+USING rooms k
+WHERE d.multichannel_room_id = k.multichannel_room_id AND d.id > k.id;
+
+DROP INDEX IF EXISTS idx_rooms_multichannel_room_id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uidx_rooms_multichannel_room_id ON rooms (multichannel_room_id);
+CREATE INDEX IF NOT EXISTS idx_rooms_status ON rooms (status);
+CREATE INDEX IF NOT EXISTS idx_rooms_deleted_at ON rooms (deleted_at);
+
+CREATE TABLE IF NOT EXISTS jobs (
+    id bigserial PRIMARY KEY,
+    type text,
+    payload jsonb,
+    attempts bigint,
+    max_attempts bigint,
+    last_error text,
+    request_id text,
+    run_at timestamptz,
+    locked_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+
+ALTER TABLE jobs
+    ADD COLUMN IF NOT EXISTS type text,
+    ADD COLUMN IF NOT EXISTS payload jsonb,
+    ADD COLUMN IF NOT EXISTS attempts bigint,
+    ADD COLUMN IF NOT EXISTS max_attempts bigint,
+    ADD COLUMN IF NOT EXISTS last_error text,
+    ADD COLUMN IF NOT EXISTS request_id text,
+    ADD COLUMN IF NOT EXISTS run_at timestamptz,
+    ADD COLUMN IF NOT EXISTS locked_at timestamptz,
+    ADD COLUMN IF NOT EXISTS created_at timestamptz,
+    ADD COLUMN IF NOT EXISTS updated_at timestamptz;
+
+CREATE INDEX IF NOT EXISTS idx_jobs_type ON jobs (type);
+CREATE INDEX IF NOT EXISTS idx_jobs_run_at ON jobs (run_at);
+
+CREATE TABLE IF NOT EXISTS dead_jobs (
+    id bigserial PRIMARY KEY,
+    job_id bigint,
+    type text,
+    key text,
+    payload jsonb,
+    attempts bigint,
+    error text,
+    request_id text,
+    status text DEFAULT 'failed',
+    retried_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+
+ALTER TABLE dead_jobs
+    ADD COLUMN IF NOT EXISTS job_id bigint,
+    ADD COLUMN IF NOT EXISTS type text,
+    ADD COLUMN IF NOT EXISTS key text,
+    ADD COLUMN IF NOT EXISTS payload jsonb,
+    ADD COLUMN IF NOT EXISTS attempts bigint,
+    ADD COLUMN IF NOT EXISTS error text,
+    ADD COLUMN IF NOT EXISTS request_id text,
+    ADD COLUMN IF NOT EXISTS status text DEFAULT 'failed',
+    ADD COLUMN IF NOT EXISTS retried_at timestamptz,
+    ADD COLUMN IF NOT EXISTS created_at timestamptz,
+    ADD COLUMN IF NOT EXISTS updated_at timestamptz;
+
+CREATE INDEX IF NOT EXISTS idx_dead_jobs_job_id ON dead_jobs (job_id);
+CREATE INDEX IF NOT EXISTS idx_dead_jobs_type ON dead_jobs (type);
+CREATE UNIQUE INDEX IF NOT EXISTS uidx_dead_jobs_type_key ON dead_jobs (type, key) WHERE status = 'failed' AND key <> '';
+CREATE INDEX IF NOT EXISTS idx_dead_jobs_request_id ON dead_jobs (request_id);
+CREATE INDEX IF NOT EXISTS idx_dead_jobs_status ON dead_jobs (status);
+CREATE INDEX IF NOT EXISTS idx_dead_jobs_created_at ON dead_jobs (created_at);
+
+CREATE TABLE IF NOT EXISTS assignments (
+    id bigserial PRIMARY KEY,
+    multichannel_room_id text,
+    agent_id bigint,
+    agent_email text,
+    strategy text,
+    created_at timestamptz
+);
+
+ALTER TABLE assignments
+    ADD COLUMN IF NOT EXISTS multichannel_room_id text,
+    ADD COLUMN IF NOT EXISTS agent_id bigint,
+    ADD COLUMN IF NOT EXISTS agent_email text,
+    ADD COLUMN IF NOT EXISTS strategy text,
+    ADD COLUMN IF NOT EXISTS created_at timestamptz;
+
+CREATE INDEX IF NOT EXISTS idx_assignments_multichannel_room_id ON assignments (multichannel_room_id);
+CREATE INDEX IF NOT EXISTS idx_assignments_agent_id ON assignments (agent_id);
+
+CREATE TABLE IF NOT EXISTS job_runs (
+    id bigserial PRIMARY KEY,
+    job text,
+    request_id text,
+    status text,
+    started_at timestamptz,
+    finished_at timestamptz,
+    duration_ms bigint,
+    error text,
+    summary jsonb
+);
+
+ALTER TABLE job_runs
+    ADD COLUMN IF NOT EXISTS job text,
+    ADD COLUMN IF NOT EXISTS request_id text,
+    ADD COLUMN IF NOT EXISTS status text,
+    ADD COLUMN IF NOT EXISTS started_at timestamptz,
+    ADD COLUMN IF NOT EXISTS finished_at timestamptz,
+    ADD COLUMN IF NOT EXISTS duration_ms bigint,
+    ADD COLUMN IF NOT EXISTS error text,
+    ADD COLUMN IF NOT EXISTS summary jsonb;
+
+CREATE INDEX IF NOT EXISTS idx_job_runs_job_started_at ON job_runs (job, started_at);
+CREATE INDEX IF NOT EXISTS idx_job_runs_request_id ON job_runs (request_id);
+CREATE INDEX IF NOT EXISTS idx_job_runs_status ON job_runs (status);
@@ -0,0 +1,2 @@
+ALTER TABLE job_runs DROP COLUMN IF EXISTS trace_id;
+ALTER TABLE jobs DROP COLUMN IF EXISTS trace_parent;
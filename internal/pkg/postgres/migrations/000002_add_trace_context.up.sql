@@ -0,0 +1,5 @@
+-- W3C traceparent of the request that enqueued the job, so the worker continues its trace.
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS trace_parent text NOT NULL DEFAULT '';
+
+-- Trace of the cron job run, empty when tracing is disabled.
+ALTER TABLE job_runs ADD COLUMN IF NOT EXISTS trace_id text NOT NULL DEFAULT '';
@@ -0,0 +1,7 @@
+-- Latest fencing token written per lock, e.g. per cron job, so writes of a holder whose
+-- lock was taken over are rejected.
+CREATE TABLE IF NOT EXISTS fencing_tokens (
+    name text PRIMARY KEY,
+    token bigint NOT NULL,
+    updated_at timestamptz NOT NULL
+);
@@ -0,0 +1,3 @@
+-- When the resolver sent the closing message of the room, so a retried resolution does
+-- not send it twice.
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS closing_sent_at timestamptz;
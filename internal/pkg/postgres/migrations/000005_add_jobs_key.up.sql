@@ -0,0 +1,3 @@
+-- What the job works on, e.g. the multichannel room ID, so its failures are dead-lettered
+-- as a single dead job of the same type and key.
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS key text NOT NULL DEFAULT '';
@@ -0,0 +1,78 @@
+// Package pgtest opens the Postgres database of TEST_DATABASE_DSN for tests, in a schema
+// of their own dropped when the test ends. Tests using it are skipped when the variable is
+// not set, e.g. TEST_DATABASE_DSN="host=localhost user=postgres password=postgres
+// dbname=postgres sslmode=disable".
+package pgtest
+
+import (
+	"context"
+	"fmt"
+	"integration-go/internal/pkg/postgres"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	pgdriver "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Open returns a connection to an empty schema.
+func Open(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	admin := open(t, dsn)
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	require.NoError(t, admin.Exec("CREATE SCHEMA "+schema).Error)
+	t.Cleanup(func() {
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+	})
+
+	if strings.Contains(dsn, "://") {
+		sep := "?"
+		if strings.Contains(dsn, "?") {
+			sep = "&"
+		}
+
+		dsn += sep + "search_path=" + schema
+	} else {
+		dsn += " search_path=" + schema
+	}
+
+	return open(t, dsn)
+}
+
+// New returns a connection to a schema with every migration applied.
+func New(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db := Open(t)
+
+	migrator, err := postgres.NewMigrator(db)
+	require.NoError(t, err)
+
+	_, err = migrator.Up(context.Background(), 0)
+	require.NoError(t, err)
+
+	return db
+}
+
+func open(t *testing.T, dsn string) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(pgdriver.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	return db
+}
@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"integration-go/internal/pkg/tracing"
+
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const tracingSpanKey = "tracing:span"
+
+// Tracing is a GORM plugin running every query in a client span, child of the span in the
+// context of the statement. The SQL is recorded without its values.
+type Tracing struct{}
+
+func NewTracing() *Tracing {
+	return &Tracing{}
+}
+
+func (t *Tracing) Name() string {
+	return "tracing"
+}
+
+func (t *Tracing) Initialize(db *gorm.DB) error {
+	return registerCallbacks(db, t.Name(), t.before, t.after)
+}
+
+func (t *Tracing) before(db *gorm.DB) {
+	// The span name is set once the table is known, after the statement is built
+	_, span := tracing.Tracer().Start(db.Statement.Context, "db",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL),
+	)
+	db.InstanceSet(tracingSpanKey, span)
+}
+
+func (t *Tracing) after(operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		v, ok := db.InstanceGet(tracingSpanKey)
+		if !ok {
+			return
+		}
+
+		span, ok := v.(trace.Span)
+		if !ok {
+			return
+		}
+		defer span.End()
+
+		table := tableName(db)
+		span.SetName(operation + " " + table)
+		span.SetAttributes(
+			semconv.DBOperationName(operation),
+			semconv.DBCollectionName(table),
+			semconv.DBQueryText(db.Statement.SQL.String()),
+		)
+
+		if isError(db) {
+			span.RecordError(db.Error)
+			span.SetStatus(codes.Error, db.Error.Error())
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	db, err := gorm.Open(postgres.Open("host=localhost dbname=test"), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(NewTracing()))
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+
+	var rows []metricsTestRow
+	require.NoError(t, db.WithContext(ctx).Where("name = ?", "secret").Find(&rows).Error)
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	span := spans[0]
+	assert.Equal(t, "query metrics_test_rows", span.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+
+	attributes := map[string]string{}
+	for _, attr := range span.Attributes() {
+		attributes[string(attr.Key)] = attr.Value.Emit()
+	}
+
+	assert.Equal(t, "postgresql", attributes["db.system"])
+	assert.Equal(t, "query", attributes["db.operation.name"])
+	assert.Equal(t, `SELECT * FROM "metrics_test_rows" WHERE name = $1`, attributes["db.query.text"])
+}
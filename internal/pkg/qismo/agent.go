@@ -0,0 +1,159 @@
+package qismo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type Agent struct {
+	ID                   int64  `json:"id"`
+	Name                 string `json:"name"`
+	Email                string `json:"email"`
+	IsAvailable          bool   `json:"is_available"`
+	ForceOffline         bool   `json:"force_offline"`
+	CurrentCustomerCount int    `json:"current_customer_count"`
+	TypeAsString         string `json:"type_as_string"`
+}
+
+type GetAgentsRequest struct {
+	Search string
+	Page   int
+	Limit  int
+}
+
+type GetAgentsResponse struct {
+	Agents     []Agent
+	TotalCount int64
+}
+
+// GetAgents lists the agents of the app, searched by name or email.
+func (q *Qismo) GetAgents(ctx context.Context, req *GetAgentsRequest) (*GetAgentsResponse, error) {
+	query := url.Values{}
+	if req.Search != "" {
+		query.Set("search", req.Search)
+	}
+	if req.Page > 0 {
+		query.Set("page", strconv.Itoa(req.Page))
+	}
+	if req.Limit > 0 {
+		query.Set("limit", strconv.Itoa(req.Limit))
+	}
+
+	url := fmt.Sprintf("%s/api/v2/admin/agents?%s", q.url, query.Encode())
+
+	var resp struct {
+		Data struct {
+			Agents []Agent `json:"agents"`
+		} `json:"data"`
+		Meta struct {
+			TotalCount int64 `json:"total_count"`
+		} `json:"meta"`
+	}
+
+	err := q.call(ctx, http.MethodGet, url, q.headers(), nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetAgentsResponse{
+		Agents:     resp.Data.Agents,
+		TotalCount: resp.Meta.TotalCount,
+	}, nil
+}
+
+const (
+	// agentsPageSize is the largest page Omnichannel returns for agent listings.
+	agentsPageSize = 100
+
+	// maxAgentsPages bounds the pages read by a single listing of every page, so a listing
+	// that never ends cannot loop forever.
+	maxAgentsPages = 10
+)
+
+// GetAvailableAgents lists agents that can be assigned to the room, following the cursor
+// through up to maxAgentsPages pages.
+func (q *Qismo) GetAvailableAgents(ctx context.Context, roomID string) ([]Agent, error) {
+	var agents []Agent
+	cursor := ""
+	for range maxAgentsPages {
+		query := url.Values{}
+		query.Set("room_id", roomID)
+		query.Set("is_available_in_room", "true")
+		query.Set("limit", strconv.Itoa(agentsPageSize))
+		if cursor != "" {
+			query.Set("cursor_after", cursor)
+		}
+
+		url := fmt.Sprintf("%s/api/v2/admin/service/available_agents?%s", q.url, query.Encode())
+
+		var resp struct {
+			Data struct {
+				Agents []Agent `json:"agents"`
+			} `json:"data"`
+			Meta struct {
+				CursorAfter string `json:"cursor_after"`
+			} `json:"meta"`
+		}
+
+		err := q.call(ctx, http.MethodGet, url, q.headers(), nil, &resp)
+		if err != nil {
+			return nil, err
+		}
+
+		agents = append(agents, resp.Data.Agents...)
+		if resp.Meta.CursorAfter == "" || resp.Meta.CursorAfter == cursor || len(resp.Data.Agents) == 0 {
+			break
+		}
+		cursor = resp.Meta.CursorAfter
+	}
+
+	return agents, nil
+}
+
+// GetAgentsByDivision lists agents that belong to any of the divisions, page by page until
+// a page is not full, through up to maxAgentsPages pages.
+func (q *Qismo) GetAgentsByDivision(ctx context.Context, divisionIDs []int64) ([]Agent, error) {
+	var agents []Agent
+	for page := 1; page <= maxAgentsPages; page++ {
+		query := url.Values{}
+		for _, id := range divisionIDs {
+			query.Add("division_ids[]", strconv.FormatInt(id, 10))
+		}
+		query.Set("page", strconv.Itoa(page))
+		query.Set("limit", strconv.Itoa(agentsPageSize))
+
+		url := fmt.Sprintf("%s/api/v2/admin/agents/by_division?%s", q.url, query.Encode())
+
+		var resp struct {
+			Data []Agent `json:"data"`
+		}
+
+		err := q.call(ctx, http.MethodGet, url, q.headers(), nil, &resp)
+		if err != nil {
+			return nil, err
+		}
+
+		agents = append(agents, resp.Data...)
+		if len(resp.Data) < agentsPageSize {
+			break
+		}
+	}
+
+	return agents, nil
+}
+
+// AssignAgent adds the agent to the room, keeping agents that are already assigned.
+func (q *Qismo) AssignAgent(ctx context.Context, roomID string, agentID int64) error {
+	url := fmt.Sprintf("%s/api/v1/admin/service/assign_agent", q.url)
+	payload := map[string]any{
+		"room_id":              roomID,
+		"agent_id":             agentID,
+		"replace_latest_agent": false,
+	}
+
+	err := q.call(ctx, http.MethodPost, url, q.headers(), payload, nil)
+	return err
+}
@@ -0,0 +1,40 @@
+package qismo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+type Channel struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	IsActive bool   `json:"is_active"`
+	BadgeURL string `json:"badge_url"`
+}
+
+// Channels groups the channels of the app by source.
+type Channels struct {
+	Qiscus    []Channel `json:"qiscus_channels"`
+	WhatsApp  []Channel `json:"wa_channels"`
+	Line      []Channel `json:"line_channels"`
+	Facebook  []Channel `json:"fb_channels"`
+	Instagram []Channel `json:"ig_channels"`
+	Telegram  []Channel `json:"telegram_channels"`
+	Custom    []Channel `json:"custom_channels"`
+}
+
+func (q *Qismo) GetChannels(ctx context.Context) (*Channels, error) {
+	url := fmt.Sprintf("%s/api/v2/channels", q.url)
+
+	var resp struct {
+		Data Channels `json:"data"`
+	}
+
+	err := q.call(ctx, http.MethodGet, url, q.headers(), nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp.Data, nil
+}
@@ -0,0 +1,201 @@
+package qismo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"integration-go/internal/pkg/client"
+	"net/http"
+	"strings"
+)
+
+// ErrorCode classifies the error responses of the Qiscus APIs.
+type ErrorCode string
+
+const (
+	ErrorCodeUnknown             ErrorCode = "unknown"
+	ErrorCodeBadRequest          ErrorCode = "bad_request"
+	ErrorCodeInvalidCredentials  ErrorCode = "invalid_credentials"
+	ErrorCodeNotFound            ErrorCode = "not_found"
+	ErrorCodeRoomNotFound        ErrorCode = "room_not_found"
+	ErrorCodeRoomAlreadyResolved ErrorCode = "room_already_resolved"
+	ErrorCodeRateLimited         ErrorCode = "rate_limited"
+	ErrorCodeUnavailable         ErrorCode = "unavailable"
+)
+
+// Sentinels matching the APIError of the same code with errors.Is.
+var (
+	ErrRoomNotFound        = errors.New("room not found")
+	ErrRoomAlreadyResolved = errors.New("room already resolved")
+	ErrInvalidCredentials  = errors.New("invalid credentials")
+)
+
+// ErrRateLimitWait wraps the error of a request that was not sent because ctx was done, or
+// would be before a token of the rate limit is available. It is not a response of Qiscus.
+var ErrRateLimitWait = errors.New("rate limit wait interrupted")
+
+// APIError is an error response of the Qiscus APIs, decoded from its envelope. It wraps
+// the *client.Error of the call.
+type APIError struct {
+	StatusCode int
+	Code       ErrorCode
+	Message    string
+	Err        *client.Error
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("Qiscus API error %d: %s", e.StatusCode, e.Message)
+}
+
+func (e *APIError) Unwrap() error {
+	if e.Err == nil {
+		return nil
+	}
+
+	return e.Err
+}
+
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrRoomNotFound:
+		return e.Code == ErrorCodeRoomNotFound
+	case ErrRoomAlreadyResolved:
+		return e.Code == ErrorCodeRoomAlreadyResolved
+	case ErrInvalidCredentials:
+		return e.Code == ErrorCodeInvalidCredentials
+	default:
+		return false
+	}
+}
+
+// Retryable tells whether the same call may succeed later, when Qiscus is rate limiting
+// or failing. Other errors will fail again until the request or the data changes.
+func (e *APIError) Retryable() bool {
+	return e.Code == ErrorCodeRateLimited || e.Code == ErrorCodeUnavailable
+}
+
+// HTTPStatusCode maps the error to the status of the API response: 404 and 409 for the
+// rooms missing or resolved in Omnichannel, 503 while rate limited and 502 otherwise, since
+// the failure is on the upstream side.
+func (e *APIError) HTTPStatusCode() int {
+	switch e.Code {
+	case ErrorCodeNotFound, ErrorCodeRoomNotFound:
+		return http.StatusNotFound
+	case ErrorCodeRoomAlreadyResolved:
+		return http.StatusConflict
+	case ErrorCodeRateLimited:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+// errorEnvelope covers the error bodies of the Qiscus APIs, whose message is either in
+// message, error or errors, as a string, an object or a list of strings.
+type errorEnvelope struct {
+	Status  int             `json:"status"`
+	Message string          `json:"message"`
+	Error   json.RawMessage `json:"error"`
+	Errors  json.RawMessage `json:"errors"`
+}
+
+// decodeError turns the error responses of err into an *APIError, leaving the failures
+// without response as they are.
+func decodeError(err error) error {
+	var clientErr *client.Error
+	if !errors.As(err, &clientErr) {
+		return err
+	}
+
+	if clientErr.Kind != client.ErrorKindHTTP && clientErr.Kind != client.ErrorKindRateLimited {
+		return err
+	}
+
+	// Not a response, whatever its kind
+	if clientErr.StatusCode == 0 {
+		return err
+	}
+
+	message := errorMessage(clientErr.RawAPIResponse)
+	if message == "" {
+		message = http.StatusText(clientErr.StatusCode)
+	}
+
+	return &APIError{
+		StatusCode: clientErr.StatusCode,
+		Code:       classify(clientErr.StatusCode, message),
+		Message:    message,
+		Err:        clientErr,
+	}
+}
+
+func errorMessage(body []byte) string {
+	var envelope errorEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return ""
+	}
+
+	for _, raw := range []json.RawMessage{envelope.Errors, envelope.Error} {
+		if message := rawMessage(raw); message != "" {
+			return message
+		}
+	}
+
+	return envelope.Message
+}
+
+func rawMessage(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+
+	var list []string
+	if err := json.Unmarshal(raw, &list); err == nil {
+		return strings.Join(list, "; ")
+	}
+
+	var object struct {
+		Message          string   `json:"message"`
+		DetailedMessages []string `json:"detailed_messages"`
+	}
+
+	if err := json.Unmarshal(raw, &object); err == nil {
+		if object.Message != "" {
+			return object.Message
+		}
+
+		return strings.Join(object.DetailedMessages, "; ")
+	}
+
+	return ""
+}
+
+// classify derives the code from the status and, as Qiscus does not send codes, from the
+// wording of the message for the room cases.
+func classify(statusCode int, message string) ErrorCode {
+	message = strings.ToLower(message)
+
+	switch {
+	case strings.Contains(message, "already resolved"):
+		return ErrorCodeRoomAlreadyResolved
+	case statusCode == http.StatusUnauthorized, statusCode == http.StatusForbidden:
+		return ErrorCodeInvalidCredentials
+	case statusCode == http.StatusNotFound && strings.Contains(message, "room"):
+		return ErrorCodeRoomNotFound
+	case statusCode == http.StatusNotFound:
+		return ErrorCodeNotFound
+	case statusCode == http.StatusTooManyRequests:
+		return ErrorCodeRateLimited
+	case statusCode >= http.StatusInternalServerError:
+		return ErrorCodeUnavailable
+	case statusCode == http.StatusBadRequest, statusCode == http.StatusUnprocessableEntity:
+		return ErrorCodeBadRequest
+	default:
+		return ErrorCodeUnknown
+	}
+}
@@ -0,0 +1,173 @@
+package qismo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"integration-go/internal/pkg/api/resp"
+	"integration-go/internal/pkg/client"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeError(t *testing.T) {
+	tests := []struct {
+		name            string
+		kind            client.ErrorKind
+		statusCode      int
+		body            string
+		expectedCode    ErrorCode
+		expectedMessage string
+		retryable       bool
+	}{
+		{
+			name:            "room not found in errors object",
+			kind:            client.ErrorKindHTTP,
+			statusCode:      http.StatusNotFound,
+			body:            `{"errors":{"message":"Customer room not found"},"status":404}`,
+			expectedCode:    ErrorCodeRoomNotFound,
+			expectedMessage: "Customer room not found",
+		},
+		{
+			name:            "other resource not found",
+			kind:            client.ErrorKindHTTP,
+			statusCode:      http.StatusNotFound,
+			body:            `{"errors":"agent not found"}`,
+			expectedCode:    ErrorCodeNotFound,
+			expectedMessage: "agent not found",
+		},
+		{
+			name:            "room already resolved in errors list",
+			kind:            client.ErrorKindHTTP,
+			statusCode:      http.StatusBadRequest,
+			body:            `{"errors":["Room is already resolved"]}`,
+			expectedCode:    ErrorCodeRoomAlreadyResolved,
+			expectedMessage: "Room is already resolved",
+		},
+		{
+			name:            "invalid credentials in error object",
+			kind:            client.ErrorKindHTTP,
+			statusCode:      http.StatusUnauthorized,
+			body:            `{"error":{"message":"Unauthorized","detailed_messages":["invalid secret key"]}}`,
+			expectedCode:    ErrorCodeInvalidCredentials,
+			expectedMessage: "Unauthorized",
+		},
+		{
+			name:            "detailed messages only",
+			kind:            client.ErrorKindHTTP,
+			statusCode:      http.StatusUnprocessableEntity,
+			body:            `{"error":{"detailed_messages":["room_id is required","tag is required"]}}`,
+			expectedCode:    ErrorCodeBadRequest,
+			expectedMessage: "room_id is required; tag is required",
+		},
+		{
+			name:            "top level message",
+			kind:            client.ErrorKindHTTP,
+			statusCode:      http.StatusConflict,
+			body:            `{"message":"conflict"}`,
+			expectedCode:    ErrorCodeUnknown,
+			expectedMessage: "conflict",
+		},
+		{
+			name:            "rate limited",
+			kind:            client.ErrorKindRateLimited,
+			statusCode:      http.StatusTooManyRequests,
+			body:            `{"errors":"too many requests"}`,
+			expectedCode:    ErrorCodeRateLimited,
+			expectedMessage: "too many requests",
+			retryable:       true,
+		},
+		{
+			name:            "unavailable without envelope",
+			kind:            client.ErrorKindHTTP,
+			statusCode:      http.StatusBadGateway,
+			body:            `<html>bad gateway</html>`,
+			expectedCode:    ErrorCodeUnavailable,
+			expectedMessage: "Bad Gateway",
+			retryable:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientErr := &client.Error{
+				Kind:           tt.kind,
+				Message:        "http client error",
+				StatusCode:     tt.statusCode,
+				RawError:       errors.New("raw error"),
+				RawAPIResponse: []byte(tt.body),
+			}
+
+			err := decodeError(clientErr)
+
+			var apiErr *APIError
+			require.True(t, errors.As(err, &apiErr))
+			assert.Equal(t, tt.statusCode, apiErr.StatusCode)
+			assert.Equal(t, tt.expectedCode, apiErr.Code)
+			assert.Equal(t, tt.expectedMessage, apiErr.Message)
+			assert.Equal(t, tt.retryable, apiErr.Retryable())
+			assert.Equal(t, clientErr, errors.Unwrap(err))
+		})
+	}
+}
+
+func TestDecodeError_WithoutResponse(t *testing.T) {
+	tests := []error{
+		errors.New("not a client error"),
+		&client.Error{Kind: client.ErrorKindNetwork, Message: "timeout"},
+		&client.Error{Kind: client.ErrorKindBreakerOpen, RawError: client.ErrBreakerOpen},
+		&client.Error{Kind: client.ErrorKindDecode, StatusCode: http.StatusOK},
+		&client.Error{Kind: client.ErrorKindRateLimited, Message: "rate limited without response"},
+	}
+
+	for _, err := range tests {
+		assert.Equal(t, err, decodeError(err))
+	}
+}
+
+func TestAPIError_Is(t *testing.T) {
+	err := fmt.Errorf("failed to resolve room: %w", &APIError{Code: ErrorCodeRoomAlreadyResolved})
+
+	assert.True(t, errors.Is(err, ErrRoomAlreadyResolved))
+	assert.False(t, errors.Is(err, ErrRoomNotFound))
+	assert.False(t, errors.Is(err, ErrInvalidCredentials))
+}
+
+func TestAPIError_WriteJSONFromError(t *testing.T) {
+	tests := []struct {
+		code            ErrorCode
+		expectedCode    int
+		expectedMessage string
+	}{
+		{code: ErrorCodeRoomNotFound, expectedCode: http.StatusNotFound, expectedMessage: "Not Found"},
+		{code: ErrorCodeNotFound, expectedCode: http.StatusNotFound, expectedMessage: "Not Found"},
+		{code: ErrorCodeRoomAlreadyResolved, expectedCode: http.StatusConflict, expectedMessage: "Conflict"},
+		{code: ErrorCodeRateLimited, expectedCode: http.StatusServiceUnavailable, expectedMessage: "Service Unavailable"},
+		{code: ErrorCodeInvalidCredentials, expectedCode: http.StatusBadGateway, expectedMessage: "Bad Gateway"},
+		{code: ErrorCodeUnavailable, expectedCode: http.StatusBadGateway, expectedMessage: "Bad Gateway"},
+		{code: ErrorCodeBadRequest, expectedCode: http.StatusBadGateway, expectedMessage: "Bad Gateway"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.code), func(t *testing.T) {
+			w := httptest.NewRecorder()
+			err := fmt.Errorf("failed to create omnichannel tag: %w", &APIError{
+				StatusCode: http.StatusBadRequest,
+				Code:       tt.code,
+				Message:    "something",
+			})
+
+			resp.WriteJSONFromError(w, err)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+
+			var body resp.HTTPError
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.expectedMessage, body.Message)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package qismo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+const (
+	MessageTypeText           = "text"
+	MessageTypeButtons        = "buttons"
+	MessageTypeCarousel       = "carousel"
+	MessageTypeFileAttachment = "file_attachment"
+	MessageTypeCustom         = "custom"
+)
+
+type SendMessageRequest struct {
+	// SenderEmail is the admin email for bot messages, or the agent email for agent messages.
+	SenderEmail string
+	RoomID      string
+	Message     string
+	// Type defaults to MessageTypeText.
+	Type    string
+	Payload any
+}
+
+func (r *SendMessageRequest) messageType() string {
+	if r.Type == "" {
+		return MessageTypeText
+	}
+
+	return r.Type
+}
+
+// SendMessageAsBot posts the message to the room as the bot, so it does not reset
+// the room's agent assignment.
+func (q *Qismo) SendMessageAsBot(ctx context.Context, req *SendMessageRequest) error {
+	url := fmt.Sprintf("%s/%s/bot", q.url, q.appID)
+	payload := map[string]any{
+		"sender_email": req.SenderEmail,
+		"room_id":      req.RoomID,
+		"message":      req.Message,
+		"type":         req.messageType(),
+		"payload":      req.Payload,
+	}
+
+	err := q.call(ctx, http.MethodPost, url, q.headers(), payload, nil)
+	return err
+}
+
+// SendMessageAsAgent posts the message to the room on behalf of an agent through the
+// Qiscus SDK API. The agent must be a participant of the room.
+func (q *Qismo) SendMessageAsAgent(ctx context.Context, req *SendMessageRequest) error {
+	url := fmt.Sprintf("%s/api/v2.1/rest/post_comment", q.sdkURL)
+	payload := map[string]any{
+		"user_id": req.SenderEmail,
+		"room_id": req.RoomID,
+		"message": req.Message,
+		"type":    req.messageType(),
+		"payload": req.Payload,
+	}
+
+	err := q.call(ctx, http.MethodPost, url, q.sdkHeaders(), payload, nil)
+	return err
+}
@@ -5,25 +5,44 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"integration-go/internal/pkg/client"
+	"io"
 	"net/http"
+
+	"golang.org/x/time/rate"
 )
 
 type Qismo struct {
 	client    httpClient
 	url       string
+	sdkURL    string
 	appID     string
 	secretKey string
+	limiter   *rate.Limiter
 }
 
-func New(client httpClient, url, appID, secretKey string) *Qismo {
+func New(client httpClient, url, sdkURL, appID, secretKey string) *Qismo {
 	return &Qismo{
 		client:    client,
 		url:       url,
+		sdkURL:    sdkURL,
 		appID:     appID,
 		secretKey: secretKey,
 	}
 }
 
+// SetRateLimit makes every request wait for a token bucket of limit requests per second
+// and burst requests, so the instance stays within the Qiscus API quota before being
+// answered with 429. A limit of zero or below removes it.
+func (q *Qismo) SetRateLimit(limit float64, burst int) {
+	if limit <= 0 {
+		q.limiter = nil
+		return
+	}
+
+	q.limiter = rate.NewLimiter(rate.Limit(limit), max(burst, 1))
+}
+
 func (q *Qismo) headers() map[string]string {
 	return map[string]string{
 		"Qiscus-App-Id":     q.appID,
@@ -31,23 +50,62 @@ func (q *Qismo) headers() map[string]string {
 	}
 }
 
+func (q *Qismo) sdkHeaders() map[string]string {
+	return map[string]string{
+		"QISCUS-SDK-APP-ID": q.appID,
+		"QISCUS-SDK-SECRET": q.secretKey,
+	}
+}
+
+// call sends the payload encoded as JSON and decodes the response into response.
+// Error responses are returned as *APIError, a request not sent because ctx ended while
+// waiting for the rate limit as ErrRateLimitWait, and every other failure, including
+// encoding the payload, as *client.Error.
+func (q *Qismo) call(ctx context.Context, method, url string, headers map[string]string, payload, response any) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return &client.Error{
+				Kind:     client.ErrorKindRequest,
+				Message:  fmt.Sprintf("unable to marshal request body: %s", err.Error()),
+				RawError: err,
+			}
+		}
+
+		body = bytes.NewBuffer(data)
+	}
+
+	if q.limiter != nil {
+		if err := q.limiter.Wait(ctx); err != nil {
+			return fmt.Errorf("%w: %w", ErrRateLimitWait, err)
+		}
+	}
+
+	if err := q.client.Call(ctx, method, url, body, headers, response); err != nil {
+		return decodeError(err)
+	}
+
+	return nil
+}
+
 func (q *Qismo) CreateRoomTag(ctx context.Context, roomID string, tag string) error {
 	url := fmt.Sprintf("%s/api/v1/room_tag/create", q.url)
-	payload, _ := json.Marshal(map[string]any{
+	payload := map[string]any{
 		"room_id": roomID,
 		"tag":     tag,
-	})
+	}
 
-	err := q.client.Call(ctx, http.MethodPost, url, bytes.NewBuffer(payload), q.headers(), nil)
+	err := q.call(ctx, http.MethodPost, url, q.headers(), payload, nil)
 	return err
 }
 
 func (q *Qismo) ResolvedRoom(ctx context.Context, roomID string) error {
 	url := fmt.Sprintf("%s/api/v1/admin/service/mark_as_resolved", q.url)
-	payload, _ := json.Marshal(map[string]any{
+	payload := map[string]any{
 		"room_id": roomID,
-	})
+	}
 
-	err := q.client.Call(ctx, http.MethodPost, url, bytes.NewBuffer(payload), q.headers(), nil)
+	err := q.call(ctx, http.MethodPost, url, q.headers(), payload, nil)
 	return err
 }
@@ -0,0 +1,317 @@
+package qismo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"integration-go/internal/pkg/client"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedRequest struct {
+	Method  string
+	Path    string
+	Query   string
+	Headers http.Header
+	Body    map[string]any
+}
+
+func newTestServer(t *testing.T, status int, response string) (*Qismo, *recordedRequest) {
+	t.Helper()
+
+	recorded := &recordedRequest{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorded.Method = r.Method
+		recorded.Path = r.URL.Path
+		recorded.Query = r.URL.RawQuery
+		recorded.Headers = r.Header.Clone()
+
+		body, _ := io.ReadAll(r.Body)
+		if len(body) > 0 {
+			require.NoError(t, json.Unmarshal(body, &recorded.Body))
+		}
+
+		w.WriteHeader(status)
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(srv.Close)
+
+	return New(client.New(), srv.URL, srv.URL, "app-id", "secret-key"), recorded
+}
+
+func TestQismo_GetRoomInfo(t *testing.T) {
+	q, recorded := newTestServer(t, http.StatusOK, `{"data":{"customer_room":{"id":1,"room_id":"room-123","source":"wa","is_resolved":true}}}`)
+
+	room, err := q.GetRoomInfo(context.Background(), "room-123")
+	assert.Nil(t, err)
+	assert.Equal(t, &CustomerRoom{ID: 1, RoomID: "room-123", Source: "wa", IsResolved: true}, room)
+	assert.Equal(t, http.MethodGet, recorded.Method)
+	assert.Equal(t, "/api/v2/customer_rooms/room-123", recorded.Path)
+	assert.Equal(t, "app-id", recorded.Headers.Get("Qiscus-App-Id"))
+	assert.Equal(t, "secret-key", recorded.Headers.Get("Qiscus-Secret-Key"))
+}
+
+func TestQismo_GetRoomInfo_Error(t *testing.T) {
+	q, _ := newTestServer(t, http.StatusNotFound, `{"errors":{"message":"room not found"}}`)
+
+	room, err := q.GetRoomInfo(context.Background(), "room-123")
+	assert.Nil(t, room)
+
+	var clientErr *client.Error
+	require.True(t, errors.As(err, &clientErr))
+	assert.Equal(t, http.StatusNotFound, clientErr.StatusCode)
+
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, ErrorCodeRoomNotFound, apiErr.Code)
+	assert.True(t, errors.Is(err, ErrRoomNotFound))
+}
+
+func TestQismo_GetRoomTags(t *testing.T) {
+	q, recorded := newTestServer(t, http.StatusOK, `{"data":[{"id":1,"name":"vip"}]}`)
+
+	tags, err := q.GetRoomTags(context.Background(), "room-123")
+	assert.Nil(t, err)
+	assert.Equal(t, []RoomTag{{ID: 1, Name: "vip"}}, tags)
+	assert.Equal(t, "/api/v1/room_tag/room-123", recorded.Path)
+}
+
+func TestQismo_GetAgents(t *testing.T) {
+	q, recorded := newTestServer(t, http.StatusOK, `{"data":{"agents":[{"id":1,"email":"a@mail.com"}]},"meta":{"total_count":10}}`)
+
+	resp, err := q.GetAgents(context.Background(), &GetAgentsRequest{Search: "a", Page: 2, Limit: 5})
+	assert.Nil(t, err)
+	assert.Equal(t, &GetAgentsResponse{Agents: []Agent{{ID: 1, Email: "a@mail.com"}}, TotalCount: 10}, resp)
+	assert.Equal(t, "/api/v2/admin/agents", recorded.Path)
+	assert.Equal(t, "limit=5&page=2&search=a", recorded.Query)
+}
+
+func TestQismo_GetAvailableAgents(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		if r.URL.Query().Get("cursor_after") == "" {
+			w.Write([]byte(`{"data":{"agents":[{"id":1},{"id":2}]},"meta":{"cursor_after":"abc"}}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"agents":[{"id":3}]},"meta":{"cursor_after":""}}`))
+	}))
+	t.Cleanup(srv.Close)
+	q := New(client.New(), srv.URL, srv.URL, "app-id", "secret-key")
+
+	agents, err := q.GetAvailableAgents(context.Background(), "room-123")
+	assert.Nil(t, err)
+	assert.Equal(t, []Agent{{ID: 1}, {ID: 2}, {ID: 3}}, agents)
+	assert.Equal(t, []string{
+		"is_available_in_room=true&limit=100&room_id=room-123",
+		"cursor_after=abc&is_available_in_room=true&limit=100&room_id=room-123",
+	}, queries)
+}
+
+func TestQismo_GetAvailableAgents_PageCap(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprintf(w, `{"data":{"agents":[{"id":%d}]},"meta":{"cursor_after":"c%d"}}`, calls, calls)
+	}))
+	t.Cleanup(srv.Close)
+	q := New(client.New(), srv.URL, srv.URL, "app-id", "secret-key")
+
+	agents, err := q.GetAvailableAgents(context.Background(), "room-123")
+	assert.Nil(t, err)
+	assert.Len(t, agents, maxAgentsPages)
+	assert.Equal(t, maxAgentsPages, calls)
+}
+
+func TestQismo_GetAgentsByDivision(t *testing.T) {
+	q, recorded := newTestServer(t, http.StatusOK, `{"data":[{"id":1},{"id":2}]}`)
+
+	agents, err := q.GetAgentsByDivision(context.Background(), []int64{10, 11})
+	assert.Nil(t, err)
+	assert.Equal(t, []Agent{{ID: 1}, {ID: 2}}, agents)
+	assert.Equal(t, "division_ids%5B%5D=10&division_ids%5B%5D=11&limit=100&page=1", recorded.Query)
+}
+
+func TestQismo_GetAgentsByDivision_Pages(t *testing.T) {
+	fullPage := func(page int) string {
+		agents := make([]string, agentsPageSize)
+		for i := range agents {
+			agents[i] = fmt.Sprintf(`{"id":%d}`, page*1000+i)
+		}
+		return `{"data":[` + strings.Join(agents, ",") + `]}`
+	}
+
+	t.Run("stop at the first page that is not full", func(t *testing.T) {
+		var pages []string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			page := r.URL.Query().Get("page")
+			pages = append(pages, page)
+			if page == "1" {
+				w.Write([]byte(fullPage(1)))
+				return
+			}
+			w.Write([]byte(`{"data":[{"id":1}]}`))
+		}))
+		t.Cleanup(srv.Close)
+		q := New(client.New(), srv.URL, srv.URL, "app-id", "secret-key")
+
+		agents, err := q.GetAgentsByDivision(context.Background(), []int64{10})
+		assert.Nil(t, err)
+		assert.Len(t, agents, agentsPageSize+1)
+		assert.Equal(t, []string{"1", "2"}, pages)
+	})
+
+	t.Run("stop at the page cap", func(t *testing.T) {
+		calls := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.Write([]byte(fullPage(calls)))
+		}))
+		t.Cleanup(srv.Close)
+		q := New(client.New(), srv.URL, srv.URL, "app-id", "secret-key")
+
+		agents, err := q.GetAgentsByDivision(context.Background(), []int64{10})
+		assert.Nil(t, err)
+		assert.Len(t, agents, maxAgentsPages*agentsPageSize)
+		assert.Equal(t, maxAgentsPages, calls)
+	})
+}
+
+func TestQismo_AssignAgent(t *testing.T) {
+	q, recorded := newTestServer(t, http.StatusOK, `{}`)
+
+	err := q.AssignAgent(context.Background(), "room-123", 7)
+	assert.Nil(t, err)
+	assert.Equal(t, http.MethodPost, recorded.Method)
+	assert.Equal(t, "/api/v1/admin/service/assign_agent", recorded.Path)
+	assert.Equal(t, map[string]any{"room_id": "room-123", "agent_id": float64(7), "replace_latest_agent": false}, recorded.Body)
+}
+
+func TestQismo_GetAdditionalInfo(t *testing.T) {
+	q, recorded := newTestServer(t, http.StatusOK, `{"data":{"extras":{"user_properties":[{"key":"plan","value":"gold"}]}}}`)
+
+	info, err := q.GetAdditionalInfo(context.Background(), "room-123")
+	assert.Nil(t, err)
+	assert.Equal(t, []AdditionalInfo{{Key: "plan", Value: "gold"}}, info)
+	assert.Equal(t, "/api/v1/qiscus/room/room-123/user_info", recorded.Path)
+}
+
+func TestQismo_UpdateAdditionalInfo(t *testing.T) {
+	var posted map[string]any
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"data":{"extras":{"user_properties":[{"key":"plan","value":"gold"},{"key":"city","value":"Jakarta"}]}}}`))
+			return
+		}
+
+		json.NewDecoder(r.Body).Decode(&posted)
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	q := New(client.New(), srv.URL, srv.URL, "app-id", "secret-key")
+
+	err := q.UpdateAdditionalInfo(context.Background(), "room-123", []AdditionalInfo{
+		{Key: "plan", Value: "platinum"},
+		{Key: "ticket", Value: "T-1"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]any{
+		"user_properties": []any{
+			map[string]any{"key": "plan", "value": "platinum"},
+			map[string]any{"key": "city", "value": "Jakarta"},
+			map[string]any{"key": "ticket", "value": "T-1"},
+		},
+	}, posted)
+}
+
+func TestQismo_SendMessageAsBot(t *testing.T) {
+	q, recorded := newTestServer(t, http.StatusOK, `{}`)
+
+	err := q.SendMessageAsBot(context.Background(), &SendMessageRequest{
+		SenderEmail: "admin@mail.com",
+		RoomID:      "room-123",
+		Message:     "hello",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "/app-id/bot", recorded.Path)
+	assert.Equal(t, map[string]any{
+		"sender_email": "admin@mail.com",
+		"room_id":      "room-123",
+		"message":      "hello",
+		"type":         MessageTypeText,
+		"payload":      nil,
+	}, recorded.Body)
+}
+
+func TestQismo_SendMessageAsAgent(t *testing.T) {
+	q, recorded := newTestServer(t, http.StatusOK, `{}`)
+
+	err := q.SendMessageAsAgent(context.Background(), &SendMessageRequest{
+		SenderEmail: "agent@mail.com",
+		RoomID:      "room-123",
+		Message:     "hello",
+		Type:        MessageTypeCustom,
+		Payload:     map[string]any{"foo": "bar"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "/api/v2.1/rest/post_comment", recorded.Path)
+	assert.Equal(t, "app-id", recorded.Headers.Get("QISCUS-SDK-APP-ID"))
+	assert.Equal(t, "secret-key", recorded.Headers.Get("QISCUS-SDK-SECRET"))
+	assert.Equal(t, "agent@mail.com", recorded.Body["user_id"])
+	assert.Equal(t, MessageTypeCustom, recorded.Body["type"])
+}
+
+func TestQismo_GetChannels(t *testing.T) {
+	q, recorded := newTestServer(t, http.StatusOK, `{"data":{"wa_channels":[{"id":1,"name":"WA","is_active":true}],"telegram_channels":[{"id":2,"name":"Bot"}]}}`)
+
+	channels, err := q.GetChannels(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, &Channels{
+		WhatsApp: []Channel{{ID: 1, Name: "WA", IsActive: true}},
+		Telegram: []Channel{{ID: 2, Name: "Bot"}},
+	}, channels)
+	assert.Equal(t, "/api/v2/channels", recorded.Path)
+}
+
+func TestQismo_Call_MarshalError(t *testing.T) {
+	q := New(client.New(), "http://localhost", "http://localhost", "app-id", "secret-key")
+
+	err := q.call(context.Background(), http.MethodPost, "http://localhost", nil, map[string]any{"fn": func() {}}, nil)
+
+	var clientErr *client.Error
+	require.True(t, errors.As(err, &clientErr))
+	assert.Contains(t, clientErr.Message, "unable to marshal request body")
+}
+
+func TestQismo_SetRateLimit(t *testing.T) {
+	q, _ := newTestServer(t, http.StatusOK, `{}`)
+	q.SetRateLimit(1, 1)
+
+	// The burst is spent by the first request, the next one cannot get a token in time
+	require.NoError(t, q.CreateRoomTag(context.Background(), "room-123", "vip"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	err := q.CreateRoomTag(ctx, "room-123", "vip")
+	assert.ErrorIs(t, err, ErrRateLimitWait)
+
+	var apiErr *APIError
+	assert.False(t, errors.As(err, &apiErr))
+
+	// A zero limit removes it
+	q.SetRateLimit(0, 0)
+	assert.Nil(t, q.limiter)
+	assert.NoError(t, q.CreateRoomTag(ctx, "room-123", "vip"))
+}
@@ -0,0 +1,122 @@
+package qismo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type CustomerRoom struct {
+	ID              int64  `json:"id"`
+	RoomID          string `json:"room_id"`
+	ChannelID       int64  `json:"channel_id"`
+	Source          string `json:"source"`
+	Name            string `json:"name"`
+	UserID          string `json:"user_id"`
+	UserAvatarURL   string `json:"user_avatar_url"`
+	IsResolved      bool   `json:"is_resolved"`
+	IsWaiting       bool   `json:"is_waiting"`
+	IsHandledByBot  bool   `json:"is_handled_by_bot"`
+	LastCommentText string `json:"last_comment_text"`
+	Extras          string `json:"extras"`
+	// LastCustomerTimestamp is the time of the last message sent by the customer.
+	LastCustomerTimestamp *time.Time `json:"last_customer_timestamp"`
+}
+
+type RoomTag struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// GetRoomInfo returns the customer room of the Omnichannel room ID.
+func (q *Qismo) GetRoomInfo(ctx context.Context, roomID string) (*CustomerRoom, error) {
+	url := fmt.Sprintf("%s/api/v2/customer_rooms/%s", q.url, url.PathEscape(roomID))
+
+	var resp struct {
+		Data struct {
+			CustomerRoom CustomerRoom `json:"customer_room"`
+		} `json:"data"`
+	}
+
+	err := q.call(ctx, http.MethodGet, url, q.headers(), nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp.Data.CustomerRoom, nil
+}
+
+func (q *Qismo) GetRoomTags(ctx context.Context, roomID string) ([]RoomTag, error) {
+	url := fmt.Sprintf("%s/api/v1/room_tag/%s", q.url, url.PathEscape(roomID))
+
+	var resp struct {
+		Data []RoomTag `json:"data"`
+	}
+
+	err := q.call(ctx, http.MethodGet, url, q.headers(), nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Data, nil
+}
+
+// GetAdditionalInfo returns the additional info shown on the customer room, stored
+// by Omnichannel as the user properties of the room.
+func (q *Qismo) GetAdditionalInfo(ctx context.Context, roomID string) ([]AdditionalInfo, error) {
+	url := fmt.Sprintf("%s/api/v1/qiscus/room/%s/user_info", q.url, url.PathEscape(roomID))
+
+	var resp struct {
+		Data struct {
+			Extras struct {
+				UserProperties []AdditionalInfo `json:"user_properties"`
+			} `json:"extras"`
+		} `json:"data"`
+	}
+
+	err := q.call(ctx, http.MethodGet, url, q.headers(), nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Data.Extras.UserProperties, nil
+}
+
+// SetAdditionalInfo replaces every user property of the room with info.
+func (q *Qismo) SetAdditionalInfo(ctx context.Context, roomID string, info []AdditionalInfo) error {
+	url := fmt.Sprintf("%s/api/v1/qiscus/room/%s/user_info", q.url, url.PathEscape(roomID))
+	payload := map[string]any{
+		"user_properties": info,
+	}
+
+	err := q.call(ctx, http.MethodPost, url, q.headers(), payload, nil)
+	return err
+}
+
+// UpdateAdditionalInfo sets the given keys and keeps the other user properties of
+// the room, since Omnichannel only supports replacing all of them at once.
+func (q *Qismo) UpdateAdditionalInfo(ctx context.Context, roomID string, info []AdditionalInfo) error {
+	current, err := q.GetAdditionalInfo(ctx, roomID)
+	if err != nil {
+		return err
+	}
+
+	index := make(map[string]int, len(current))
+	for i, item := range current {
+		index[item.Key] = i
+	}
+
+	for _, item := range info {
+		if i, ok := index[item.Key]; ok {
+			current[i].Value = item.Value
+			continue
+		}
+
+		index[item.Key] = len(current)
+		current = append(current, item)
+	}
+
+	return q.SetAdditionalInfo(ctx, roomID, current)
+}
@@ -0,0 +1,92 @@
+package qismo
+
+import (
+	"context"
+	"encoding/json"
+	"integration-go/internal/pkg/api/resp"
+	"integration-go/internal/pkg/validate"
+	"io"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+)
+
+// WebhookHandler handles the raw payload of a single webhook type.
+type WebhookHandler func(ctx context.Context, payload []byte) error
+
+// WebhookRouter dispatches Omnichannel webhooks received on a single endpoint
+// to the handler registered for their webhook_type.
+type WebhookRouter struct {
+	handlers map[string]WebhookHandler
+}
+
+func NewWebhookRouter() *WebhookRouter {
+	return &WebhookRouter{
+		handlers: map[string]WebhookHandler{},
+	}
+}
+
+// Handle registers the handler of a webhook type, replacing any previous one.
+func (r *WebhookRouter) Handle(webhookType string, h WebhookHandler) {
+	r.handlers[webhookType] = h
+}
+
+// HandleWebhook registers a handler that receives the payload decoded into T and
+// validated against its validate tags, e.g. HandleWebhook(r, WebhookTypeNewSession,
+// svc.EnqueueCreateRoom).
+func HandleWebhook[T any](r *WebhookRouter, webhookType string, fn func(ctx context.Context, req *T) error) {
+	r.Handle(webhookType, func(ctx context.Context, payload []byte) error {
+		var req T
+		if err := validate.Unmarshal(payload, &req); err != nil {
+			return err
+		}
+
+		return fn(ctx, &req)
+	})
+}
+
+// Dispatch calls the handler registered for webhookType. Unknown types are logged and
+// acknowledged, so enabling a new webhook in Omnichannel never causes failed deliveries.
+func (r *WebhookRouter) Dispatch(ctx context.Context, webhookType string, payload []byte) error {
+	h, ok := r.handlers[webhookType]
+	if !ok {
+		log.Ctx(ctx).Warn().Str("webhook_type", webhookType).Msg("unhandled webhook type")
+		return nil
+	}
+
+	return h(ctx, payload)
+}
+
+// ServeHTTP reads the webhook type from the webhook_type field of the payload, falling back
+// to the type query parameter for webhooks whose payload has no webhook_type.
+func (r *WebhookRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+
+	payload, err := io.ReadAll(req.Body)
+	if err != nil {
+		resp.WriteJSONFromError(w, err)
+		return
+	}
+
+	var envelope struct {
+		WebhookType string `json:"webhook_type"`
+	}
+
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		resp.WriteJSONFromError(w, err)
+		return
+	}
+
+	webhookType := envelope.WebhookType
+	if webhookType == "" {
+		webhookType = req.URL.Query().Get("type")
+	}
+
+	if err := r.Dispatch(ctx, webhookType, payload); err != nil {
+		log.Ctx(ctx).Error().Str("webhook_type", webhookType).Msgf("failed to handle webhook: %s", err.Error())
+		resp.WriteJSONFromError(w, err)
+		return
+	}
+
+	resp.WriteJSON(w, http.StatusOK, "ok")
+}
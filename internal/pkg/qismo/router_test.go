@@ -0,0 +1,99 @@
+package qismo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookRouter_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name         string
+		url          string
+		body         string
+		handlerErr   error
+		expectedCode int
+		expectedType string
+		expectedRoom string
+	}{
+		{
+			name:         "dispatch by webhook_type",
+			url:          "/wh/qiscus/omnichannel",
+			body:         `{"webhook_type":"mark_as_resolved","service":{"room_id":"room-123"}}`,
+			expectedCode: http.StatusOK,
+			expectedType: WebhookTypeMarkAsResolved,
+			expectedRoom: "room-123",
+		},
+		{
+			name:         "dispatch by type query parameter",
+			url:          "/wh/qiscus/omnichannel?type=mark_as_resolved",
+			body:         `{"service":{"room_id":"room-456"}}`,
+			expectedCode: http.StatusOK,
+			expectedType: WebhookTypeMarkAsResolved,
+			expectedRoom: "room-456",
+		},
+		{
+			name:         "unknown type is acknowledged",
+			url:          "/wh/qiscus/omnichannel",
+			body:         `{"webhook_type":"something_new"}`,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "handler error",
+			url:          "/wh/qiscus/omnichannel",
+			body:         `{"webhook_type":"mark_as_resolved","service":{"room_id":"room-123"}}`,
+			handlerErr:   fmt.Errorf("unexpected"),
+			expectedCode: http.StatusInternalServerError,
+			expectedType: WebhookTypeMarkAsResolved,
+			expectedRoom: "room-123",
+		},
+		{
+			name:         "invalid json",
+			url:          "/wh/qiscus/omnichannel",
+			body:         `{`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "payload fails validation",
+			url:          "/wh/qiscus/omnichannel",
+			body:         `{"webhook_type":"mark_as_resolved","service":{"room_id":""}}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "payload does not match type",
+			url:          "/wh/qiscus/omnichannel",
+			body:         `{"webhook_type":"mark_as_resolved","service":{"room_id":123}}`,
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotType, gotRoom string
+
+			router := NewWebhookRouter()
+			HandleWebhook(router, WebhookTypeMarkAsResolved, func(ctx context.Context, req *WebhookMarkAsResolvedRequest) error {
+				gotType = WebhookTypeMarkAsResolved
+				gotRoom = req.Service.RoomID
+				return tt.handlerErr
+			})
+			HandleWebhook(router, WebhookTypeNewSession, func(ctx context.Context, req *WebhookNewSessionRequest) error {
+				gotType = WebhookTypeNewSession
+				return nil
+			})
+
+			req := httptest.NewRequest(http.MethodPost, tt.url, strings.NewReader(tt.body))
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.expectedCode, recorder.Code)
+			assert.Equal(t, tt.expectedType, gotType)
+			assert.Equal(t, tt.expectedRoom, gotRoom)
+		})
+	}
+}
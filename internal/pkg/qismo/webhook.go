@@ -1,11 +1,21 @@
 package qismo
 
+import "time"
+
+const (
+	WebhookTypeNewSession      = "new_session"
+	WebhookTypeMarkAsResolved  = "mark_as_resolved"
+	WebhookTypeAgentAllocation = "agent_allocation"
+	WebhookTypeCustomButton    = "custom_button"
+	WebhookTypeNewMessage      = "new_message"
+)
+
 type WebhookNewSessionRequest struct {
 	IsNewSession bool `json:"is_new_session"`
 	Payload      struct {
 		Room struct {
 			ID              string `json:"id"`
-			IDStr           string `json:"id_str"`
+			IDStr           string `json:"id_str" validate:"required"`
 			IsPublicChannel bool   `json:"is_public_channel"`
 			Name            string `json:"name"`
 			Options         string `json:"options"`
@@ -20,3 +30,98 @@ type WebhookNewSessionRequest struct {
 	} `json:"payload"`
 	WebhookType string `json:"webhook_type"`
 }
+
+type WebhookCustomer struct {
+	AdditionalInfo []AdditionalInfo `json:"additional_info"`
+	Avatar         string           `json:"avatar"`
+	Name           string           `json:"name"`
+	UserID         string           `json:"user_id"`
+}
+
+type AdditionalInfo struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type WebhookAgent struct {
+	ID          int64  `json:"id"`
+	Email       string `json:"email"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	IsAvailable bool   `json:"is_available"`
+}
+
+type WebhookService struct {
+	ID             int64   `json:"id"`
+	RoomID         string  `json:"room_id" validate:"required"`
+	IsResolved     bool    `json:"is_resolved"`
+	Notes          *string `json:"notes"`
+	FirstCommentID string  `json:"first_comment_id"`
+	LastCommentID  string  `json:"last_comment_id"`
+	Source         string  `json:"source"`
+}
+
+// WebhookMarkAsResolvedRequest is sent when a room is resolved, by an agent or through the API.
+type WebhookMarkAsResolvedRequest struct {
+	Customer    WebhookCustomer `json:"customer"`
+	ResolvedBy  WebhookAgent    `json:"resolved_by"`
+	Service     WebhookService  `json:"service"`
+	WebhookType string          `json:"webhook_type"`
+}
+
+// WebhookAgentAllocationRequest is sent by Custom Agent Allocation (CAA) when a room
+// needs an agent, instead of Omnichannel assigning one. LatestService is not validated,
+// the allocation relies on RoomID only.
+type WebhookAgentAllocationRequest struct {
+	AppID          string         `json:"app_id"`
+	AvatarURL      string         `json:"avatar_url"`
+	CandidateAgent *WebhookAgent  `json:"candidate_agent"`
+	Email          string         `json:"email"`
+	Extras         string         `json:"extras"`
+	IsNewSession   bool           `json:"is_new_session"`
+	IsResolved     bool           `json:"is_resolved"`
+	LatestService  WebhookService `json:"latest_service" validate:"-"`
+	Name           string         `json:"name"`
+	RoomID         string         `json:"room_id" validate:"required"`
+	Source         string         `json:"source"`
+	WebhookType    string         `json:"webhook_type"`
+}
+
+// WebhookCustomButtonRequest is sent when an agent clicks a custom button on the room.
+type WebhookCustomButtonRequest struct {
+	Agent          WebhookAgent     `json:"agent"`
+	ChannelID      int64            `json:"channel_id"`
+	Customer       WebhookCustomer  `json:"customer"`
+	RoomID         string           `json:"room_id" validate:"required"`
+	AdditionalInfo []AdditionalInfo `json:"additional_info"`
+	WebhookType    string           `json:"webhook_type"`
+}
+
+// WebhookNewMessageRequest is sent for every message posted to a room.
+type WebhookNewMessageRequest struct {
+	Payload struct {
+		From struct {
+			ID    int64  `json:"id"`
+			Email string `json:"email"`
+			Name  string `json:"name"`
+		} `json:"from"`
+		Room struct {
+			ID           string `json:"id"`
+			IDStr        string `json:"id_str" validate:"required"`
+			Name         string `json:"name"`
+			Options      string `json:"options"`
+			Participants []struct {
+				Email string `json:"email"`
+			} `json:"participants"`
+		} `json:"room"`
+		Message struct {
+			ID        int64          `json:"id"`
+			IDStr     string         `json:"id_str"`
+			Type      string         `json:"type"`
+			Text      string         `json:"text"`
+			Payload   map[string]any `json:"payload"`
+			Timestamp time.Time      `json:"timestamp"`
+		} `json:"message"`
+	} `json:"payload"`
+	WebhookType string `json:"webhook_type"`
+}
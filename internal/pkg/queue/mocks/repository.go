@@ -0,0 +1,243 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "integration-go/internal/entity"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// Repository is an autogenerated mock type for the Repository type
+type Repository struct {
+	mock.Mock
+}
+
+type Repository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Repository) EXPECT() *Repository_Expecter {
+	return &Repository_Expecter{mock: &_m.Mock}
+}
+
+// Bury provides a mock function with given fields: ctx, job, cause
+func (_m *Repository) Bury(ctx context.Context, job *entity.Job, cause error) error {
+	ret := _m.Called(ctx, job, cause)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Bury")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Job, error) error); ok {
+		r0 = rf(ctx, job, cause)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Repository_Bury_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Bury'
+type Repository_Bury_Call struct {
+	*mock.Call
+}
+
+// Bury is a helper method to define mock.On call
+//   - ctx context.Context
+//   - job *entity.Job
+//   - cause error
+func (_e *Repository_Expecter) Bury(ctx interface{}, job interface{}, cause interface{}) *Repository_Bury_Call {
+	return &Repository_Bury_Call{Call: _e.mock.On("Bury", ctx, job, cause)}
+}
+
+func (_c *Repository_Bury_Call) Run(run func(ctx context.Context, job *entity.Job, cause error)) *Repository_Bury_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.Job), args[2].(error))
+	})
+	return _c
+}
+
+func (_c *Repository_Bury_Call) Return(_a0 error) *Repository_Bury_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Repository_Bury_Call) RunAndReturn(run func(context.Context, *entity.Job, error) error) *Repository_Bury_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Claim provides a mock function with given fields: ctx, limit, lockTimeout
+func (_m *Repository) Claim(ctx context.Context, limit int, lockTimeout time.Duration) ([]entity.Job, error) {
+	ret := _m.Called(ctx, limit, lockTimeout)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Claim")
+	}
+
+	var r0 []entity.Job
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Duration) ([]entity.Job, error)); ok {
+		return rf(ctx, limit, lockTimeout)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Duration) []entity.Job); ok {
+		r0 = rf(ctx, limit, lockTimeout)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Job)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Duration) error); ok {
+		r1 = rf(ctx, limit, lockTimeout)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_Claim_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Claim'
+type Repository_Claim_Call struct {
+	*mock.Call
+}
+
+// Claim is a helper method to define mock.On call
+//   - ctx context.Context
+//   - limit int
+//   - lockTimeout time.Duration
+func (_e *Repository_Expecter) Claim(ctx interface{}, limit interface{}, lockTimeout interface{}) *Repository_Claim_Call {
+	return &Repository_Claim_Call{Call: _e.mock.On("Claim", ctx, limit, lockTimeout)}
+}
+
+func (_c *Repository_Claim_Call) Run(run func(ctx context.Context, limit int, lockTimeout time.Duration)) *Repository_Claim_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *Repository_Claim_Call) Return(_a0 []entity.Job, _a1 error) *Repository_Claim_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_Claim_Call) RunAndReturn(run func(context.Context, int, time.Duration) ([]entity.Job, error)) *Repository_Claim_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Complete provides a mock function with given fields: ctx, job
+func (_m *Repository) Complete(ctx context.Context, job *entity.Job) error {
+	ret := _m.Called(ctx, job)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Complete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Job) error); ok {
+		r0 = rf(ctx, job)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Repository_Complete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Complete'
+type Repository_Complete_Call struct {
+	*mock.Call
+}
+
+// Complete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - job *entity.Job
+func (_e *Repository_Expecter) Complete(ctx interface{}, job interface{}) *Repository_Complete_Call {
+	return &Repository_Complete_Call{Call: _e.mock.On("Complete", ctx, job)}
+}
+
+func (_c *Repository_Complete_Call) Run(run func(ctx context.Context, job *entity.Job)) *Repository_Complete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.Job))
+	})
+	return _c
+}
+
+func (_c *Repository_Complete_Call) Return(_a0 error) *Repository_Complete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Repository_Complete_Call) RunAndReturn(run func(context.Context, *entity.Job) error) *Repository_Complete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Retry provides a mock function with given fields: ctx, job, runAt, cause
+func (_m *Repository) Retry(ctx context.Context, job *entity.Job, runAt time.Time, cause error) error {
+	ret := _m.Called(ctx, job, runAt, cause)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Retry")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Job, time.Time, error) error); ok {
+		r0 = rf(ctx, job, runAt, cause)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Repository_Retry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Retry'
+type Repository_Retry_Call struct {
+	*mock.Call
+}
+
+// Retry is a helper method to define mock.On call
+//   - ctx context.Context
+//   - job *entity.Job
+//   - runAt time.Time
+//   - cause error
+func (_e *Repository_Expecter) Retry(ctx interface{}, job interface{}, runAt interface{}, cause interface{}) *Repository_Retry_Call {
+	return &Repository_Retry_Call{Call: _e.mock.On("Retry", ctx, job, runAt, cause)}
+}
+
+func (_c *Repository_Retry_Call) Run(run func(ctx context.Context, job *entity.Job, runAt time.Time, cause error)) *Repository_Retry_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.Job), args[2].(time.Time), args[3].(error))
+	})
+	return _c
+}
+
+func (_c *Repository_Retry_Call) Return(_a0 error) *Repository_Retry_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Repository_Retry_Call) RunAndReturn(run func(context.Context, *entity.Job, time.Time, error) error) *Repository_Retry_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewRepository creates a new instance of Repository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Repository {
+	mock := &Repository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
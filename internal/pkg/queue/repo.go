@@ -0,0 +1,117 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"integration-go/internal/entity"
+	"integration-go/internal/pkg/config"
+	"integration-go/internal/pkg/tracing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type repo struct {
+	db          *gorm.DB
+	maxAttempts int
+}
+
+func NewRepository(db *gorm.DB, maxAttempts int) *repo {
+	return &repo{
+		db:          db,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Enqueue persists a job to be processed by the worker as soon as possible, in the trace
+// of ctx. Jobs of the same type and key are dead-lettered as a single dead job.
+func (r *repo) Enqueue(ctx context.Context, jobType string, key string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	requestID, _ := ctx.Value(config.RequestIDKey).(string)
+
+	err = r.db.WithContext(ctx).Create(&entity.Job{
+		Type:        jobType,
+		Key:         key,
+		Payload:     data,
+		MaxAttempts: r.maxAttempts,
+		RequestID:   requestID,
+		TraceParent: tracing.TraceParent(ctx),
+		RunAt:       time.Now(),
+	}).Error
+	return err
+}
+
+// Claim locks up to limit due jobs and increments their attempts. Jobs locked longer than
+// lockTimeout are considered abandoned by a crashed worker and can be claimed again.
+func (r *repo) Claim(ctx context.Context, limit int, lockTimeout time.Duration) ([]entity.Job, error) {
+	var jobs []entity.Job
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE jobs SET locked_at = NOW(), attempts = attempts + 1, updated_at = NOW()
+		WHERE id IN (
+			SELECT id FROM jobs
+			WHERE run_at <= NOW() AND (locked_at IS NULL OR locked_at < ?)
+			ORDER BY run_at, id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, time.Now().Add(-lockTimeout), limit).Scan(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+func (r *repo) Complete(ctx context.Context, job *entity.Job) error {
+	err := r.db.WithContext(ctx).Delete(&entity.Job{}, job.ID).Error
+	return err
+}
+
+// Retry releases the job lock and schedules the next attempt at runAt.
+func (r *repo) Retry(ctx context.Context, job *entity.Job, runAt time.Time, cause error) error {
+	err := r.db.WithContext(ctx).Model(&entity.Job{}).Where("id = ?", job.ID).Updates(map[string]any{
+		"locked_at":  nil,
+		"run_at":     runAt,
+		"last_error": cause.Error(),
+	}).Error
+	return err
+}
+
+// Bury moves the job to the dead-letter table. A keyed job with the same type and key as a
+// dead job that is not retried yet adds its attempts to that dead job instead.
+func (r *repo) Bury(ctx context.Context, job *entity.Job, cause error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:     []clause.Column{{Name: "type"}, {Name: "key"}},
+			TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "status = 'failed' AND key <> ''"}}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"job_id":     job.ID,
+				"attempts":   gorm.Expr("dead_jobs.attempts + ?", job.Attempts),
+				"payload":    job.Payload,
+				"error":      cause.Error(),
+				"request_id": job.RequestID,
+				"updated_at": time.Now(),
+			}),
+		}).Create(&entity.DeadJob{
+			JobID:     job.ID,
+			Type:      job.Type,
+			Key:       job.Key,
+			Payload:   job.Payload,
+			Attempts:  job.Attempts,
+			Error:     cause.Error(),
+			RequestID: job.RequestID,
+			Status:    entity.DeadJobStatusFailed,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Delete(&entity.Job{}, job.ID).Error
+	})
+}
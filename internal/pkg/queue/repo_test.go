@@ -0,0 +1,40 @@
+package queue
+
+import (
+	"context"
+	"integration-go/internal/entity"
+	"integration-go/internal/pkg/postgres/pgtest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepo_Bury_SameKey(t *testing.T) {
+	db := pgtest.New(t)
+	repo := NewRepository(db, 3)
+	ctx := context.Background()
+
+	// The same room fails twice, e.g. after its dead job was not replayed yet
+	require.NoError(t, repo.Enqueue(ctx, "room.create", "room-123", map[string]string{"room_id": "room-123"}))
+	require.NoError(t, repo.Enqueue(ctx, "room.create", "room-123", map[string]string{"room_id": "room-123"}))
+
+	jobs, err := repo.Claim(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+
+	require.NoError(t, repo.Bury(ctx, &jobs[0], errUnexpected))
+	require.NoError(t, repo.Bury(ctx, &jobs[1], errUnexpected))
+
+	var deadJobs []entity.DeadJob
+	require.NoError(t, db.Find(&deadJobs).Error)
+	require.Len(t, deadJobs, 1)
+	assert.Equal(t, "room-123", deadJobs[0].Key)
+	assert.Equal(t, jobs[1].ID, deadJobs[0].JobID)
+	assert.Equal(t, 2, deadJobs[0].Attempts)
+
+	var remaining int64
+	require.NoError(t, db.Model(&entity.Job{}).Count(&remaining).Error)
+	assert.Zero(t, remaining)
+}
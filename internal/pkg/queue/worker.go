@@ -0,0 +1,255 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"integration-go/internal/entity"
+	"integration-go/internal/pkg/config"
+	"integration-go/internal/pkg/tracing"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const maxBackoff = time.Hour
+
+// finishTimeout bounds completing, retrying or burying a job once its handler returned.
+// It runs after the job timeout, so the job timeout must leave room for it within the
+// lock timeout.
+const finishTimeout = 10 * time.Second
+
+// Handler processes the payload of a job. Returning an error schedules a retry, unless it
+// is wrapped with Permanent or reports itself as not retryable.
+type Handler func(ctx context.Context, payload []byte) error
+
+//go:generate mockery --with-expecter --case snake --name Repository
+type Repository interface {
+	Claim(ctx context.Context, limit int, lockTimeout time.Duration) ([]entity.Job, error)
+	Complete(ctx context.Context, job *entity.Job) error
+	Retry(ctx context.Context, job *entity.Job, runAt time.Time, cause error) error
+	Bury(ctx context.Context, job *entity.Job, cause error) error
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks an error that will fail again on retry, e.g. a malformed payload,
+// so the job is dead-lettered right away.
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// retryable is implemented by errors that know whether the failed call may succeed later,
+// e.g. *qismo.APIError.
+type retryable interface {
+	Retryable() bool
+}
+
+// isPermanent tells whether err will fail again on retry: it is marked with Permanent, or
+// it wraps an error that reports itself as not retryable.
+func isPermanent(err error) bool {
+	var perr *permanentError
+	if errors.As(err, &perr) {
+		return true
+	}
+
+	var rerr retryable
+	return errors.As(err, &rerr) && !rerr.Retryable()
+}
+
+type enqueuedAtKey struct{}
+
+// EnqueuedAt returns when the job processed in ctx was enqueued, zero outside of a job.
+func EnqueuedAt(ctx context.Context) time.Time {
+	at, _ := ctx.Value(enqueuedAtKey{}).(time.Time)
+	return at
+}
+
+type Worker struct {
+	repo     Repository
+	handlers map[string]Handler
+	cfg      config.Worker
+	now      func() time.Time
+}
+
+func NewWorker(repo Repository, cfg config.Worker) (*Worker, error) {
+	if cfg.JobTimeout <= 0 || cfg.JobTimeout+finishTimeout >= cfg.LockTimeout {
+		return nil, fmt.Errorf("job timeout %s must be positive and shorter than the lock timeout %s by more than %s",
+			cfg.JobTimeout, cfg.LockTimeout, finishTimeout)
+	}
+
+	return &Worker{
+		repo:     repo,
+		handlers: map[string]Handler{},
+		cfg:      cfg,
+		now:      time.Now,
+	}, nil
+}
+
+// Register sets the handler of a job type. It must be called before Run.
+func (w *Worker) Register(jobType string, h Handler) {
+	w.handlers[jobType] = h
+}
+
+// Run starts the configured number of pollers and blocks until ctx is canceled
+// and every in-flight job has finished.
+func (w *Worker) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < w.cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.poll(ctx)
+		}()
+	}
+
+	wg.Wait()
+}
+
+func (w *Worker) poll(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Drain due jobs before waiting for the next tick
+		for w.processNext(ctx) {
+			if ctx.Err() != nil {
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// processNext claims and processes a single job. It reports whether a job was found.
+func (w *Worker) processNext(ctx context.Context) bool {
+	jobs, err := w.repo.Claim(ctx, 1, w.cfg.LockTimeout)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Error().Msgf("failed to claim job: %s", err.Error())
+		}
+		return false
+	}
+
+	if len(jobs) == 0 {
+		return false
+	}
+
+	// The job runs on its own context, so a shutdown lets it finish instead of
+	// interrupting it halfway.
+	w.process(context.Background(), &jobs[0])
+	return true
+}
+
+// process handles the job in a consumer span continuing the trace of the request that
+// enqueued it, if any.
+func (w *Worker) process(ctx context.Context, job *entity.Job) {
+	ctx = tracing.WithTraceParent(ctx, job.TraceParent)
+	ctx, span := tracing.Tracer().Start(ctx, "job "+job.Type,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.Int64("job.id", job.ID),
+			attribute.String("job.type", job.Type),
+			attribute.Int("job.attempts", job.Attempts),
+			attribute.String("request_id", job.RequestID),
+		),
+	)
+	defer span.End()
+
+	logCtx := log.With().
+		Str("request_id", job.RequestID).
+		Int64("job_id", job.ID).
+		Str("job_type", job.Type)
+	if traceID := tracing.TraceID(ctx); traceID != "" {
+		logCtx = logCtx.Str("trace_id", traceID)
+	}
+
+	ctx = logCtx.Logger().WithContext(ctx)
+	ctx = context.WithValue(ctx, config.RequestIDKey, job.RequestID)
+	ctx = context.WithValue(ctx, enqueuedAtKey{}, job.CreatedAt)
+
+	err := w.handleWithTimeout(ctx, job)
+
+	// The job is finished even when the handler used up its timeout
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), finishTimeout)
+	defer cancel()
+
+	if err == nil {
+		if err := w.repo.Complete(ctx, job); err != nil {
+			log.Ctx(ctx).Error().Msgf("failed to complete job: %s", err.Error())
+		}
+		return
+	}
+
+	span.SetStatus(codes.Error, err.Error())
+
+	if isPermanent(err) || job.Attempts >= job.MaxAttempts {
+		log.Ctx(ctx).Error().Int("attempts", job.Attempts).Msgf("job failed, moved to dead-letter: %s", err.Error())
+		if err := w.repo.Bury(ctx, job, err); err != nil {
+			log.Ctx(ctx).Error().Msgf("failed to bury job: %s", err.Error())
+		}
+		return
+	}
+
+	runAt := w.now().Add(w.backoff(job.Attempts))
+	log.Ctx(ctx).Warn().Int("attempts", job.Attempts).Time("run_at", runAt).Msgf("job failed, will be retried: %s", err.Error())
+	if err := w.repo.Retry(ctx, job, runAt, err); err != nil {
+		log.Ctx(ctx).Error().Msgf("failed to reschedule job: %s", err.Error())
+	}
+}
+
+// handleWithTimeout runs the handler within the job timeout, shorter than the lock timeout
+// so the job is not claimed again by another worker while it still runs.
+func (w *Worker) handleWithTimeout(ctx context.Context, job *entity.Job) error {
+	ctx, cancel := context.WithTimeout(ctx, w.cfg.JobTimeout)
+	defer cancel()
+
+	return w.handle(ctx, job)
+}
+
+func (w *Worker) handle(ctx context.Context, job *entity.Job) (err error) {
+	h, ok := w.handlers[job.Type]
+	if !ok {
+		return Permanent(fmt.Errorf("no handler registered for job type %q", job.Type))
+	}
+
+	defer func() {
+		if rvr := recover(); rvr != nil {
+			err = fmt.Errorf("panic: %v", rvr)
+		}
+	}()
+
+	return h(ctx, job.Payload)
+}
+
+// backoff doubles the delay on every attempt, starting from the configured base.
+func (w *Worker) backoff(attempts int) time.Duration {
+	d := w.cfg.Backoff
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= maxBackoff {
+			return maxBackoff
+		}
+	}
+
+	return d
+}
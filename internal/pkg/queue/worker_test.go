@@ -0,0 +1,235 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"integration-go/internal/entity"
+	"integration-go/internal/pkg/config"
+	"integration-go/internal/pkg/queue/mocks"
+	"integration-go/internal/pkg/tracing"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var errUnexpected = fmt.Errorf("unexpected")
+
+// testRetryableError stands for the errors of API clients that know whether a call may
+// succeed later, e.g. *qismo.APIError.
+type testRetryableError struct {
+	retryable bool
+}
+
+func (e *testRetryableError) Error() string   { return "api error" }
+func (e *testRetryableError) Retryable() bool { return e.retryable }
+
+func TestWorker_Process(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cfg := config.Worker{
+		Concurrency: 1,
+		Backoff:     10 * time.Second,
+		LockTimeout: time.Minute,
+		JobTimeout:  30 * time.Second,
+	}
+
+	t.Run("success complete job", func(t *testing.T) {
+		mockRepo := mocks.NewRepository(t)
+		job := &entity.Job{ID: 1, Type: "test", Payload: []byte(`{"id":1}`), Attempts: 1, MaxAttempts: 3, CreatedAt: now}
+
+		mockRepo.EXPECT().Complete(mock.Anything, job).Return(nil).Once()
+
+		w, err := NewWorker(mockRepo, cfg)
+		require.NoError(t, err)
+		var got []byte
+		var enqueuedAt time.Time
+		w.Register("test", func(ctx context.Context, payload []byte) error {
+			got = payload
+			enqueuedAt = EnqueuedAt(ctx)
+			return nil
+		})
+
+		w.process(context.Background(), job)
+		assert.Equal(t, []byte(`{"id":1}`), got)
+		assert.Equal(t, now, enqueuedAt)
+	})
+
+	t.Run("success continue trace of enqueuing request", func(t *testing.T) {
+		mockRepo := mocks.NewRepository(t)
+		job := &entity.Job{
+			ID:          1,
+			Type:        "test",
+			Attempts:    1,
+			MaxAttempts: 3,
+			TraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		}
+
+		mockRepo.EXPECT().Complete(mock.Anything, job).Return(nil).Once()
+
+		w, err := NewWorker(mockRepo, cfg)
+		require.NoError(t, err)
+		var traceID string
+		w.Register("test", func(ctx context.Context, payload []byte) error {
+			traceID = tracing.TraceID(ctx)
+			return nil
+		})
+
+		w.process(context.Background(), job)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceID)
+	})
+
+	t.Run("error handler schedules retry with backoff", func(t *testing.T) {
+		mockRepo := mocks.NewRepository(t)
+		job := &entity.Job{ID: 1, Type: "test", Attempts: 2, MaxAttempts: 3}
+
+		mockRepo.EXPECT().Retry(mock.Anything, job, now.Add(20*time.Second), errUnexpected).Return(nil).Once()
+
+		w, err := NewWorker(mockRepo, cfg)
+		require.NoError(t, err)
+		w.now = func() time.Time { return now }
+		w.Register("test", func(ctx context.Context, payload []byte) error {
+			return errUnexpected
+		})
+
+		w.process(context.Background(), job)
+	})
+
+	t.Run("error handler on last attempt moves job to dead-letter", func(t *testing.T) {
+		mockRepo := mocks.NewRepository(t)
+		job := &entity.Job{ID: 1, Type: "test", Attempts: 3, MaxAttempts: 3}
+
+		mockRepo.EXPECT().Bury(mock.Anything, job, errUnexpected).Return(nil).Once()
+
+		w, err := NewWorker(mockRepo, cfg)
+		require.NoError(t, err)
+		w.Register("test", func(ctx context.Context, payload []byte) error {
+			return errUnexpected
+		})
+
+		w.process(context.Background(), job)
+	})
+
+	t.Run("permanent error moves job to dead-letter", func(t *testing.T) {
+		mockRepo := mocks.NewRepository(t)
+		job := &entity.Job{ID: 1, Type: "test", Attempts: 1, MaxAttempts: 3}
+		perr := Permanent(errUnexpected)
+
+		mockRepo.EXPECT().Bury(mock.Anything, job, perr).Return(nil).Once()
+
+		w, err := NewWorker(mockRepo, cfg)
+		require.NoError(t, err)
+		w.Register("test", func(ctx context.Context, payload []byte) error {
+			return perr
+		})
+
+		w.process(context.Background(), job)
+	})
+
+	t.Run("not retryable error moves job to dead-letter", func(t *testing.T) {
+		mockRepo := mocks.NewRepository(t)
+		job := &entity.Job{ID: 1, Type: "test", Attempts: 1, MaxAttempts: 3}
+		apiErr := fmt.Errorf("failed to create room tag: %w", &testRetryableError{retryable: false})
+
+		mockRepo.EXPECT().Bury(mock.Anything, job, apiErr).Return(nil).Once()
+
+		w, err := NewWorker(mockRepo, cfg)
+		require.NoError(t, err)
+		w.Register("test", func(ctx context.Context, payload []byte) error {
+			return apiErr
+		})
+
+		w.process(context.Background(), job)
+	})
+
+	t.Run("retryable error schedules retry", func(t *testing.T) {
+		mockRepo := mocks.NewRepository(t)
+		job := &entity.Job{ID: 1, Type: "test", Attempts: 1, MaxAttempts: 3}
+		apiErr := &testRetryableError{retryable: true}
+
+		mockRepo.EXPECT().Retry(mock.Anything, job, now.Add(10*time.Second), apiErr).Return(nil).Once()
+
+		w, err := NewWorker(mockRepo, cfg)
+		require.NoError(t, err)
+		w.now = func() time.Time { return now }
+		w.Register("test", func(ctx context.Context, payload []byte) error {
+			return apiErr
+		})
+
+		w.process(context.Background(), job)
+	})
+
+	t.Run("unknown job type moves job to dead-letter", func(t *testing.T) {
+		mockRepo := mocks.NewRepository(t)
+		job := &entity.Job{ID: 1, Type: "unknown", Attempts: 1, MaxAttempts: 3}
+
+		mockRepo.EXPECT().Bury(mock.Anything, job, mock.AnythingOfType("*queue.permanentError")).Return(nil).Once()
+
+		w, err := NewWorker(mockRepo, cfg)
+		require.NoError(t, err)
+		w.process(context.Background(), job)
+	})
+
+	t.Run("panic handler schedules retry", func(t *testing.T) {
+		mockRepo := mocks.NewRepository(t)
+		job := &entity.Job{ID: 1, Type: "test", Attempts: 1, MaxAttempts: 3}
+
+		mockRepo.EXPECT().Retry(mock.Anything, job, now.Add(10*time.Second), fmt.Errorf("panic: boom")).Return(nil).Once()
+
+		w, err := NewWorker(mockRepo, cfg)
+		require.NoError(t, err)
+		w.now = func() time.Time { return now }
+		w.Register("test", func(ctx context.Context, payload []byte) error {
+			panic("boom")
+		})
+
+		w.process(context.Background(), job)
+	})
+}
+
+func TestWorker_Process_Timeout(t *testing.T) {
+	cfg := config.Worker{
+		Concurrency: 1,
+		Backoff:     10 * time.Second,
+		LockTimeout: time.Minute,
+		JobTimeout:  10 * time.Millisecond,
+	}
+
+	mockRepo := mocks.NewRepository(t)
+	job := &entity.Job{ID: 1, Type: "test", Attempts: 1, MaxAttempts: 3}
+
+	// The handler used up its timeout, the job is still rescheduled
+	notCanceled := mock.MatchedBy(func(ctx context.Context) bool { return ctx.Err() == nil })
+	mockRepo.EXPECT().Retry(notCanceled, job, mock.AnythingOfType("time.Time"), context.DeadlineExceeded).Return(nil).Once()
+
+	w, err := NewWorker(mockRepo, cfg)
+	require.NoError(t, err)
+	w.Register("test", func(ctx context.Context, payload []byte) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	w.process(context.Background(), job)
+}
+
+func TestNewWorker(t *testing.T) {
+	_, err := NewWorker(nil, config.Worker{LockTimeout: 5 * time.Minute, JobTimeout: 4 * time.Minute})
+	assert.NoError(t, err)
+
+	_, err = NewWorker(nil, config.Worker{LockTimeout: 5 * time.Minute, JobTimeout: 5 * time.Minute})
+	assert.EqualError(t, err, "job timeout 5m0s must be positive and shorter than the lock timeout 5m0s by more than 10s")
+
+	_, err = NewWorker(nil, config.Worker{LockTimeout: 5 * time.Minute})
+	assert.Error(t, err)
+}
+
+func TestWorker_Backoff(t *testing.T) {
+	w, err := NewWorker(nil, config.Worker{Backoff: 10 * time.Second, LockTimeout: time.Minute, JobTimeout: 30 * time.Second})
+	require.NoError(t, err)
+
+	assert.Equal(t, 10*time.Second, w.backoff(1))
+	assert.Equal(t, 20*time.Second, w.backoff(2))
+	assert.Equal(t, 40*time.Second, w.backoff(3))
+	assert.Equal(t, maxBackoff, w.backoff(20))
+}
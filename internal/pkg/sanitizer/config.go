@@ -104,14 +104,16 @@ func defaultSensitiveFields() map[string]struct{} {
 // defaultSensitiveHeaders returns static list of sensitive headers
 func defaultSensitiveHeaders() map[string]struct{} {
 	return map[string]struct{}{
-		"authorization":       {},
-		"qiscus-secret-key":   {},
-		"qiscus-app-secret":   {},
-		"x-api-key":           {},
-		"x-auth-token":        {},
-		"cookie":              {},
-		"set-cookie":          {},
-		"proxy-authorization": {},
-		"www-authenticate":    {},
+		"authorization":           {},
+		"qiscus-secret-key":       {},
+		"qiscus-app-secret":       {},
+		"x-api-key":               {},
+		"x-auth-token":            {},
+		"x-qiscus-signature":      {},
+		"x-qiscus-webhook-secret": {},
+		"cookie":                  {},
+		"set-cookie":              {},
+		"proxy-authorization":     {},
+		"www-authenticate":        {},
 	}
 }
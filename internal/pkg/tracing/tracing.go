@@ -0,0 +1,120 @@
+// Package tracing sets up OpenTelemetry tracing and the W3C trace context propagation
+// shared by the HTTP ingress, the outbound client, the database, the worker and the cron.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"integration-go/internal/pkg/config"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+const instrumentationName = "integration-go"
+
+const traceParentHeader = "traceparent"
+
+// Setup installs the W3C trace context propagator and, unless the exporter is none, a
+// tracer provider exporting the spans of process, e.g. "api". The returned function
+// flushes the pending spans and must be called before the process exits.
+//
+// Without an exporter no span is recorded, but an incoming trace context is still
+// propagated to the outbound requests and jobs.
+func Setup(ctx context.Context, cfg config.Tracing, process string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cfg.Exporter {
+	case ExporterNone, "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterOTLP:
+		// The endpoint, headers and TLS are read from the standard OTEL_EXPORTER_OTLP_*
+		// environment variables
+		exporter, err = otlptracehttp.New(ctx)
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", cfg.Exporter)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s exporter: %w", cfg.Exporter, err)
+	}
+
+	res := resource.NewSchemaless(
+		semconv.ServiceName(cfg.ServiceName),
+		attribute.String("process", process),
+	)
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the tracer of the application, from the global provider installed by
+// Setup.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Inject writes the trace context of ctx into the headers of an outbound request.
+func Inject(ctx context.Context, header propagation.TextMapCarrier) {
+	otel.GetTextMapPropagator().Inject(ctx, header)
+}
+
+// Extract returns ctx carrying the trace context of an incoming request.
+func Extract(ctx context.Context, header propagation.TextMapCarrier) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, header)
+}
+
+// TraceParent returns the W3C traceparent of the span in ctx, to be stored with work
+// continued later, e.g. a job. It is empty without a valid span.
+func TraceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+
+	return carrier.Get(traceParentHeader)
+}
+
+// WithTraceParent returns ctx continuing the trace of traceParent, as returned by
+// TraceParent. An empty or invalid traceParent leaves ctx unchanged.
+func WithTraceParent(ctx context.Context, traceParent string) context.Context {
+	if traceParent == "" {
+		return ctx
+	}
+
+	carrier := propagation.MapCarrier{traceParentHeader: traceParent}
+	return propagation.TraceContext{}.Extract(ctx, carrier)
+}
+
+// TraceID returns the trace ID of the span in ctx, to correlate logs with traces. It is
+// empty without a valid span.
+func TraceID(ctx context.Context) string {
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if !spanCtx.HasTraceID() {
+		return ""
+	}
+
+	return spanCtx.TraceID().String()
+}
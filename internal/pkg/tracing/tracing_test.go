@@ -0,0 +1,49 @@
+package tracing
+
+import (
+	"context"
+	"integration-go/internal/pkg/config"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetup(t *testing.T) {
+	tests := []struct {
+		name        string
+		exporter    string
+		expectedErr string
+	}{
+		{name: "none", exporter: ExporterNone},
+		{name: "empty is none", exporter: ""},
+		{name: "stdout", exporter: ExporterStdout},
+		{name: "unknown", exporter: "jaeger", expectedErr: `unknown tracing exporter "jaeger"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shutdown, err := Setup(context.Background(), config.Tracing{Exporter: tt.exporter, ServiceName: "test", SampleRatio: 1}, "api")
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NoError(t, shutdown(context.Background()))
+		})
+	}
+}
+
+func TestTraceParent(t *testing.T) {
+	traceParent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	ctx := WithTraceParent(context.Background(), traceParent)
+	assert.Equal(t, traceParent, TraceParent(ctx))
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", TraceID(ctx))
+
+	// No or an invalid trace context
+	assert.Empty(t, TraceParent(context.Background()))
+	assert.Empty(t, TraceID(WithTraceParent(context.Background(), "")))
+	assert.Empty(t, TraceID(WithTraceParent(context.Background(), "00-invalid")))
+}
@@ -0,0 +1,54 @@
+// Package validate decodes request payloads and checks them against their validate tags.
+// Failures are validator.ValidationErrors, written by resp.WriteJSONFromError as a 400
+// listing each failing field.
+package validate
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	// Report fields by their JSON name, as the client sent them
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			return field.Name
+		}
+
+		return name
+	})
+
+	return v
+}
+
+// Struct validates v against its validate tags.
+func Struct(v any) error {
+	return validate.Struct(v)
+}
+
+// DecodeJSON decodes the JSON body into v and validates it.
+func DecodeJSON(r io.Reader, v any) error {
+	if err := json.NewDecoder(r).Decode(v); err != nil {
+		return err
+	}
+
+	return Struct(v)
+}
+
+// Unmarshal decodes the JSON payload into v and validates it.
+func Unmarshal(data []byte, v any) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+
+	return Struct(v)
+}
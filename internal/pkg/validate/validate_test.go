@@ -0,0 +1,94 @@
+package validate
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRequest struct {
+	Name  string `json:"name" validate:"required"`
+	Email string `json:"email,omitempty" validate:"omitempty,email"`
+	Room  struct {
+		ID string `json:"id_str" validate:"required"`
+	} `json:"room"`
+	Internal string `json:"-" validate:"required"`
+	Untagged string `validate:"required"`
+}
+
+func TestDecodeJSON(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedFields []string
+		expectedErr    bool
+	}{
+		{
+			name: "valid",
+			body: `{"name":"john","room":{"id_str":"room-1"}}`,
+		},
+		{
+			name:           "missing fields reported by json name",
+			body:           `{"email":"john"}`,
+			expectedFields: []string{"testRequest.name", "testRequest.email", "testRequest.room.id_str"},
+		},
+		{
+			name:        "invalid json",
+			body:        `{`,
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := testRequest{Internal: "set", Untagged: "set"}
+			err := DecodeJSON(strings.NewReader(tt.body), &req)
+
+			if tt.expectedErr {
+				require.Error(t, err)
+				assert.False(t, errors.As(err, new(validator.ValidationErrors)))
+				return
+			}
+
+			if tt.expectedFields == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			var validationErrs validator.ValidationErrors
+			require.ErrorAs(t, err, &validationErrs)
+
+			fields := make([]string, 0, len(validationErrs))
+			for _, fieldErr := range validationErrs {
+				fields = append(fields, fieldErr.Namespace())
+			}
+			assert.Equal(t, tt.expectedFields, fields)
+		})
+	}
+}
+
+func TestStruct_FieldNames(t *testing.T) {
+	req := testRequest{Name: "john"}
+	req.Room.ID = "room-1"
+
+	var validationErrs validator.ValidationErrors
+	require.ErrorAs(t, Struct(&req), &validationErrs)
+	require.Len(t, validationErrs, 2)
+
+	// Fields hidden from JSON or untagged keep their Go name
+	assert.Equal(t, "testRequest.Internal", validationErrs[0].Namespace())
+	assert.Equal(t, "testRequest.Untagged", validationErrs[1].Namespace())
+}
+
+func TestUnmarshal(t *testing.T) {
+	var req testRequest
+	err := Unmarshal([]byte(`{"name":"john","room":{}}`), &req)
+
+	var validationErrs validator.ValidationErrors
+	require.ErrorAs(t, err, &validationErrs)
+	assert.Equal(t, "required", validationErrs[0].Tag())
+}
@@ -0,0 +1,104 @@
+package worker
+
+import (
+	"context"
+	"integration-go/internal/allocation"
+	"integration-go/internal/deadletter"
+	"integration-go/internal/pkg/client"
+	"integration-go/internal/pkg/config"
+	"integration-go/internal/pkg/postgres"
+	"integration-go/internal/pkg/qismo"
+	"integration-go/internal/pkg/queue"
+	"integration-go/internal/pkg/redis"
+	"integration-go/internal/pkg/tracing"
+	"integration-go/internal/resolver"
+	"integration-go/internal/room"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+func NewServer() *Server {
+	cfg := config.Load()
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, "worker")
+	if err != nil {
+		log.Fatal().Msgf("unable to set up tracing: %s", err.Error())
+	}
+
+	db := postgres.NewGORM(cfg.Database)
+	postgres.CheckSchema(db)
+	rdb := redis.New(cfg.Redis.URL)
+
+	client := client.NewFromConfig(cfg.Client)
+	qismo := qismo.New(client, cfg.Qiscus.Omnichannel.URL, cfg.Qiscus.SDKURL, cfg.Qiscus.AppID, cfg.Qiscus.SecretKey)
+	qismo.SetRateLimit(cfg.Qiscus.RateLimit, cfg.Qiscus.RateBurst)
+
+	queueRepo := queue.NewRepository(db, cfg.Worker.MaxAttempts)
+	worker, err := queue.NewWorker(queueRepo, cfg.Worker)
+	if err != nil {
+		log.Fatal().Msgf("unable to create worker: %s", err.Error())
+	}
+
+	// Room
+	roomRepo := room.NewRepository(db)
+	roomSvc := room.NewService(roomRepo, qismo, queueRepo)
+	roomJobHandler := room.NewJobHandler(roomSvc)
+	worker.Register(room.JobCreateRoom, roomJobHandler.CreateRoom)
+
+	// Resolver
+	deadLetterRepo := deadletter.NewRepository(db, cfg.Worker.MaxAttempts)
+	resolverPolicy, err := resolver.NewPolicy(cfg.Resolver)
+	if err != nil {
+		log.Fatal().Msgf("unable to create resolver policy: %s", err.Error())
+	}
+
+	resolverSvc := resolver.NewService(roomRepo, qismo, deadLetterRepo, resolver.NewRepository(rdb), resolverPolicy)
+	resolverJobHandler := resolver.NewJobHandler(resolverSvc)
+	worker.Register(resolver.JobResolveRoom, resolverJobHandler.ResolveRoom)
+
+	// Allocation
+	allocationRepo := allocation.NewRepository(db, rdb)
+	allocationStrategy, err := allocation.NewStrategy(cfg.Allocation.Strategy, cfg.Allocation.Divisions, allocationRepo, qismo)
+	if err != nil {
+		log.Fatal().Msgf("unable to create allocation strategy: %s", err.Error())
+	}
+
+	allocationSvc := allocation.NewService(allocationRepo, roomRepo, qismo, queueRepo, allocationStrategy, cfg.Allocation.MaxCustomers)
+	allocationJobHandler := allocation.NewJobHandler(allocationSvc)
+	worker.Register(allocation.JobAllocateAgent, allocationJobHandler.AllocateAgent)
+
+	return &Server{
+		worker:          worker,
+		shutdownTracing: shutdownTracing,
+	}
+}
+
+type Server struct {
+	worker *queue.Worker
+	// shutdownTracing flushes the pending spans.
+	shutdownTracing func(context.Context) error
+}
+
+// Run starts the worker pool and blocks until SIGINT or SIGTERM is received
+// and every in-flight job has finished.
+func (s *Server) Run() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Info().Msg("worker is started")
+
+	s.worker.Run(ctx)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.shutdownTracing(shutdownCtx); err != nil {
+		log.Error().Msgf("failed to flush traces: %s", err.Error())
+	}
+
+	log.Info().Msg("worker stopped")
+}
@@ -0,0 +1,41 @@
+package resolver
+
+import (
+	"context"
+	"time"
+)
+
+type cronJob struct {
+	svc *Service
+}
+
+// NewCronJob returns the job that resolves expired rooms, to be registered in the
+// cron server.
+func NewCronJob(svc *Service) *cronJob {
+	return &cronJob{
+		svc: svc,
+	}
+}
+
+func (j *cronJob) Name() string {
+	return "resolver"
+}
+
+func (j *cronJob) Schedule() string {
+	return "* * * * *"
+}
+
+func (j *cronJob) Timeout() time.Duration {
+	return j.svc.policy.RunTimeout()
+}
+
+func (j *cronJob) Run(ctx context.Context) (map[string]int, error) {
+	stats, err := j.svc.ResolvedOmnichannelRoom(ctx)
+
+	return map[string]int{
+		"processed": stats.Processed,
+		"resolved":  stats.Resolved,
+		"failed":    stats.Failed,
+		"skipped":   stats.Skipped,
+	}, err
+}
@@ -0,0 +1,42 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"integration-go/internal/pkg/qismo"
+	"integration-go/internal/pkg/queue"
+)
+
+const (
+	JobResolveRoom = "room.resolve"
+)
+
+type ResolveRoomPayload struct {
+	MultichannelRoomID string `json:"multichannel_room_id"`
+}
+
+type jobHandler struct {
+	svc *Service
+}
+
+func NewJobHandler(svc *Service) *jobHandler {
+	return &jobHandler{
+		svc: svc,
+	}
+}
+
+func (h *jobHandler) ResolveRoom(ctx context.Context, payload []byte) error {
+	var p ResolveRoomPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return queue.Permanent(err)
+	}
+
+	err := h.svc.ResolveRoom(ctx, p.MultichannelRoomID)
+	// A room missing in Omnichannel will not appear on retry, dead-letter the job right away
+	if errors.Is(err, qismo.ErrRoomNotFound) {
+		return queue.Permanent(err)
+	}
+
+	return err
+}
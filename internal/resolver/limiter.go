@@ -0,0 +1,72 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"integration-go/internal/pkg/qismo"
+
+	"golang.org/x/time/rate"
+)
+
+// errLimiterWait is returned when ctx is done, or would be before a token is available,
+// while waiting for the limiter. The request was not sent.
+var errLimiterWait = errors.New("rate limiter wait interrupted")
+
+// rateLimitedOmnichannel waits for the limiter before every Omnichannel request, so
+// concurrent resolutions stay within the Qiscus API quota.
+type rateLimitedOmnichannel struct {
+	omni    Omnichannel
+	limiter *rate.Limiter
+}
+
+func newRateLimitedOmnichannel(omni Omnichannel, limiter *rate.Limiter) Omnichannel {
+	if limiter == nil {
+		return omni
+	}
+
+	return &rateLimitedOmnichannel{
+		omni:    omni,
+		limiter: limiter,
+	}
+}
+
+func (o *rateLimitedOmnichannel) ResolvedRoom(ctx context.Context, roomID string) error {
+	if err := o.wait(ctx); err != nil {
+		return err
+	}
+
+	return o.omni.ResolvedRoom(ctx, roomID)
+}
+
+func (o *rateLimitedOmnichannel) GetRoomInfo(ctx context.Context, roomID string) (*qismo.CustomerRoom, error) {
+	if err := o.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	return o.omni.GetRoomInfo(ctx, roomID)
+}
+
+func (o *rateLimitedOmnichannel) GetRoomTags(ctx context.Context, roomID string) ([]qismo.RoomTag, error) {
+	if err := o.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	return o.omni.GetRoomTags(ctx, roomID)
+}
+
+func (o *rateLimitedOmnichannel) SendMessageAsBot(ctx context.Context, req *qismo.SendMessageRequest) error {
+	if err := o.wait(ctx); err != nil {
+		return err
+	}
+
+	return o.omni.SendMessageAsBot(ctx, req)
+}
+
+func (o *rateLimitedOmnichannel) wait(ctx context.Context) error {
+	if err := o.limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("%w: %w", errLimiterWait, err)
+	}
+
+	return nil
+}
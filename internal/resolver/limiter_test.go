@@ -0,0 +1,31 @@
+package resolver
+
+import (
+	"context"
+	"integration-go/internal/resolver/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/time/rate"
+)
+
+func TestRateLimitedOmnichannel(t *testing.T) {
+	mockOmni := mocks.NewOmnichannel(t)
+
+	assert.Equal(t, mockOmni, newRateLimitedOmnichannel(mockOmni, nil))
+
+	omni := newRateLimitedOmnichannel(mockOmni, rate.NewLimiter(rate.Limit(1), 1))
+
+	mockOmni.EXPECT().ResolvedRoom(mock.Anything, "room-123").Return(nil).Once()
+	err := omni.ResolvedRoom(context.Background(), "room-123")
+	assert.Nil(t, err)
+
+	// The bucket is empty, the next request waits longer than the context allows
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = omni.ResolvedRoom(ctx, "room-456")
+	assert.ErrorIs(t, err, errLimiterWait)
+	assert.ErrorIs(t, err, context.Canceled)
+}
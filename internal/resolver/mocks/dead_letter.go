@@ -0,0 +1,84 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entity "integration-go/internal/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DeadLetter is an autogenerated mock type for the DeadLetter type
+type DeadLetter struct {
+	mock.Mock
+}
+
+type DeadLetter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *DeadLetter) EXPECT() *DeadLetter_Expecter {
+	return &DeadLetter_Expecter{mock: &_m.Mock}
+}
+
+// Record provides a mock function with given fields: ctx, deadJob
+func (_m *DeadLetter) Record(ctx context.Context, deadJob *entity.DeadJob) error {
+	ret := _m.Called(ctx, deadJob)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Record")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.DeadJob) error); ok {
+		r0 = rf(ctx, deadJob)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeadLetter_Record_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Record'
+type DeadLetter_Record_Call struct {
+	*mock.Call
+}
+
+// Record is a helper method to define mock.On call
+//   - ctx context.Context
+//   - deadJob *entity.DeadJob
+func (_e *DeadLetter_Expecter) Record(ctx interface{}, deadJob interface{}) *DeadLetter_Record_Call {
+	return &DeadLetter_Record_Call{Call: _e.mock.On("Record", ctx, deadJob)}
+}
+
+func (_c *DeadLetter_Record_Call) Run(run func(ctx context.Context, deadJob *entity.DeadJob)) *DeadLetter_Record_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.DeadJob))
+	})
+	return _c
+}
+
+func (_c *DeadLetter_Record_Call) Return(_a0 error) *DeadLetter_Record_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *DeadLetter_Record_Call) RunAndReturn(run func(context.Context, *entity.DeadJob) error) *DeadLetter_Record_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewDeadLetter creates a new instance of DeadLetter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDeadLetter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DeadLetter {
+	mock := &DeadLetter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -1,9 +1,10 @@
-// Code generated by mockery v2.43.2. DO NOT EDIT.
+// Code generated by mockery v2.53.4. DO NOT EDIT.
 
 package mocks
 
 import (
 	context "context"
+	qismo "integration-go/internal/pkg/qismo"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -21,6 +22,124 @@ func (_m *Omnichannel) EXPECT() *Omnichannel_Expecter {
 	return &Omnichannel_Expecter{mock: &_m.Mock}
 }
 
+// GetRoomInfo provides a mock function with given fields: ctx, roomID
+func (_m *Omnichannel) GetRoomInfo(ctx context.Context, roomID string) (*qismo.CustomerRoom, error) {
+	ret := _m.Called(ctx, roomID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRoomInfo")
+	}
+
+	var r0 *qismo.CustomerRoom
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*qismo.CustomerRoom, error)); ok {
+		return rf(ctx, roomID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *qismo.CustomerRoom); ok {
+		r0 = rf(ctx, roomID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*qismo.CustomerRoom)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, roomID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Omnichannel_GetRoomInfo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRoomInfo'
+type Omnichannel_GetRoomInfo_Call struct {
+	*mock.Call
+}
+
+// GetRoomInfo is a helper method to define mock.On call
+//   - ctx context.Context
+//   - roomID string
+func (_e *Omnichannel_Expecter) GetRoomInfo(ctx interface{}, roomID interface{}) *Omnichannel_GetRoomInfo_Call {
+	return &Omnichannel_GetRoomInfo_Call{Call: _e.mock.On("GetRoomInfo", ctx, roomID)}
+}
+
+func (_c *Omnichannel_GetRoomInfo_Call) Run(run func(ctx context.Context, roomID string)) *Omnichannel_GetRoomInfo_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Omnichannel_GetRoomInfo_Call) Return(_a0 *qismo.CustomerRoom, _a1 error) *Omnichannel_GetRoomInfo_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Omnichannel_GetRoomInfo_Call) RunAndReturn(run func(context.Context, string) (*qismo.CustomerRoom, error)) *Omnichannel_GetRoomInfo_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRoomTags provides a mock function with given fields: ctx, roomID
+func (_m *Omnichannel) GetRoomTags(ctx context.Context, roomID string) ([]qismo.RoomTag, error) {
+	ret := _m.Called(ctx, roomID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRoomTags")
+	}
+
+	var r0 []qismo.RoomTag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]qismo.RoomTag, error)); ok {
+		return rf(ctx, roomID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []qismo.RoomTag); ok {
+		r0 = rf(ctx, roomID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]qismo.RoomTag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, roomID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Omnichannel_GetRoomTags_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRoomTags'
+type Omnichannel_GetRoomTags_Call struct {
+	*mock.Call
+}
+
+// GetRoomTags is a helper method to define mock.On call
+//   - ctx context.Context
+//   - roomID string
+func (_e *Omnichannel_Expecter) GetRoomTags(ctx interface{}, roomID interface{}) *Omnichannel_GetRoomTags_Call {
+	return &Omnichannel_GetRoomTags_Call{Call: _e.mock.On("GetRoomTags", ctx, roomID)}
+}
+
+func (_c *Omnichannel_GetRoomTags_Call) Run(run func(ctx context.Context, roomID string)) *Omnichannel_GetRoomTags_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Omnichannel_GetRoomTags_Call) Return(_a0 []qismo.RoomTag, _a1 error) *Omnichannel_GetRoomTags_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Omnichannel_GetRoomTags_Call) RunAndReturn(run func(context.Context, string) ([]qismo.RoomTag, error)) *Omnichannel_GetRoomTags_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ResolvedRoom provides a mock function with given fields: ctx, roomID
 func (_m *Omnichannel) ResolvedRoom(ctx context.Context, roomID string) error {
 	ret := _m.Called(ctx, roomID)
@@ -68,6 +187,53 @@ func (_c *Omnichannel_ResolvedRoom_Call) RunAndReturn(run func(context.Context,
 	return _c
 }
 
+// SendMessageAsBot provides a mock function with given fields: ctx, req
+func (_m *Omnichannel) SendMessageAsBot(ctx context.Context, req *qismo.SendMessageRequest) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendMessageAsBot")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *qismo.SendMessageRequest) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Omnichannel_SendMessageAsBot_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendMessageAsBot'
+type Omnichannel_SendMessageAsBot_Call struct {
+	*mock.Call
+}
+
+// SendMessageAsBot is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req *qismo.SendMessageRequest
+func (_e *Omnichannel_Expecter) SendMessageAsBot(ctx interface{}, req interface{}) *Omnichannel_SendMessageAsBot_Call {
+	return &Omnichannel_SendMessageAsBot_Call{Call: _e.mock.On("SendMessageAsBot", ctx, req)}
+}
+
+func (_c *Omnichannel_SendMessageAsBot_Call) Run(run func(ctx context.Context, req *qismo.SendMessageRequest)) *Omnichannel_SendMessageAsBot_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*qismo.SendMessageRequest))
+	})
+	return _c
+}
+
+func (_c *Omnichannel_SendMessageAsBot_Call) Return(_a0 error) *Omnichannel_SendMessageAsBot_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Omnichannel_SendMessageAsBot_Call) RunAndReturn(run func(context.Context, *qismo.SendMessageRequest) error) *Omnichannel_SendMessageAsBot_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewOmnichannel creates a new instance of Omnichannel. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewOmnichannel(t interface {
@@ -1,4 +1,4 @@
-// Code generated by mockery v2.43.2. DO NOT EDIT.
+// Code generated by mockery v2.53.4. DO NOT EDIT.
 
 package mocks
 
@@ -7,6 +7,8 @@ import (
 	entity "integration-go/internal/entity"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // RoomRepository is an autogenerated mock type for the RoomRepository type
@@ -69,29 +71,90 @@ func (_c *RoomRepository_DeleteBy_Call) RunAndReturn(run func(context.Context, m
 	return _c
 }
 
-// Fetch provides a mock function with given fields: ctx
-func (_m *RoomRepository) Fetch(ctx context.Context) ([]entity.Room, error) {
-	ret := _m.Called(ctx)
+// FetchExpired provides a mock function with given fields: ctx, createdBefore, afterID, limit
+func (_m *RoomRepository) FetchExpired(ctx context.Context, createdBefore time.Time, afterID int64, limit int) ([]entity.Room, error) {
+	ret := _m.Called(ctx, createdBefore, afterID, limit)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Fetch")
+		panic("no return value specified for FetchExpired")
 	}
 
 	var r0 []entity.Room
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]entity.Room, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int64, int) ([]entity.Room, error)); ok {
+		return rf(ctx, createdBefore, afterID, limit)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []entity.Room); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int64, int) []entity.Room); ok {
+		r0 = rf(ctx, createdBefore, afterID, limit)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entity.Room)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int64, int) error); ok {
+		r1 = rf(ctx, createdBefore, afterID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RoomRepository_FetchExpired_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FetchExpired'
+type RoomRepository_FetchExpired_Call struct {
+	*mock.Call
+}
+
+// FetchExpired is a helper method to define mock.On call
+//   - ctx context.Context
+//   - createdBefore time.Time
+//   - afterID int64
+//   - limit int
+func (_e *RoomRepository_Expecter) FetchExpired(ctx interface{}, createdBefore interface{}, afterID interface{}, limit interface{}) *RoomRepository_FetchExpired_Call {
+	return &RoomRepository_FetchExpired_Call{Call: _e.mock.On("FetchExpired", ctx, createdBefore, afterID, limit)}
+}
+
+func (_c *RoomRepository_FetchExpired_Call) Run(run func(ctx context.Context, createdBefore time.Time, afterID int64, limit int)) *RoomRepository_FetchExpired_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(int64), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *RoomRepository_FetchExpired_Call) Return(_a0 []entity.Room, _a1 error) *RoomRepository_FetchExpired_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RoomRepository_FetchExpired_Call) RunAndReturn(run func(context.Context, time.Time, int64, int) ([]entity.Room, error)) *RoomRepository_FetchExpired_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByMultichannelRoomID provides a mock function with given fields: ctx, multichannelRoomID
+func (_m *RoomRepository) FindByMultichannelRoomID(ctx context.Context, multichannelRoomID string) (*entity.Room, error) {
+	ret := _m.Called(ctx, multichannelRoomID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByMultichannelRoomID")
+	}
+
+	var r0 *entity.Room
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.Room, error)); ok {
+		return rf(ctx, multichannelRoomID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.Room); ok {
+		r0 = rf(ctx, multichannelRoomID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Room)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, multichannelRoomID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -99,30 +162,129 @@ func (_m *RoomRepository) Fetch(ctx context.Context) ([]entity.Room, error) {
 	return r0, r1
 }
 
-// RoomRepository_Fetch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Fetch'
-type RoomRepository_Fetch_Call struct {
+// RoomRepository_FindByMultichannelRoomID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByMultichannelRoomID'
+type RoomRepository_FindByMultichannelRoomID_Call struct {
 	*mock.Call
 }
 
-// Fetch is a helper method to define mock.On call
+// FindByMultichannelRoomID is a helper method to define mock.On call
 //   - ctx context.Context
-func (_e *RoomRepository_Expecter) Fetch(ctx interface{}) *RoomRepository_Fetch_Call {
-	return &RoomRepository_Fetch_Call{Call: _e.mock.On("Fetch", ctx)}
+//   - multichannelRoomID string
+func (_e *RoomRepository_Expecter) FindByMultichannelRoomID(ctx interface{}, multichannelRoomID interface{}) *RoomRepository_FindByMultichannelRoomID_Call {
+	return &RoomRepository_FindByMultichannelRoomID_Call{Call: _e.mock.On("FindByMultichannelRoomID", ctx, multichannelRoomID)}
 }
 
-func (_c *RoomRepository_Fetch_Call) Run(run func(ctx context.Context)) *RoomRepository_Fetch_Call {
+func (_c *RoomRepository_FindByMultichannelRoomID_Call) Run(run func(ctx context.Context, multichannelRoomID string)) *RoomRepository_FindByMultichannelRoomID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context))
+		run(args[0].(context.Context), args[1].(string))
 	})
 	return _c
 }
 
-func (_c *RoomRepository_Fetch_Call) Return(_a0 []entity.Room, _a1 error) *RoomRepository_Fetch_Call {
+func (_c *RoomRepository_FindByMultichannelRoomID_Call) Return(_a0 *entity.Room, _a1 error) *RoomRepository_FindByMultichannelRoomID_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *RoomRepository_Fetch_Call) RunAndReturn(run func(context.Context) ([]entity.Room, error)) *RoomRepository_Fetch_Call {
+func (_c *RoomRepository_FindByMultichannelRoomID_Call) RunAndReturn(run func(context.Context, string) (*entity.Room, error)) *RoomRepository_FindByMultichannelRoomID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkClosingSent provides a mock function with given fields: ctx, multichannelRoomID, at
+func (_m *RoomRepository) MarkClosingSent(ctx context.Context, multichannelRoomID string, at time.Time) error {
+	ret := _m.Called(ctx, multichannelRoomID, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkClosingSent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, multichannelRoomID, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RoomRepository_MarkClosingSent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkClosingSent'
+type RoomRepository_MarkClosingSent_Call struct {
+	*mock.Call
+}
+
+// MarkClosingSent is a helper method to define mock.On call
+//   - ctx context.Context
+//   - multichannelRoomID string
+//   - at time.Time
+func (_e *RoomRepository_Expecter) MarkClosingSent(ctx interface{}, multichannelRoomID interface{}, at interface{}) *RoomRepository_MarkClosingSent_Call {
+	return &RoomRepository_MarkClosingSent_Call{Call: _e.mock.On("MarkClosingSent", ctx, multichannelRoomID, at)}
+}
+
+func (_c *RoomRepository_MarkClosingSent_Call) Run(run func(ctx context.Context, multichannelRoomID string, at time.Time)) *RoomRepository_MarkClosingSent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *RoomRepository_MarkClosingSent_Call) Return(_a0 error) *RoomRepository_MarkClosingSent_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *RoomRepository_MarkClosingSent_Call) RunAndReturn(run func(context.Context, string, time.Time) error) *RoomRepository_MarkClosingSent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Transition provides a mock function with given fields: ctx, multichannelRoomID, to, actor, note
+func (_m *RoomRepository) Transition(ctx context.Context, multichannelRoomID string, to entity.RoomStatus, actor string, note string) error {
+	ret := _m.Called(ctx, multichannelRoomID, to, actor, note)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Transition")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, entity.RoomStatus, string, string) error); ok {
+		r0 = rf(ctx, multichannelRoomID, to, actor, note)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RoomRepository_Transition_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Transition'
+type RoomRepository_Transition_Call struct {
+	*mock.Call
+}
+
+// Transition is a helper method to define mock.On call
+//   - ctx context.Context
+//   - multichannelRoomID string
+//   - to entity.RoomStatus
+//   - actor string
+//   - note string
+func (_e *RoomRepository_Expecter) Transition(ctx interface{}, multichannelRoomID interface{}, to interface{}, actor interface{}, note interface{}) *RoomRepository_Transition_Call {
+	return &RoomRepository_Transition_Call{Call: _e.mock.On("Transition", ctx, multichannelRoomID, to, actor, note)}
+}
+
+func (_c *RoomRepository_Transition_Call) Run(run func(ctx context.Context, multichannelRoomID string, to entity.RoomStatus, actor string, note string)) *RoomRepository_Transition_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(entity.RoomStatus), args[3].(string), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *RoomRepository_Transition_Call) Return(_a0 error) *RoomRepository_Transition_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *RoomRepository_Transition_Call) RunAndReturn(run func(context.Context, string, entity.RoomStatus, string, string) error) *RoomRepository_Transition_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,131 @@
+package resolver
+
+import (
+	"fmt"
+	"integration-go/internal/entity"
+	"integration-go/internal/pkg/config"
+	"integration-go/internal/pkg/qismo"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	ModeCreated = "created"
+	ModeIdle    = "idle"
+)
+
+// Policy decides when a room is resolved automatically.
+type Policy struct {
+	cfg config.Resolver
+}
+
+func NewPolicy(cfg config.Resolver) (*Policy, error) {
+	if cfg.Mode != ModeCreated && cfg.Mode != ModeIdle {
+		return nil, fmt.Errorf("unknown resolver mode %q", cfg.Mode)
+	}
+
+	if cfg.BatchSize <= 0 || cfg.MaxRoomsPerRun <= 0 || cfg.Concurrency <= 0 {
+		return nil, fmt.Errorf("batch size, max rooms per run and concurrency must be positive")
+	}
+
+	if cfg.ClosingMessage != "" && cfg.ClosingMessageSender == "" {
+		return nil, fmt.Errorf("closing message sender is required when closing message is set")
+	}
+
+	return &Policy{cfg: cfg}, nil
+}
+
+// MinTimeout is the shortest timeout of any room. Rooms created more recently than
+// MinTimeout ago can never be expired, whatever their channel, tags or activity.
+func (p *Policy) MinTimeout() time.Duration {
+	shortest := p.cfg.Timeout
+	for _, timeout := range p.cfg.ChannelTimeouts {
+		shortest = min(shortest, timeout)
+	}
+
+	for _, timeout := range p.cfg.TagTimeouts {
+		shortest = min(shortest, timeout)
+	}
+
+	return shortest
+}
+
+// NeedsRoomInfo reports whether the Omnichannel room info is needed to evaluate a room.
+func (p *Policy) NeedsRoomInfo() bool {
+	return p.cfg.Mode == ModeIdle || len(p.cfg.ChannelTimeouts) > 0
+}
+
+// NeedsTags reports whether the room tags are needed to evaluate a room.
+func (p *Policy) NeedsTags() bool {
+	return len(p.cfg.TagTimeouts) > 0
+}
+
+// Timeout returns the timeout of a room. The shortest override of the room tags wins,
+// then the override of the room channel, then the default timeout.
+func (p *Policy) Timeout(source string, tags []qismo.RoomTag) time.Duration {
+	var timeout time.Duration
+	for _, tag := range tags {
+		if t, ok := p.cfg.TagTimeouts[tag.Name]; ok && (timeout == 0 || t < timeout) {
+			timeout = t
+		}
+	}
+
+	if timeout > 0 {
+		return timeout
+	}
+
+	if t, ok := p.cfg.ChannelTimeouts[source]; ok {
+		return t
+	}
+
+	return p.cfg.Timeout
+}
+
+// IsExpired reports whether the room is due to be resolved at now. The info may be nil
+// when NeedsRoomInfo is false.
+func (p *Policy) IsExpired(room *entity.Room, info *qismo.CustomerRoom, tags []qismo.RoomTag, now time.Time) bool {
+	since := room.CreatedAt
+	source := ""
+	if info != nil {
+		source = info.Source
+		if p.cfg.Mode == ModeIdle && info.LastCustomerTimestamp != nil {
+			since = *info.LastCustomerTimestamp
+		}
+	}
+
+	return now.Sub(since) >= p.Timeout(source, tags)
+}
+
+// ClosingMessage returns the message sent before resolving and its sender, the message
+// is empty when disabled.
+func (p *Policy) ClosingMessage() (message, sender string) {
+	return p.cfg.ClosingMessage, p.cfg.ClosingMessageSender
+}
+
+func (p *Policy) BatchSize() int {
+	return p.cfg.BatchSize
+}
+
+func (p *Policy) MaxRoomsPerRun() int {
+	return p.cfg.MaxRoomsPerRun
+}
+
+// Concurrency is at least one, so a zero value policy still resolves rooms.
+func (p *Policy) Concurrency() int {
+	return max(p.cfg.Concurrency, 1)
+}
+
+func (p *Policy) RunTimeout() time.Duration {
+	return p.cfg.RunTimeout
+}
+
+// Limiter returns the token bucket shared by every Omnichannel request of a run, nil when
+// the rate is unlimited.
+func (p *Policy) Limiter() *rate.Limiter {
+	if p.cfg.RateLimit <= 0 {
+		return nil
+	}
+
+	return rate.NewLimiter(rate.Limit(p.cfg.RateLimit), max(p.cfg.RateBurst, 1))
+}
@@ -0,0 +1,62 @@
+package resolver
+
+import (
+	"integration-go/internal/entity"
+	"integration-go/internal/pkg/config"
+	"integration-go/internal/pkg/qismo"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPolicy(t *testing.T) {
+	policy, err := NewPolicy(config.Resolver{Mode: ModeIdle, Timeout: time.Minute, BatchSize: 100, MaxRoomsPerRun: 1000, Concurrency: 4})
+	assert.Nil(t, err)
+	assert.NotNil(t, policy)
+
+	policy, err = NewPolicy(config.Resolver{Mode: ModeIdle, Timeout: time.Minute})
+	assert.EqualError(t, err, "batch size, max rooms per run and concurrency must be positive")
+	assert.Nil(t, policy)
+
+	policy, err = NewPolicy(config.Resolver{Mode: "forever"})
+	assert.EqualError(t, err, `unknown resolver mode "forever"`)
+	assert.Nil(t, policy)
+
+	policy, err = NewPolicy(config.Resolver{Mode: ModeCreated, BatchSize: 100, MaxRoomsPerRun: 1000, Concurrency: 4, ClosingMessage: "Bye"})
+	assert.EqualError(t, err, "closing message sender is required when closing message is set")
+	assert.Nil(t, policy)
+}
+
+func TestPolicy_Timeout(t *testing.T) {
+	policy := &Policy{cfg: config.Resolver{
+		Timeout:         10 * time.Minute,
+		ChannelTimeouts: map[string]time.Duration{"wa": 30 * time.Minute},
+		TagTimeouts:     map[string]time.Duration{"vip": 2 * time.Hour, "urgent": 5 * time.Minute},
+	}}
+
+	assert.Equal(t, 10*time.Minute, policy.Timeout("telegram", nil))
+	assert.Equal(t, 30*time.Minute, policy.Timeout("wa", nil))
+	assert.Equal(t, 2*time.Hour, policy.Timeout("wa", []qismo.RoomTag{{Name: "vip"}, {Name: "other"}}))
+	assert.Equal(t, 5*time.Minute, policy.Timeout("wa", []qismo.RoomTag{{Name: "vip"}, {Name: "urgent"}}))
+	assert.Equal(t, 5*time.Minute, policy.MinTimeout())
+	assert.True(t, policy.NeedsRoomInfo())
+	assert.True(t, policy.NeedsTags())
+}
+
+func TestPolicy_IsExpired(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	room := &entity.Room{CreatedAt: now.Add(-time.Hour)}
+	lastCustomerMessage := now.Add(-5 * time.Minute)
+	info := &qismo.CustomerRoom{LastCustomerTimestamp: &lastCustomerMessage}
+
+	created := &Policy{cfg: config.Resolver{Mode: ModeCreated, Timeout: 10 * time.Minute}}
+	assert.True(t, created.IsExpired(room, nil, nil, now))
+	assert.True(t, created.IsExpired(room, info, nil, now))
+	assert.False(t, created.NeedsRoomInfo())
+
+	idle := &Policy{cfg: config.Resolver{Mode: ModeIdle, Timeout: 10 * time.Minute}}
+	assert.False(t, idle.IsExpired(room, info, nil, now))
+	assert.True(t, idle.IsExpired(room, &qismo.CustomerRoom{}, nil, now))
+	assert.True(t, idle.NeedsRoomInfo())
+}
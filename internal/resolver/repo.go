@@ -0,0 +1,33 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type repo struct {
+	rdb *redis.Client
+}
+
+func NewRepository(rdb *redis.Client) *repo {
+	return &repo{
+		rdb: rdb,
+	}
+}
+
+// GetCursor returns the cursor stored in redis, or 0 when none is stored, so every cron
+// replica continues where the previous run stopped.
+func (r *repo) GetCursor(ctx context.Context, key string) (int64, error) {
+	id, err := r.rdb.Get(ctx, key).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+
+	return id, err
+}
+
+func (r *repo) SetCursor(ctx context.Context, key string, id int64) error {
+	return r.rdb.Set(ctx, key, id, 0).Err()
+}
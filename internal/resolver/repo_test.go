@@ -0,0 +1,35 @@
+package resolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepo_Cursor(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx := context.Background()
+
+	// Each replica, or a restarted one, has its own client on the same redis
+	first := NewRepository(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+	second := NewRepository(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+
+	id, err := first.GetCursor(ctx, cursorKey)
+	require.NoError(t, err)
+	assert.Zero(t, id)
+
+	require.NoError(t, first.SetCursor(ctx, cursorKey, 42))
+
+	id, err = second.GetCursor(ctx, cursorKey)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), id)
+
+	mr.Close()
+
+	_, err = second.GetCursor(ctx, cursorKey)
+	assert.Error(t, err)
+}
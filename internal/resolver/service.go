@@ -2,64 +2,395 @@ package resolver
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"integration-go/internal/entity"
+	"integration-go/internal/pkg/config"
+	"integration-go/internal/pkg/postgres"
+	"integration-go/internal/pkg/qismo"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
 )
 
+// actor is recorded as the actor of the room transitions made by the resolver.
+const actor = "resolver"
+
+// cursorKey holds the last room ID evaluated by a run that reached the per-run cap, next to
+// the cron lock of the resolver.
+const cursorKey = "cron:cursor:resolver"
+
 //go:generate mockery --with-expecter --case snake --name RoomRepository
 type RoomRepository interface {
-	Fetch(ctx context.Context) ([]entity.Room, error)
+	FetchExpired(ctx context.Context, createdBefore time.Time, afterID int64, limit int) ([]entity.Room, error)
+	FindByMultichannelRoomID(ctx context.Context, multichannelRoomID string) (*entity.Room, error)
+	Transition(ctx context.Context, multichannelRoomID string, to entity.RoomStatus, actor, note string) error
+	MarkClosingSent(ctx context.Context, multichannelRoomID string, at time.Time) error
 	DeleteBy(ctx context.Context, query map[string]any) error
 }
 
 //go:generate mockery --with-expecter --case snake --name Omnichannel
 type Omnichannel interface {
 	ResolvedRoom(ctx context.Context, roomID string) error
+	GetRoomInfo(ctx context.Context, roomID string) (*qismo.CustomerRoom, error)
+	GetRoomTags(ctx context.Context, roomID string) ([]qismo.RoomTag, error)
+	SendMessageAsBot(ctx context.Context, req *qismo.SendMessageRequest) error
+}
+
+//go:generate mockery --with-expecter --case snake --name DeadLetter
+type DeadLetter interface {
+	Record(ctx context.Context, deadJob *entity.DeadJob) error
+}
+
+//go:generate mockery --with-expecter --case snake --name Cursor
+type Cursor interface {
+	GetCursor(ctx context.Context, key string) (int64, error)
+	SetCursor(ctx context.Context, key string, id int64) error
 }
 
 type Service struct {
-	roomRepo RoomRepository
-	omni     Omnichannel
+	roomRepo   RoomRepository
+	omni       Omnichannel
+	deadLetter DeadLetter
+	policy     *Policy
+
+	// cursor stores the last room ID evaluated by a run that reached the per-run cap, so
+	// the next run continues after it instead of evaluating the same rooms again, even on
+	// another replica or after a restart.
+	mu     sync.Mutex
+	cursor Cursor
 }
 
-func NewService(roomRepo RoomRepository, omni Omnichannel) *Service {
+func NewService(roomRepo RoomRepository, omni Omnichannel, deadLetter DeadLetter, cursor Cursor, policy *Policy) *Service {
 	return &Service{
-		roomRepo: roomRepo,
-		omni:     omni,
+		roomRepo:   roomRepo,
+		omni:       newRateLimitedOmnichannel(omni, policy.Limiter()),
+		deadLetter: deadLetter,
+		cursor:     cursor,
+		policy:     policy,
+	}
+}
+
+// RunStats counts the rooms of a run by outcome. Processed is the sum of the others.
+type RunStats struct {
+	Processed int `json:"processed"`
+	Resolved  int `json:"resolved"`
+	Failed    int `json:"failed"`
+	Skipped   int `json:"skipped"`
+}
+
+type outcome int
+
+const (
+	outcomeSkipped outcome = iota
+	outcomeResolved
+	outcomeFailed
+)
+
+func (r *RunStats) add(o outcome) {
+	r.Processed++
+	switch o {
+	case outcomeResolved:
+		r.Resolved++
+	case outcomeFailed:
+		r.Failed++
+	default:
+		r.Skipped++
 	}
 }
 
-func (s *Service) ResolvedOmnichannelRoom(ctx context.Context) error {
-	rooms, err := s.roomRepo.Fetch(ctx)
+// ResolvedOmnichannelRoom resolves the expired rooms, loaded in batches of rooms old enough
+// to possibly be expired, until none are left, the per-run cap is reached or ctx is done.
+// The rooms of a batch are resolved concurrently.
+func (s *Service) ResolvedOmnichannelRoom(ctx context.Context) (*RunStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := &RunStats{}
+
+	start, err := s.cursor.GetCursor(ctx, cursorKey)
 	if err != nil {
-		return fmt.Errorf("failed to fetch rooms: %w", err)
+		return stats, fmt.Errorf("failed to get cursor: %w", err)
 	}
 
 	now := time.Now()
-	for _, room := range rooms {
-		diffMinutes := int(now.Sub(room.CreatedAt).Minutes())
-		if diffMinutes < 10 {
-			return nil
+	createdBefore := now.Add(-s.policy.MinTimeout())
+	maxRooms := s.policy.MaxRoomsPerRun()
+
+	cursor := start
+	for stats.Processed < maxRooms {
+		limit := min(s.policy.BatchSize(), maxRooms-stats.Processed)
+
+		rooms, err := s.roomRepo.FetchExpired(ctx, createdBefore, cursor, limit)
+		if err != nil {
+			return stats, fmt.Errorf("failed to fetch rooms: %w", err)
+		}
+
+		for _, o := range s.processBatch(ctx, rooms, now) {
+			stats.add(o)
+		}
+
+		if ctx.Err() != nil {
+			// The cursor is not moved, so the interrupted batch is evaluated again next run
+			log.Ctx(ctx).Warn().Int64("cursor", start).Msg("resolver run timed out, continue on the next run")
+			return stats, nil
+		}
+
+		if len(rooms) < limit {
+			// Every candidate has been evaluated, the next run starts over
+			return stats, s.saveCursor(ctx, start, 0)
+		}
+
+		cursor = rooms[len(rooms)-1].ID
+	}
+
+	log.Ctx(ctx).Warn().Int("processed", stats.Processed).Int64("cursor", cursor).
+		Msg("resolver reached the maximum rooms per run, continue on the next run")
+
+	return stats, s.saveCursor(ctx, start, cursor)
+}
+
+// saveCursor stores the cursor the next run starts from, unless the run did not move it.
+func (s *Service) saveCursor(ctx context.Context, start, cursor int64) error {
+	if cursor == start {
+		return nil
+	}
+
+	if err := s.cursor.SetCursor(ctx, cursorKey, cursor); err != nil {
+		return fmt.Errorf("failed to save cursor: %w", err)
+	}
+
+	return nil
+}
+
+// processBatch resolves the rooms with a bounded pool of goroutines and returns the outcome
+// of each room, in the order of rooms.
+func (s *Service) processBatch(ctx context.Context, rooms []entity.Room, now time.Time) []outcome {
+	outcomes := make([]outcome, len(rooms))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for range min(s.policy.Concurrency(), len(rooms)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				outcomes[i] = s.processRoom(ctx, &rooms[i], now)
+			}
+		}()
+	}
+
+	for i := range rooms {
+		indexes <- i
+	}
+
+	close(indexes)
+	wg.Wait()
+
+	return outcomes
+}
+
+func (s *Service) processRoom(ctx context.Context, room *entity.Room, now time.Time) outcome {
+	// Already resolved in Omnichannel, e.g. by an agent in the dashboard
+	if room.IsResolved() {
+		return outcomeSkipped
+	}
+
+	if now.Sub(room.CreatedAt) < s.policy.MinTimeout() {
+		return outcomeSkipped
+	}
+
+	// The run timed out, leave the room to the next run
+	if ctx.Err() != nil {
+		return outcomeSkipped
+	}
+
+	expired, err := s.isExpired(ctx, room, now)
+	if err != nil {
+		if interrupted(ctx, err) {
+			return outcomeSkipped
+		}
+
+		s.fail(ctx, room.MultichannelRoomID, err)
+		return outcomeFailed
+	}
+
+	if !expired {
+		return outcomeSkipped
+	}
+
+	if err := s.resolve(ctx, room); err != nil {
+		if interrupted(ctx, err) {
+			return outcomeSkipped
 		}
 
-		if err := s.omni.ResolvedRoom(ctx, room.MultichannelRoomID); err != nil {
-			log.Ctx(ctx).Error().Msgf("failed to resolved room: %s", err.Error())
-			continue
+		s.fail(ctx, room.MultichannelRoomID, err)
+		return outcomeFailed
+	}
+
+	return outcomeResolved
+}
+
+// fail handles a room that could not be evaluated or resolved. A room whose failure may
+// pass, e.g. Qiscus is unavailable or the database failed, is retried on the next run. A
+// room that Qiscus rejected for good, e.g. with a bad request, is buried: it moves to the
+// resolve failed status, no longer picked by the runs, and is recorded as a failed event.
+func (s *Service) fail(ctx context.Context, multichannelRoomID string, err error) {
+	log.Ctx(ctx).Error().Str("room_id", multichannelRoomID).Msg(err.Error())
+
+	var apiErr *qismo.APIError
+	if !errors.As(err, &apiErr) || apiErr.Retryable() {
+		return
+	}
+
+	// Recorded even when the run is canceled, so the room can be replayed
+	s.recordFailure(context.WithoutCancel(ctx), multichannelRoomID, err)
+}
+
+// interrupted reports whether err comes from the run rather than from the room, e.g. the
+// run timed out, could not get a limiter token before its deadline or lost its lock to
+// another replica. Such rooms are left to the next run instead of being recorded as failed.
+func interrupted(ctx context.Context, err error) bool {
+	return ctx.Err() != nil || errors.Is(err, errLimiterWait) || errors.Is(err, qismo.ErrRateLimitWait) ||
+		errors.Is(err, postgres.ErrStaleFencingToken)
+}
+
+// ResolveRoom resolves a single room when a failed resolution is replayed. Only rooms still
+// in the resolve failed status are resolved; rooms that were resolved, reopened by a new
+// session or removed in the meantime are skipped.
+func (s *Service) ResolveRoom(ctx context.Context, multichannelRoomID string) error {
+	room, err := s.roomRepo.FindByMultichannelRoomID(ctx, multichannelRoomID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Ctx(ctx).Info().Str("room_id", multichannelRoomID).Msg("skip resolve, room not found")
+			return nil
 		}
 
-		err := s.roomRepo.DeleteBy(ctx, map[string]any{
-			"multichannel_room_id": room.MultichannelRoomID,
-		})
+		return fmt.Errorf("failed to find room: %w", err)
+	}
+
+	if room.Status != entity.RoomStatusResolveFailed {
+		log.Ctx(ctx).Info().Str("room_id", multichannelRoomID).Str("status", string(room.Status)).
+			Msg("skip resolve, room is no longer resolve failed")
+		return nil
+	}
+
+	return s.resolve(ctx, room)
+}
 
+// isExpired evaluates the room against the policy, only fetching the room info and
+// tags from Omnichannel when the policy needs them.
+func (s *Service) isExpired(ctx context.Context, room *entity.Room, now time.Time) (bool, error) {
+	var info *qismo.CustomerRoom
+	if s.policy.NeedsRoomInfo() {
+		var err error
+		info, err = s.omni.GetRoomInfo(ctx, room.MultichannelRoomID)
 		if err != nil {
-			log.Ctx(ctx).Error().Msgf("failed to delete room: %s", err.Error())
-			continue
+			return false, fmt.Errorf("failed to get room info: %w", err)
+		}
+	}
+
+	var tags []qismo.RoomTag
+	if s.policy.NeedsTags() {
+		var err error
+		tags, err = s.omni.GetRoomTags(ctx, room.MultichannelRoomID)
+		if err != nil {
+			return false, fmt.Errorf("failed to get room tags: %w", err)
+		}
+	}
+
+	return s.policy.IsExpired(room, info, tags, now), nil
+}
+
+// resolve sends the closing message if any, marks the room as resolved in Omnichannel,
+// then moves it to the resolved status and soft deletes it, keeping its history.
+func (s *Service) resolve(ctx context.Context, room *entity.Room) error {
+	multichannelRoomID := room.MultichannelRoomID
+	if err := s.sendClosingMessage(ctx, room); err != nil {
+		return err
+	}
+
+	if err := s.omni.ResolvedRoom(ctx, multichannelRoomID); err != nil {
+		if !errors.Is(err, qismo.ErrRoomAlreadyResolved) {
+			return fmt.Errorf("failed to resolved room: %w", err)
 		}
 
+		// Resolved in Omnichannel in the meantime, e.g. by an agent in the dashboard
+		log.Ctx(ctx).Info().Str("room_id", multichannelRoomID).Msg("room is already resolved in omnichannel")
+	}
+
+	// The room is resolved in Omnichannel, finish the local bookkeeping even when the run
+	// is canceled, e.g. on shutdown, so the room is not left open locally
+	ctx = context.WithoutCancel(ctx)
+
+	err := s.roomRepo.Transition(ctx, multichannelRoomID, entity.RoomStatusResolved, actor, "")
+	if err != nil {
+		return fmt.Errorf("failed to update room status: %w", err)
+	}
+
+	err = s.roomRepo.DeleteBy(ctx, map[string]any{
+		"multichannel_room_id": multichannelRoomID,
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to delete room: %w", err)
+	}
+
+	return nil
+}
+
+// sendClosingMessage sends the closing message if any, unless an earlier attempt already
+// sent it. A message that cannot be sent fails the resolution, so the room is retried. A
+// sent message that cannot be recorded is logged; it is only sent again when the
+// resolution fails afterwards too.
+func (s *Service) sendClosingMessage(ctx context.Context, room *entity.Room) error {
+	message, sender := s.policy.ClosingMessage()
+	if message == "" || room.ClosingSentAt != nil {
+		return nil
+	}
+
+	err := s.omni.SendMessageAsBot(ctx, &qismo.SendMessageRequest{
+		SenderEmail: sender,
+		RoomID:      room.MultichannelRoomID,
+		Message:     message,
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to send closing message: %w", err)
+	}
+
+	err = s.roomRepo.MarkClosingSent(context.WithoutCancel(ctx), room.MultichannelRoomID, time.Now())
+	if err != nil {
+		log.Ctx(ctx).Error().Str("room_id", room.MultichannelRoomID).Msgf("failed to record closing message: %s", err.Error())
 	}
 
 	return nil
 }
+
+// recordFailure moves the room to the resolve failed status and keeps the failed resolution
+// as a failed event, so it can be inspected and replayed as a JobResolveRoom job once the
+// cause is fixed.
+func (s *Service) recordFailure(ctx context.Context, multichannelRoomID string, cause error) {
+	err := s.roomRepo.Transition(ctx, multichannelRoomID, entity.RoomStatusResolveFailed, actor, cause.Error())
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("failed to update room status: %s", err.Error())
+	}
+
+	payload, _ := json.Marshal(ResolveRoomPayload{MultichannelRoomID: multichannelRoomID})
+	requestID, _ := ctx.Value(config.RequestIDKey).(string)
+
+	err = s.deadLetter.Record(ctx, &entity.DeadJob{
+		Type:      JobResolveRoom,
+		Key:       multichannelRoomID,
+		Payload:   payload,
+		Error:     cause.Error(),
+		RequestID: requestID,
+	})
+
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("failed to record failed event: %s", err.Error())
+	}
+}
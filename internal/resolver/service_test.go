@@ -4,33 +4,53 @@ import (
 	"context"
 	"fmt"
 	"integration-go/internal/entity"
+	"integration-go/internal/pkg/config"
+	"integration-go/internal/pkg/postgres"
+	"integration-go/internal/pkg/qismo"
 	"integration-go/internal/resolver/mocks"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/time/rate"
+	"gorm.io/gorm"
 )
 
-var errUnexpected = fmt.Errorf("unexpected")
+var (
+	errUnexpected = fmt.Errorf("unexpected")
+	defaultPolicy = &Policy{cfg: config.Resolver{Mode: ModeCreated, Timeout: 10 * time.Minute, BatchSize: 100, MaxRoomsPerRun: 1000}}
+)
+
+// newCursor returns a cursor at id for the runs that are not expected to move it.
+func newCursor(t *testing.T, id int64) *mocks.Cursor {
+	cursor := mocks.NewCursor(t)
+	cursor.EXPECT().GetCursor(mock.Anything, cursorKey).Return(id, nil)
+	return cursor
+}
 
 func TestResolvedOmnichannelRoom(t *testing.T) {
 	mockRoomRepo := mocks.NewRoomRepository(t)
 	mockOmni := mocks.NewOmnichannel(t)
+	mockDeadLetter := mocks.NewDeadLetter(t)
 
 	t.Run("error fetch rooms", func(t *testing.T) {
-		mockRoomRepo.EXPECT().Fetch(mock.Anything).Return(nil, errUnexpected).Once()
+		mockRoomRepo.EXPECT().FetchExpired(mock.Anything, mock.AnythingOfType("time.Time"), int64(0), 100).Return(nil, errUnexpected).Once()
 
 		svc := Service{
-			roomRepo: mockRoomRepo,
-			omni:     mockOmni,
+			cursor:     newCursor(t, 0),
+			roomRepo:   mockRoomRepo,
+			omni:       mockOmni,
+			deadLetter: mockDeadLetter,
+			policy:     defaultPolicy,
 		}
 
-		err := svc.ResolvedOmnichannelRoom(context.Background())
+		_, err := svc.ResolvedOmnichannelRoom(context.Background())
 		assert.Equal(t, fmt.Errorf("failed to fetch rooms: %w", errUnexpected), err)
 
 		mockRoomRepo.AssertExpectations(t)
 		mockOmni.AssertExpectations(t)
+		mockDeadLetter.AssertExpectations(t)
 	})
 
 	t.Run("skip room less than 10 minutes", func(t *testing.T) {
@@ -41,24 +61,29 @@ func TestResolvedOmnichannelRoom(t *testing.T) {
 			},
 		}
 
-		mockRoomRepo.EXPECT().Fetch(mock.Anything).Return(rooms, nil).Once()
+		mockRoomRepo.EXPECT().FetchExpired(mock.Anything, mock.AnythingOfType("time.Time"), int64(0), 100).Return(rooms, nil).Once()
 
 		svc := Service{
-			roomRepo: mockRoomRepo,
-			omni:     mockOmni,
+			cursor:     newCursor(t, 0),
+			roomRepo:   mockRoomRepo,
+			omni:       mockOmni,
+			deadLetter: mockDeadLetter,
+			policy:     defaultPolicy,
 		}
 
-		err := svc.ResolvedOmnichannelRoom(context.Background())
+		_, err := svc.ResolvedOmnichannelRoom(context.Background())
 		assert.Nil(t, err)
 
 		mockRoomRepo.AssertExpectations(t)
 		mockOmni.AssertExpectations(t)
+		mockDeadLetter.AssertExpectations(t)
 	})
 
-	t.Run("error resolved room but continue process", func(t *testing.T) {
+	t.Run("skip room already resolved", func(t *testing.T) {
 		rooms := []entity.Room{
 			{
 				MultichannelRoomID: "room-123",
+				Status:             entity.RoomStatusResolved,
 				CreatedAt:          time.Now().Add(-15 * time.Minute),
 			},
 			{
@@ -67,27 +92,85 @@ func TestResolvedOmnichannelRoom(t *testing.T) {
 			},
 		}
 
-		mockRoomRepo.EXPECT().Fetch(mock.Anything).Return(rooms, nil).Once()
+		mockRoomRepo.EXPECT().FetchExpired(mock.Anything, mock.AnythingOfType("time.Time"), int64(0), 100).Return(rooms, nil).Once()
+		mockOmni.EXPECT().ResolvedRoom(mock.Anything, "room-456").Return(nil).Once()
+		mockRoomRepo.EXPECT().Transition(mock.Anything, "room-456", entity.RoomStatusResolved, actor, "").Return(nil).Once()
+		mockRoomRepo.EXPECT().DeleteBy(mock.Anything, map[string]interface{}{
+			"multichannel_room_id": "room-456",
+		}).Return(nil).Once()
 
-		// First room
-		mockOmni.EXPECT().ResolvedRoom(mock.Anything, "room-123").Return(errUnexpected).Once()
+		svc := Service{
+			cursor:     newCursor(t, 0),
+			roomRepo:   mockRoomRepo,
+			omni:       mockOmni,
+			deadLetter: mockDeadLetter,
+			policy:     defaultPolicy,
+		}
+
+		stats, err := svc.ResolvedOmnichannelRoom(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, &RunStats{Processed: 2, Resolved: 1, Skipped: 1}, stats)
+
+		mockRoomRepo.AssertExpectations(t)
+		mockOmni.AssertExpectations(t)
+		mockDeadLetter.AssertExpectations(t)
+	})
+
+	t.Run("error resolved room but continue process", func(t *testing.T) {
+		rooms := []entity.Room{
+			{
+				MultichannelRoomID: "room-123",
+				CreatedAt:          time.Now().Add(-15 * time.Minute),
+			},
+			{
+				MultichannelRoomID: "room-456",
+				CreatedAt:          time.Now().Add(-20 * time.Minute),
+			},
+			{
+				MultichannelRoomID: "room-789",
+				CreatedAt:          time.Now().Add(-20 * time.Minute),
+			},
+		}
+
+		mockRoomRepo.EXPECT().FetchExpired(mock.Anything, mock.AnythingOfType("time.Time"), int64(0), 100).Return(rooms, nil).Once()
+
+		// First room is rejected for good, it is buried
+		badRequest := &qismo.APIError{StatusCode: 400, Code: qismo.ErrorCodeBadRequest, Message: "invalid room"}
+		mockOmni.EXPECT().ResolvedRoom(mock.Anything, "room-123").Return(badRequest).Once()
+		mockRoomRepo.EXPECT().Transition(mock.Anything, "room-123", entity.RoomStatusResolveFailed, actor,
+			fmt.Errorf("failed to resolved room: %w", badRequest).Error()).Return(nil).Once()
+		mockDeadLetter.EXPECT().Record(mock.Anything, &entity.DeadJob{
+			Type:    JobResolveRoom,
+			Key:     "room-123",
+			Payload: []byte(`{"multichannel_room_id":"room-123"}`),
+			Error:   fmt.Errorf("failed to resolved room: %w", badRequest).Error(),
+		}).Return(nil).Once()
 
 		// Second room
 		mockOmni.EXPECT().ResolvedRoom(mock.Anything, "room-456").Return(nil).Once()
+		mockRoomRepo.EXPECT().Transition(mock.Anything, "room-456", entity.RoomStatusResolved, actor, "").Return(nil).Once()
 		mockRoomRepo.EXPECT().DeleteBy(mock.Anything, map[string]interface{}{
 			"multichannel_room_id": "room-456",
 		}).Return(nil).Once()
 
+		// Third room fails for a reason that may pass, it is left to the next run
+		mockOmni.EXPECT().ResolvedRoom(mock.Anything, "room-789").Return(errUnexpected).Once()
+
 		svc := Service{
-			roomRepo: mockRoomRepo,
-			omni:     mockOmni,
+			cursor:     newCursor(t, 0),
+			roomRepo:   mockRoomRepo,
+			omni:       mockOmni,
+			deadLetter: mockDeadLetter,
+			policy:     defaultPolicy,
 		}
 
-		err := svc.ResolvedOmnichannelRoom(context.Background())
+		stats, err := svc.ResolvedOmnichannelRoom(context.Background())
 		assert.Nil(t, err)
+		assert.Equal(t, &RunStats{Processed: 3, Resolved: 1, Failed: 2}, stats)
 
 		mockRoomRepo.AssertExpectations(t)
 		mockOmni.AssertExpectations(t)
+		mockDeadLetter.AssertExpectations(t)
 	})
 
 	t.Run("error delete room but continue process", func(t *testing.T) {
@@ -102,30 +185,36 @@ func TestResolvedOmnichannelRoom(t *testing.T) {
 			},
 		}
 
-		mockRoomRepo.EXPECT().Fetch(mock.Anything).Return(rooms, nil).Once()
+		mockRoomRepo.EXPECT().FetchExpired(mock.Anything, mock.AnythingOfType("time.Time"), int64(0), 100).Return(rooms, nil).Once()
 
 		// First room
 		mockOmni.EXPECT().ResolvedRoom(mock.Anything, "room-123").Return(nil).Once()
+		mockRoomRepo.EXPECT().Transition(mock.Anything, "room-123", entity.RoomStatusResolved, actor, "").Return(nil).Once()
 		mockRoomRepo.EXPECT().DeleteBy(mock.Anything, map[string]interface{}{
 			"multichannel_room_id": "room-123",
 		}).Return(errUnexpected).Once()
 
 		// Second room
 		mockOmni.EXPECT().ResolvedRoom(mock.Anything, "room-456").Return(nil).Once()
+		mockRoomRepo.EXPECT().Transition(mock.Anything, "room-456", entity.RoomStatusResolved, actor, "").Return(nil).Once()
 		mockRoomRepo.EXPECT().DeleteBy(mock.Anything, map[string]interface{}{
 			"multichannel_room_id": "room-456",
 		}).Return(nil).Once()
 
 		svc := Service{
-			roomRepo: mockRoomRepo,
-			omni:     mockOmni,
+			cursor:     newCursor(t, 0),
+			roomRepo:   mockRoomRepo,
+			omni:       mockOmni,
+			deadLetter: mockDeadLetter,
+			policy:     defaultPolicy,
 		}
 
-		err := svc.ResolvedOmnichannelRoom(context.Background())
+		_, err := svc.ResolvedOmnichannelRoom(context.Background())
 		assert.Nil(t, err)
 
 		mockRoomRepo.AssertExpectations(t)
 		mockOmni.AssertExpectations(t)
+		mockDeadLetter.AssertExpectations(t)
 	})
 
 	t.Run("success resolve all rooms", func(t *testing.T) {
@@ -140,29 +229,473 @@ func TestResolvedOmnichannelRoom(t *testing.T) {
 			},
 		}
 
-		mockRoomRepo.EXPECT().Fetch(mock.Anything).Return(rooms, nil).Once()
+		mockRoomRepo.EXPECT().FetchExpired(mock.Anything, mock.AnythingOfType("time.Time"), int64(0), 100).Return(rooms, nil).Once()
 
 		// First room
 		mockOmni.EXPECT().ResolvedRoom(mock.Anything, "room-123").Return(nil).Once()
+		mockRoomRepo.EXPECT().Transition(mock.Anything, "room-123", entity.RoomStatusResolved, actor, "").Return(nil).Once()
 		mockRoomRepo.EXPECT().DeleteBy(mock.Anything, map[string]interface{}{
 			"multichannel_room_id": "room-123",
 		}).Return(nil).Once()
 
 		// Second room
 		mockOmni.EXPECT().ResolvedRoom(mock.Anything, "room-456").Return(nil).Once()
+		mockRoomRepo.EXPECT().Transition(mock.Anything, "room-456", entity.RoomStatusResolved, actor, "").Return(nil).Once()
 		mockRoomRepo.EXPECT().DeleteBy(mock.Anything, map[string]interface{}{
 			"multichannel_room_id": "room-456",
 		}).Return(nil).Once()
 
 		svc := Service{
-			roomRepo: mockRoomRepo,
-			omni:     mockOmni,
+			cursor:     newCursor(t, 0),
+			roomRepo:   mockRoomRepo,
+			omni:       mockOmni,
+			deadLetter: mockDeadLetter,
+			policy:     defaultPolicy,
 		}
 
-		err := svc.ResolvedOmnichannelRoom(context.Background())
+		_, err := svc.ResolvedOmnichannelRoom(context.Background())
 		assert.Nil(t, err)
 
 		mockRoomRepo.AssertExpectations(t)
 		mockOmni.AssertExpectations(t)
+		mockDeadLetter.AssertExpectations(t)
+	})
+}
+
+func TestResolveRoom(t *testing.T) {
+	mockRoomRepo := mocks.NewRoomRepository(t)
+	mockOmni := mocks.NewOmnichannel(t)
+
+	svc := Service{
+		roomRepo: mockRoomRepo,
+		omni:     mockOmni,
+		policy:   defaultPolicy,
+	}
+
+	t.Run("error find room", func(t *testing.T) {
+		mockRoomRepo.EXPECT().FindByMultichannelRoomID(mock.Anything, "room-123").Return(nil, errUnexpected).Once()
+
+		err := svc.ResolveRoom(context.Background(), "room-123")
+		assert.Equal(t, fmt.Errorf("failed to find room: %w", errUnexpected), err)
+	})
+
+	t.Run("skip room not found", func(t *testing.T) {
+		mockRoomRepo.EXPECT().FindByMultichannelRoomID(mock.Anything, "room-123").Return(nil, gorm.ErrRecordNotFound).Once()
+
+		err := svc.ResolveRoom(context.Background(), "room-123")
+		assert.Nil(t, err)
+	})
+
+	t.Run("skip room already resolved", func(t *testing.T) {
+		mockRoomRepo.EXPECT().FindByMultichannelRoomID(mock.Anything, "room-123").
+			Return(&entity.Room{MultichannelRoomID: "room-123", Status: entity.RoomStatusResolved}, nil).Once()
+
+		err := svc.ResolveRoom(context.Background(), "room-123")
+		assert.Nil(t, err)
+	})
+
+	t.Run("skip room no longer resolve failed", func(t *testing.T) {
+		// e.g. reopened by a new session after the failed resolution
+		mockRoomRepo.EXPECT().FindByMultichannelRoomID(mock.Anything, "room-123").
+			Return(&entity.Room{MultichannelRoomID: "room-123", Status: entity.RoomStatusNew}, nil).Once()
+
+		err := svc.ResolveRoom(context.Background(), "room-123")
+		assert.Nil(t, err)
+	})
+
+	t.Run("success resolve room", func(t *testing.T) {
+		mockRoomRepo.EXPECT().FindByMultichannelRoomID(mock.Anything, "room-123").
+			Return(&entity.Room{MultichannelRoomID: "room-123", Status: entity.RoomStatusResolveFailed}, nil).Once()
+		mockOmni.EXPECT().ResolvedRoom(mock.Anything, "room-123").Return(nil).Once()
+		mockRoomRepo.EXPECT().Transition(mock.Anything, "room-123", entity.RoomStatusResolved, actor, "").Return(nil).Once()
+		mockRoomRepo.EXPECT().DeleteBy(mock.Anything, map[string]interface{}{
+			"multichannel_room_id": "room-123",
+		}).Return(nil).Once()
+
+		err := svc.ResolveRoom(context.Background(), "room-123")
+		assert.Nil(t, err)
+	})
+
+	t.Run("success room already resolved in omnichannel", func(t *testing.T) {
+		mockRoomRepo.EXPECT().FindByMultichannelRoomID(mock.Anything, "room-123").
+			Return(&entity.Room{MultichannelRoomID: "room-123", Status: entity.RoomStatusResolveFailed}, nil).Once()
+		mockOmni.EXPECT().ResolvedRoom(mock.Anything, "room-123").
+			Return(&qismo.APIError{StatusCode: 400, Code: qismo.ErrorCodeRoomAlreadyResolved}).Once()
+		mockRoomRepo.EXPECT().Transition(mock.Anything, "room-123", entity.RoomStatusResolved, actor, "").Return(nil).Once()
+		mockRoomRepo.EXPECT().DeleteBy(mock.Anything, map[string]interface{}{
+			"multichannel_room_id": "room-123",
+		}).Return(nil).Once()
+
+		err := svc.ResolveRoom(context.Background(), "room-123")
+		assert.Nil(t, err)
+	})
+}
+
+func TestResolvedOmnichannelRoom_Policy(t *testing.T) {
+	mockRoomRepo := mocks.NewRoomRepository(t)
+	mockOmni := mocks.NewOmnichannel(t)
+	mockDeadLetter := mocks.NewDeadLetter(t)
+
+	t.Run("idle mode skips room with recent customer message", func(t *testing.T) {
+		lastCustomerMessage := time.Now().Add(-5 * time.Minute)
+		rooms := []entity.Room{
+			{
+				MultichannelRoomID: "room-123",
+				CreatedAt:          time.Now().Add(-time.Hour),
+			},
+		}
+
+		mockRoomRepo.EXPECT().FetchExpired(mock.Anything, mock.AnythingOfType("time.Time"), int64(0), 100).Return(rooms, nil).Once()
+		mockOmni.EXPECT().GetRoomInfo(mock.Anything, "room-123").
+			Return(&qismo.CustomerRoom{RoomID: "room-123", LastCustomerTimestamp: &lastCustomerMessage}, nil).Once()
+
+		svc := Service{
+			cursor:     newCursor(t, 0),
+			roomRepo:   mockRoomRepo,
+			omni:       mockOmni,
+			deadLetter: mockDeadLetter,
+			policy:     &Policy{cfg: config.Resolver{Mode: ModeIdle, Timeout: 10 * time.Minute, BatchSize: 100, MaxRoomsPerRun: 1000}},
+		}
+
+		_, err := svc.ResolvedOmnichannelRoom(context.Background())
+		assert.Nil(t, err)
+	})
+
+	t.Run("error get room info skips room", func(t *testing.T) {
+		rooms := []entity.Room{
+			{
+				MultichannelRoomID: "room-123",
+				CreatedAt:          time.Now().Add(-time.Hour),
+			},
+		}
+
+		mockRoomRepo.EXPECT().FetchExpired(mock.Anything, mock.AnythingOfType("time.Time"), int64(0), 100).Return(rooms, nil).Once()
+		mockOmni.EXPECT().GetRoomInfo(mock.Anything, "room-123").Return(nil, errUnexpected).Once()
+
+		svc := Service{
+			cursor:     newCursor(t, 0),
+			roomRepo:   mockRoomRepo,
+			omni:       mockOmni,
+			deadLetter: mockDeadLetter,
+			policy:     &Policy{cfg: config.Resolver{Mode: ModeIdle, Timeout: 10 * time.Minute, BatchSize: 100, MaxRoomsPerRun: 1000}},
+		}
+
+		_, err := svc.ResolvedOmnichannelRoom(context.Background())
+		assert.Nil(t, err)
+	})
+
+	t.Run("tag override and closing message", func(t *testing.T) {
+		rooms := []entity.Room{
+			{
+				MultichannelRoomID: "room-123",
+				CreatedAt:          time.Now().Add(-15 * time.Minute),
+			},
+			{
+				MultichannelRoomID: "room-456",
+				CreatedAt:          time.Now().Add(-15 * time.Minute),
+			},
+		}
+
+		mockRoomRepo.EXPECT().FetchExpired(mock.Anything, mock.AnythingOfType("time.Time"), int64(0), 100).Return(rooms, nil).Once()
+
+		// First room is tagged vip, which has a longer timeout
+		mockOmni.EXPECT().GetRoomTags(mock.Anything, "room-123").Return([]qismo.RoomTag{{Name: "vip"}}, nil).Once()
+
+		// Second room uses the default timeout
+		mockOmni.EXPECT().GetRoomTags(mock.Anything, "room-456").Return(nil, nil).Once()
+		mockOmni.EXPECT().SendMessageAsBot(mock.Anything, &qismo.SendMessageRequest{
+			SenderEmail: "admin@mail.com",
+			RoomID:      "room-456",
+			Message:     "Thank you for contacting us",
+		}).Return(nil).Once()
+		mockRoomRepo.EXPECT().MarkClosingSent(mock.Anything, "room-456", mock.AnythingOfType("time.Time")).Return(nil).Once()
+		mockOmni.EXPECT().ResolvedRoom(mock.Anything, "room-456").Return(nil).Once()
+		mockRoomRepo.EXPECT().Transition(mock.Anything, "room-456", entity.RoomStatusResolved, actor, "").Return(nil).Once()
+		mockRoomRepo.EXPECT().DeleteBy(mock.Anything, map[string]interface{}{
+			"multichannel_room_id": "room-456",
+		}).Return(nil).Once()
+
+		svc := Service{
+			cursor:     newCursor(t, 0),
+			roomRepo:   mockRoomRepo,
+			omni:       mockOmni,
+			deadLetter: mockDeadLetter,
+			policy: &Policy{cfg: config.Resolver{
+				Mode:                 ModeCreated,
+				Timeout:              10 * time.Minute,
+				BatchSize:            100,
+				MaxRoomsPerRun:       1000,
+				TagTimeouts:          map[string]time.Duration{"vip": time.Hour},
+				ClosingMessage:       "Thank you for contacting us",
+				ClosingMessageSender: "admin@mail.com",
+			}},
+		}
+
+		_, err := svc.ResolvedOmnichannelRoom(context.Background())
+		assert.Nil(t, err)
+	})
+
+	t.Run("not resolved when closing message cannot be sent", func(t *testing.T) {
+		rooms := []entity.Room{
+			{
+				MultichannelRoomID: "room-123",
+				CreatedAt:          time.Now().Add(-15 * time.Minute),
+			},
+		}
+
+		// The room stays open and is retried on the next run
+		mockRoomRepo.EXPECT().FetchExpired(mock.Anything, mock.AnythingOfType("time.Time"), int64(0), 100).Return(rooms, nil).Once()
+		mockOmni.EXPECT().SendMessageAsBot(mock.Anything, mock.Anything).Return(errUnexpected).Once()
+
+		svc := Service{
+			cursor:     newCursor(t, 0),
+			roomRepo:   mockRoomRepo,
+			omni:       mockOmni,
+			deadLetter: mockDeadLetter,
+			policy: &Policy{cfg: config.Resolver{
+				Mode:                 ModeCreated,
+				Timeout:              10 * time.Minute,
+				BatchSize:            100,
+				MaxRoomsPerRun:       1000,
+				ClosingMessage:       "Thank you for contacting us",
+				ClosingMessageSender: "admin@mail.com",
+			}},
+		}
+
+		stats, err := svc.ResolvedOmnichannelRoom(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, &RunStats{Processed: 1, Failed: 1}, stats)
 	})
+
+	t.Run("skip closing message already sent", func(t *testing.T) {
+		sentAt := time.Now().Add(-time.Minute)
+		rooms := []entity.Room{
+			{
+				MultichannelRoomID: "room-123",
+				ClosingSentAt:      &sentAt,
+				CreatedAt:          time.Now().Add(-15 * time.Minute),
+			},
+		}
+
+		// e.g. a retry after resolving failed, the closing message was sent by the first attempt
+		mockRoomRepo.EXPECT().FetchExpired(mock.Anything, mock.AnythingOfType("time.Time"), int64(0), 100).Return(rooms, nil).Once()
+		mockOmni.EXPECT().ResolvedRoom(mock.Anything, "room-123").Return(nil).Once()
+		mockRoomRepo.EXPECT().Transition(mock.Anything, "room-123", entity.RoomStatusResolved, actor, "").Return(nil).Once()
+		mockRoomRepo.EXPECT().DeleteBy(mock.Anything, map[string]interface{}{
+			"multichannel_room_id": "room-123",
+		}).Return(nil).Once()
+
+		svc := Service{
+			cursor:     newCursor(t, 0),
+			roomRepo:   mockRoomRepo,
+			omni:       mockOmni,
+			deadLetter: mockDeadLetter,
+			policy: &Policy{cfg: config.Resolver{
+				Mode:                 ModeCreated,
+				Timeout:              10 * time.Minute,
+				BatchSize:            100,
+				MaxRoomsPerRun:       1000,
+				ClosingMessage:       "Thank you for contacting us",
+				ClosingMessageSender: "admin@mail.com",
+			}},
+		}
+
+		stats, err := svc.ResolvedOmnichannelRoom(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, &RunStats{Processed: 1, Resolved: 1}, stats)
+	})
+}
+
+func TestResolvedOmnichannelRoom_Batches(t *testing.T) {
+	mockRoomRepo := mocks.NewRoomRepository(t)
+	mockOmni := mocks.NewOmnichannel(t)
+
+	rooms := func(ids ...int64) []entity.Room {
+		var rooms []entity.Room
+		for _, id := range ids {
+			rooms = append(rooms, entity.Room{
+				ID:                 id,
+				MultichannelRoomID: fmt.Sprintf("room-%d", id),
+				CreatedAt:          time.Now().Add(-time.Hour),
+			})
+		}
+		return rooms
+	}
+
+	expectResolve := func(roomID string) {
+		mockOmni.EXPECT().ResolvedRoom(mock.Anything, roomID).Return(nil).Once()
+		mockRoomRepo.EXPECT().Transition(mock.Anything, roomID, entity.RoomStatusResolved, actor, "").Return(nil).Once()
+		mockRoomRepo.EXPECT().DeleteBy(mock.Anything, map[string]interface{}{
+			"multichannel_room_id": roomID,
+		}).Return(nil).Once()
+	}
+
+	mockCursor := mocks.NewCursor(t)
+
+	svc := Service{
+		roomRepo: mockRoomRepo,
+		omni:     mockOmni,
+		cursor:   mockCursor,
+		policy:   &Policy{cfg: config.Resolver{Mode: ModeCreated, Timeout: 10 * time.Minute, BatchSize: 2, MaxRoomsPerRun: 3}},
+	}
+
+	t.Run("stop at the maximum rooms per run", func(t *testing.T) {
+		mockCursor.EXPECT().GetCursor(mock.Anything, cursorKey).Return(0, nil).Once()
+		mockCursor.EXPECT().SetCursor(mock.Anything, cursorKey, int64(3)).Return(nil).Once()
+		mockRoomRepo.EXPECT().FetchExpired(mock.Anything, mock.AnythingOfType("time.Time"), int64(0), 2).Return(rooms(1, 2), nil).Once()
+		mockRoomRepo.EXPECT().FetchExpired(mock.Anything, mock.AnythingOfType("time.Time"), int64(2), 1).Return(rooms(3), nil).Once()
+		expectResolve("room-1")
+		expectResolve("room-2")
+		expectResolve("room-3")
+
+		stats, err := svc.ResolvedOmnichannelRoom(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, &RunStats{Processed: 3, Resolved: 3}, stats)
+	})
+
+	t.Run("continue after the previous run and start over when done", func(t *testing.T) {
+		mockCursor.EXPECT().GetCursor(mock.Anything, cursorKey).Return(3, nil).Once()
+		mockCursor.EXPECT().SetCursor(mock.Anything, cursorKey, int64(0)).Return(nil).Once()
+		mockRoomRepo.EXPECT().FetchExpired(mock.Anything, mock.AnythingOfType("time.Time"), int64(3), 2).Return(rooms(4), nil).Once()
+		expectResolve("room-4")
+
+		_, err := svc.ResolvedOmnichannelRoom(context.Background())
+		assert.Nil(t, err)
+	})
+
+	t.Run("failed to get cursor", func(t *testing.T) {
+		mockCursor.EXPECT().GetCursor(mock.Anything, cursorKey).Return(0, errUnexpected).Once()
+
+		_, err := svc.ResolvedOmnichannelRoom(context.Background())
+		assert.ErrorIs(t, err, errUnexpected)
+	})
+
+	t.Run("failed to save cursor", func(t *testing.T) {
+		mockCursor.EXPECT().GetCursor(mock.Anything, cursorKey).Return(0, nil).Once()
+		mockCursor.EXPECT().SetCursor(mock.Anything, cursorKey, int64(3)).Return(errUnexpected).Once()
+		mockRoomRepo.EXPECT().FetchExpired(mock.Anything, mock.AnythingOfType("time.Time"), int64(0), 2).Return(rooms(1, 2), nil).Once()
+		mockRoomRepo.EXPECT().FetchExpired(mock.Anything, mock.AnythingOfType("time.Time"), int64(2), 1).Return(rooms(3), nil).Once()
+		expectResolve("room-1")
+		expectResolve("room-2")
+		expectResolve("room-3")
+
+		stats, err := svc.ResolvedOmnichannelRoom(context.Background())
+		assert.ErrorIs(t, err, errUnexpected)
+		assert.Equal(t, &RunStats{Processed: 3, Resolved: 3}, stats)
+	})
+}
+
+func TestResolvedOmnichannelRoom_Timeout(t *testing.T) {
+	mockRoomRepo := mocks.NewRoomRepository(t)
+	mockOmni := mocks.NewOmnichannel(t)
+
+	rooms := []entity.Room{
+		{ID: 1, MultichannelRoomID: "room-1", CreatedAt: time.Now().Add(-time.Hour)},
+		{ID: 2, MultichannelRoomID: "room-2", CreatedAt: time.Now().Add(-time.Hour)},
+	}
+
+	mockRoomRepo.EXPECT().FetchExpired(mock.Anything, mock.AnythingOfType("time.Time"), int64(5), 2).Return(rooms, nil).Once()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	svc := Service{
+		roomRepo: mockRoomRepo,
+		omni:     mockOmni,
+		policy:   &Policy{cfg: config.Resolver{Mode: ModeCreated, Timeout: 10 * time.Minute, BatchSize: 2, MaxRoomsPerRun: 10}},
+		cursor:   newCursor(t, 5),
+	}
+
+	stats, err := svc.ResolvedOmnichannelRoom(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, &RunStats{Processed: 2, Skipped: 2}, stats)
+}
+
+func TestResolvedOmnichannelRoom_CanceledAfterResolved(t *testing.T) {
+	mockRoomRepo := mocks.NewRoomRepository(t)
+	mockOmni := mocks.NewOmnichannel(t)
+
+	rooms := []entity.Room{
+		{ID: 1, MultichannelRoomID: "room-1", CreatedAt: time.Now().Add(-time.Hour)},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	notCanceled := mock.MatchedBy(func(ctx context.Context) bool { return ctx.Err() == nil })
+
+	mockRoomRepo.EXPECT().FetchExpired(mock.Anything, mock.AnythingOfType("time.Time"), int64(0), 100).Return(rooms, nil).Once()
+	// The run is canceled, e.g. on shutdown, right after the room is resolved in Omnichannel
+	mockOmni.EXPECT().ResolvedRoom(mock.Anything, "room-1").RunAndReturn(func(context.Context, string) error {
+		cancel()
+		return nil
+	}).Once()
+	mockRoomRepo.EXPECT().Transition(notCanceled, "room-1", entity.RoomStatusResolved, actor, "").Return(nil).Once()
+	mockRoomRepo.EXPECT().DeleteBy(notCanceled, map[string]any{"multichannel_room_id": "room-1"}).Return(nil).Once()
+
+	svc := Service{
+		cursor:   newCursor(t, 0),
+		roomRepo: mockRoomRepo,
+		omni:     mockOmni,
+		policy:   &Policy{cfg: config.Resolver{Mode: ModeCreated, Timeout: 10 * time.Minute, BatchSize: 100, MaxRoomsPerRun: 1000, Concurrency: 1}},
+	}
+
+	stats, err := svc.ResolvedOmnichannelRoom(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, &RunStats{Processed: 1, Resolved: 1}, stats)
+}
+
+func TestResolvedOmnichannelRoom_LimiterDeadline(t *testing.T) {
+	mockRoomRepo := mocks.NewRoomRepository(t)
+	mockOmni := mocks.NewOmnichannel(t)
+
+	rooms := []entity.Room{
+		{ID: 1, MultichannelRoomID: "room-1", CreatedAt: time.Now().Add(-time.Hour)},
+	}
+
+	// The bucket is empty and the next token comes after the run deadline, so the wait fails
+	// before ctx is done. The room is skipped, not recorded as failed.
+	limiter := rate.NewLimiter(rate.Limit(0.01), 1)
+	limiter.Allow()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	mockRoomRepo.EXPECT().FetchExpired(mock.Anything, mock.AnythingOfType("time.Time"), int64(0), 100).Return(rooms, nil).Once()
+
+	svc := Service{
+		cursor:   newCursor(t, 0),
+		roomRepo: mockRoomRepo,
+		omni:     newRateLimitedOmnichannel(mockOmni, limiter),
+		policy:   &Policy{cfg: config.Resolver{Mode: ModeCreated, Timeout: 10 * time.Minute, BatchSize: 100, MaxRoomsPerRun: 1000, Concurrency: 1}},
+	}
+
+	stats, err := svc.ResolvedOmnichannelRoom(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, &RunStats{Processed: 1, Skipped: 1}, stats)
+}
+
+func TestResolvedOmnichannelRoom_StaleFencingToken(t *testing.T) {
+	mockRoomRepo := mocks.NewRoomRepository(t)
+	mockOmni := mocks.NewOmnichannel(t)
+
+	rooms := []entity.Room{
+		{ID: 1, MultichannelRoomID: "room-1", CreatedAt: time.Now().Add(-time.Hour)},
+	}
+
+	// Another replica took over the lock and wrote first, the room is left to it
+	mockRoomRepo.EXPECT().FetchExpired(mock.Anything, mock.AnythingOfType("time.Time"), int64(0), 100).Return(rooms, nil).Once()
+	mockOmni.EXPECT().ResolvedRoom(mock.Anything, "room-1").Return(nil).Once()
+	mockRoomRepo.EXPECT().Transition(mock.Anything, "room-1", entity.RoomStatusResolved, "resolver", "").Return(postgres.ErrStaleFencingToken).Once()
+
+	svc := Service{
+		cursor:   newCursor(t, 0),
+		roomRepo: mockRoomRepo,
+		omni:     mockOmni,
+		policy:   defaultPolicy,
+	}
+
+	stats, err := svc.ResolvedOmnichannelRoom(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, &RunStats{Processed: 1, Skipped: 1}, stats)
 }
@@ -0,0 +1,61 @@
+package room
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"integration-go/internal/entity"
+	"strings"
+)
+
+// sortColumns are the columns rooms can be sorted by.
+var sortColumns = map[string]bool{
+	"id":         true,
+	"created_at": true,
+	"updated_at": true,
+}
+
+const defaultSort = "-created_at"
+
+// parseSort reads a sort like "created_at" or "-created_at" for descending order.
+func parseSort(sort string) (string, bool, error) {
+	column, desc := strings.CutPrefix(sort, "-")
+	if !sortColumns[column] {
+		return "", false, &roomError{roomErrorInvalidSort}
+	}
+
+	return column, desc, nil
+}
+
+// cursor is the opaque position of a room in a listing. It keeps the sort column, so a
+// cursor cannot be reused with another sort.
+type cursor struct {
+	SortBy string `json:"sort_by"`
+	entity.RoomCursor
+}
+
+func encodeCursor(sortBy string, room *entity.Room) string {
+	c := cursor{SortBy: sortBy, RoomCursor: entity.RoomCursor{ID: room.ID}}
+	switch sortBy {
+	case "created_at":
+		c.Value = room.CreatedAt
+	case "updated_at":
+		c.Value = room.UpdatedAt
+	}
+
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string, sortBy string) (*entity.RoomCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, &roomError{roomErrorInvalidCursor}
+	}
+
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.SortBy != sortBy || c.ID == 0 {
+		return nil, &roomError{roomErrorInvalidCursor}
+	}
+
+	return &c.RoomCursor, nil
+}
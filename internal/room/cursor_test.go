@@ -0,0 +1,55 @@
+package room
+
+import (
+	"integration-go/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSort(t *testing.T) {
+	column, desc, err := parseSort("-created_at")
+	assert.Nil(t, err)
+	assert.Equal(t, "created_at", column)
+	assert.True(t, desc)
+
+	column, desc, err = parseSort("id")
+	assert.Nil(t, err)
+	assert.Equal(t, "id", column)
+	assert.False(t, desc)
+
+	_, _, err = parseSort("status; DROP TABLE rooms")
+	assert.Equal(t, &roomError{roomErrorInvalidSort}, err)
+}
+
+func TestCursor(t *testing.T) {
+	room := &entity.Room{
+		ID:        12,
+		CreatedAt: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC),
+	}
+
+	t.Run("success decode cursor of the same sort", func(t *testing.T) {
+		cursor, err := decodeCursor(encodeCursor("updated_at", room), "updated_at")
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.RoomCursor{ID: 12, Value: room.UpdatedAt}, cursor)
+
+		cursor, err = decodeCursor(encodeCursor("id", room), "id")
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.RoomCursor{ID: 12}, cursor)
+	})
+
+	t.Run("error cursor of another sort", func(t *testing.T) {
+		_, err := decodeCursor(encodeCursor("created_at", room), "updated_at")
+		assert.Equal(t, &roomError{roomErrorInvalidCursor}, err)
+	})
+
+	t.Run("error malformed cursor", func(t *testing.T) {
+		_, err := decodeCursor("not a cursor", "id")
+		assert.Equal(t, &roomError{roomErrorInvalidCursor}, err)
+
+		_, err = decodeCursor("e30", "id")
+		assert.Equal(t, &roomError{roomErrorInvalidCursor}, err)
+	})
+}
@@ -8,21 +8,42 @@ type roomError struct {
 
 const (
 	roomErrorNotFound = iota
+	roomErrorInvalidTransition
+	roomErrorInvalidStatus
+	roomErrorInvalidSort
+	roomErrorInvalidCursor
 )
 
 func (e *roomError) Error() string {
 	switch e.code {
 	case roomErrorNotFound:
 		return "Room not found"
+	case roomErrorInvalidTransition:
+		return "Invalid room status transition"
+	case roomErrorInvalidStatus:
+		return "Invalid room status"
+	case roomErrorInvalidSort:
+		return "Invalid sort, use id, created_at or updated_at, prefixed with - for descending order"
+	case roomErrorInvalidCursor:
+		return "Invalid cursor"
 	default:
 		return "Unknown error code"
 	}
 }
 
+// HTTPMessage is the message sent to the client, which is the message of the error.
+func (e *roomError) HTTPMessage() string {
+	return e.Error()
+}
+
 func (e *roomError) HTTPStatusCode() int {
 	switch e.code {
 	case roomErrorNotFound:
 		return http.StatusNotFound
+	case roomErrorInvalidTransition:
+		return http.StatusConflict
+	case roomErrorInvalidStatus, roomErrorInvalidSort, roomErrorInvalidCursor:
+		return http.StatusBadRequest
 	default:
 		return http.StatusInternalServerError
 	}
@@ -1,15 +1,22 @@
 package room
 
 import (
-	"encoding/json"
+	"integration-go/internal/entity"
 	"integration-go/internal/pkg/api/resp"
 	"integration-go/internal/pkg/qismo"
+	"integration-go/internal/pkg/validate"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/rs/zerolog/log"
 )
 
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
 type httpHandler struct {
 	svc *Service
 }
@@ -20,6 +27,25 @@ func NewHttpHandler(svc *Service) *httpHandler {
 	}
 }
 
+func (h *httpHandler) GetRooms(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, err := parseFilter(r)
+	if err != nil {
+		resp.WriteJSONFromError(w, err)
+		return
+	}
+
+	rooms, total, nextCursor, err := h.svc.GetRooms(ctx, filter)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("failed to get rooms: %s", err.Error())
+		resp.WriteJSONFromError(w, err)
+		return
+	}
+
+	resp.WriteJSONWithCursor(w, http.StatusOK, rooms, int(total), filter.Page, filter.Limit, nextCursor)
+}
+
 func (h *httpHandler) GetRoomByID(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -39,21 +65,109 @@ func (h *httpHandler) GetRoomByID(w http.ResponseWriter, r *http.Request) {
 	resp.WriteJSON(w, http.StatusOK, room)
 }
 
+func (h *httpHandler) GetRoomEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		resp.WriteJSONFromError(w, err)
+		return
+	}
+
+	events, err := h.svc.GetRoomEvents(ctx, int64(id))
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("failed to get room events: %s", err.Error())
+		resp.WriteJSONFromError(w, err)
+		return
+	}
+
+	resp.WriteJSON(w, http.StatusOK, events)
+}
+
 func (h *httpHandler) WebhookQismoNewSession(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var req qismo.WebhookNewSessionRequest
-	err := json.NewDecoder(r.Body).Decode(&req)
-	if err != nil {
+	if err := validate.DecodeJSON(r.Body, &req); err != nil {
 		resp.WriteJSONFromError(w, err)
 		return
 	}
 
-	if err := h.svc.CreateRoom(ctx, &req); err != nil {
-		log.Ctx(ctx).Error().Msgf("failed to create room: %s", err.Error())
+	if err := h.svc.EnqueueCreateRoom(ctx, &req); err != nil {
+		log.Ctx(ctx).Error().Msgf("failed to enqueue create room: %s", err.Error())
 		resp.WriteJSONFromError(w, err)
 		return
 	}
 
 	resp.WriteJSON(w, http.StatusOK, "ok")
 }
+
+// parseFilter reads the room filter from the query. With a cursor the page is ignored and
+// reported as 0.
+func parseFilter(r *http.Request) (*entity.RoomFilter, error) {
+	q := r.URL.Query()
+
+	filter := &entity.RoomFilter{
+		MultichannelRoomID: q.Get("multichannel_room_id"),
+		Status:             entity.RoomStatus(q.Get("status")),
+		Page:               1,
+		Limit:              defaultLimit,
+	}
+
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return nil, &roomError{roomErrorInvalidStatus}
+	}
+
+	sort := q.Get("sort")
+	if sort == "" {
+		sort = defaultSort
+	}
+
+	var err error
+	filter.SortBy, filter.SortDesc, err = parseSort(sort)
+	if err != nil {
+		return nil, err
+	}
+
+	if v := q.Get("page"); v != "" {
+		page, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, err
+		}
+		filter.Page = max(page, 1)
+	}
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, err
+		}
+		filter.Limit = min(max(limit, 1), maxLimit)
+	}
+
+	if v := q.Get("cursor"); v != "" {
+		filter.Cursor, err = decodeCursor(v, filter.SortBy)
+		if err != nil {
+			return nil, err
+		}
+		filter.Page = 0
+	}
+
+	if v := q.Get("from"); v != "" {
+		from, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, err
+		}
+		filter.From = &from
+	}
+
+	if v := q.Get("to"); v != "" {
+		to, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, err
+		}
+		filter.To = &to
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,38 @@
+package room
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"integration-go/internal/pkg/qismo"
+	"integration-go/internal/pkg/queue"
+)
+
+const (
+	JobCreateRoom = "room.create"
+)
+
+type jobHandler struct {
+	svc *Service
+}
+
+func NewJobHandler(svc *Service) *jobHandler {
+	return &jobHandler{
+		svc: svc,
+	}
+}
+
+func (h *jobHandler) CreateRoom(ctx context.Context, payload []byte) error {
+	var req qismo.WebhookNewSessionRequest
+	if err := json.Unmarshal(payload, &req); err != nil {
+		return queue.Permanent(err)
+	}
+
+	err := h.svc.CreateRoom(ctx, &req)
+	// A room missing in Omnichannel will not appear on retry, dead-letter the job right away
+	if errors.Is(err, qismo.ErrRoomNotFound) {
+		return queue.Permanent(err)
+	}
+
+	return err
+}
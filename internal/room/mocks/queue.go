@@ -0,0 +1,85 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Queue is an autogenerated mock type for the Queue type
+type Queue struct {
+	mock.Mock
+}
+
+type Queue_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Queue) EXPECT() *Queue_Expecter {
+	return &Queue_Expecter{mock: &_m.Mock}
+}
+
+// Enqueue provides a mock function with given fields: ctx, jobType, key, payload
+func (_m *Queue) Enqueue(ctx context.Context, jobType string, key string, payload interface{}) error {
+	ret := _m.Called(ctx, jobType, key, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Enqueue")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, interface{}) error); ok {
+		r0 = rf(ctx, jobType, key, payload)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Queue_Enqueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Enqueue'
+type Queue_Enqueue_Call struct {
+	*mock.Call
+}
+
+// Enqueue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - jobType string
+//   - key string
+//   - payload interface{}
+func (_e *Queue_Expecter) Enqueue(ctx interface{}, jobType interface{}, key interface{}, payload interface{}) *Queue_Enqueue_Call {
+	return &Queue_Enqueue_Call{Call: _e.mock.On("Enqueue", ctx, jobType, key, payload)}
+}
+
+func (_c *Queue_Enqueue_Call) Run(run func(ctx context.Context, jobType string, key string, payload interface{})) *Queue_Enqueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(interface{}))
+	})
+	return _c
+}
+
+func (_c *Queue_Enqueue_Call) Return(_a0 error) *Queue_Enqueue_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Queue_Enqueue_Call) RunAndReturn(run func(context.Context, string, string, interface{}) error) *Queue_Enqueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewQueue creates a new instance of Queue. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewQueue(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Queue {
+	mock := &Queue{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
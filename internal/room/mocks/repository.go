@@ -1,4 +1,4 @@
-// Code generated by mockery v2.43.2. DO NOT EDIT.
+// Code generated by mockery v2.53.4. DO NOT EDIT.
 
 package mocks
 
@@ -22,6 +22,131 @@ func (_m *Repository) EXPECT() *Repository_Expecter {
 	return &Repository_Expecter{mock: &_m.Mock}
 }
 
+// Fetch provides a mock function with given fields: ctx, filter
+func (_m *Repository) Fetch(ctx context.Context, filter *entity.RoomFilter) ([]entity.Room, int64, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Fetch")
+	}
+
+	var r0 []entity.Room
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.RoomFilter) ([]entity.Room, int64, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.RoomFilter) []entity.Room); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Room)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.RoomFilter) int64); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *entity.RoomFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Repository_Fetch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Fetch'
+type Repository_Fetch_Call struct {
+	*mock.Call
+}
+
+// Fetch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter *entity.RoomFilter
+func (_e *Repository_Expecter) Fetch(ctx interface{}, filter interface{}) *Repository_Fetch_Call {
+	return &Repository_Fetch_Call{Call: _e.mock.On("Fetch", ctx, filter)}
+}
+
+func (_c *Repository_Fetch_Call) Run(run func(ctx context.Context, filter *entity.RoomFilter)) *Repository_Fetch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*entity.RoomFilter))
+	})
+	return _c
+}
+
+func (_c *Repository_Fetch_Call) Return(_a0 []entity.Room, _a1 int64, _a2 error) *Repository_Fetch_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *Repository_Fetch_Call) RunAndReturn(run func(context.Context, *entity.RoomFilter) ([]entity.Room, int64, error)) *Repository_Fetch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FetchEvents provides a mock function with given fields: ctx, roomID
+func (_m *Repository) FetchEvents(ctx context.Context, roomID int64) ([]entity.RoomEvent, error) {
+	ret := _m.Called(ctx, roomID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchEvents")
+	}
+
+	var r0 []entity.RoomEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]entity.RoomEvent, error)); ok {
+		return rf(ctx, roomID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []entity.RoomEvent); ok {
+		r0 = rf(ctx, roomID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.RoomEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, roomID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_FetchEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FetchEvents'
+type Repository_FetchEvents_Call struct {
+	*mock.Call
+}
+
+// FetchEvents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - roomID int64
+func (_e *Repository_Expecter) FetchEvents(ctx interface{}, roomID interface{}) *Repository_FetchEvents_Call {
+	return &Repository_FetchEvents_Call{Call: _e.mock.On("FetchEvents", ctx, roomID)}
+}
+
+func (_c *Repository_FetchEvents_Call) Run(run func(ctx context.Context, roomID int64)) *Repository_FetchEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Repository_FetchEvents_Call) Return(_a0 []entity.RoomEvent, _a1 error) *Repository_FetchEvents_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_FetchEvents_Call) RunAndReturn(run func(context.Context, int64) ([]entity.RoomEvent, error)) *Repository_FetchEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // FindByID provides a mock function with given fields: ctx, id
 func (_m *Repository) FindByID(ctx context.Context, id int64) (*entity.Room, error) {
 	ret := _m.Called(ctx, id)
@@ -128,6 +253,56 @@ func (_c *Repository_Save_Call) RunAndReturn(run func(context.Context, *entity.R
 	return _c
 }
 
+// Transition provides a mock function with given fields: ctx, multichannelRoomID, to, actor, note
+func (_m *Repository) Transition(ctx context.Context, multichannelRoomID string, to entity.RoomStatus, actor string, note string) error {
+	ret := _m.Called(ctx, multichannelRoomID, to, actor, note)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Transition")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, entity.RoomStatus, string, string) error); ok {
+		r0 = rf(ctx, multichannelRoomID, to, actor, note)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Repository_Transition_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Transition'
+type Repository_Transition_Call struct {
+	*mock.Call
+}
+
+// Transition is a helper method to define mock.On call
+//   - ctx context.Context
+//   - multichannelRoomID string
+//   - to entity.RoomStatus
+//   - actor string
+//   - note string
+func (_e *Repository_Expecter) Transition(ctx interface{}, multichannelRoomID interface{}, to interface{}, actor interface{}, note interface{}) *Repository_Transition_Call {
+	return &Repository_Transition_Call{Call: _e.mock.On("Transition", ctx, multichannelRoomID, to, actor, note)}
+}
+
+func (_c *Repository_Transition_Call) Run(run func(ctx context.Context, multichannelRoomID string, to entity.RoomStatus, actor string, note string)) *Repository_Transition_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(entity.RoomStatus), args[3].(string), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *Repository_Transition_Call) Return(_a0 error) *Repository_Transition_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Repository_Transition_Call) RunAndReturn(run func(context.Context, string, entity.RoomStatus, string, string) error) *Repository_Transition_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewRepository creates a new instance of Repository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewRepository(t interface {
@@ -2,9 +2,13 @@ package room
 
 import (
 	"context"
+	"fmt"
 	"integration-go/internal/entity"
+	"integration-go/internal/pkg/postgres"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type repo struct {
@@ -17,14 +21,132 @@ func NewRepository(db *gorm.DB) *repo {
 	}
 }
 
+// Save upserts new rooms on multichannel_room_id, so a redelivered webhook never inserts a
+// second row. Qiscus reuses the room ID for the next session of the customer, so a room
+// resolved before the new room was created is reopened instead.
 func (r *repo) Save(ctx context.Context, room *entity.Room) error {
-	err := r.db.WithContext(ctx).Save(room).Error
-	return err
+	if room.ID != 0 {
+		err := r.db.WithContext(ctx).Save(room).Error
+		return err
+	}
+
+	if room.CreatedAt.IsZero() {
+		room.CreatedAt = time.Now()
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "multichannel_room_id"}},
+			DoNothing: true,
+		}).Create(room)
+		if res.Error != nil || res.RowsAffected > 0 {
+			return res.Error
+		}
+
+		var existing entity.Room
+		err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("multichannel_room_id = ?", room.MultichannelRoomID).First(&existing).Error
+		if err != nil {
+			return err
+		}
+
+		// A room resolved after the session started, e.g. by a mark_as_resolved webhook
+		// processed before the new session job, belongs to this session and stays resolved
+		if !existing.IsResolved() || (existing.ResolvedAt != nil && !existing.ResolvedAt.Before(room.CreatedAt)) {
+			*room = existing
+			return nil
+		}
+
+		from := existing.Status
+		if err := existing.Reopen(room.CreatedAt); err != nil {
+			return err
+		}
+
+		if err := tx.Unscoped().Save(&existing).Error; err != nil {
+			return err
+		}
+
+		*room = existing
+		return tx.Create(&entity.RoomEvent{
+			RoomID:             existing.ID,
+			MultichannelRoomID: existing.MultichannelRoomID,
+			FromStatus:         from,
+			ToStatus:           entity.RoomStatusNew,
+			Note:               "reopened by a new session",
+		}).Error
+	})
+}
+
+// Fetch returns a page of the rooms matching the filter, soft deleted rooms included, and
+// the total of matching rooms. The page starts after filter.Cursor when set, otherwise at
+// filter.Page. SortBy must be a column allowed by the caller.
+func (r *repo) Fetch(ctx context.Context, filter *entity.RoomFilter) ([]entity.Room, int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Unscoped().Model(&entity.Room{}).Scopes(filterScope(filter)).Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	dir, op := "ASC", ">"
+	if filter.SortDesc {
+		dir, op = "DESC", "<"
+	}
+
+	// The ID breaks ties, so rooms with the same sort value are neither skipped nor repeated
+	order := fmt.Sprintf("%s %s", filter.SortBy, dir)
+	if filter.SortBy != "id" {
+		order += fmt.Sprintf(", id %s", dir)
+	}
+
+	db := r.db.WithContext(ctx).Unscoped().Scopes(filterScope(filter))
+	switch {
+	case filter.Cursor != nil && filter.SortBy == "id":
+		db = db.Where(fmt.Sprintf("id %s ?", op), filter.Cursor.ID)
+	case filter.Cursor != nil:
+		db = db.Where(fmt.Sprintf("(%s, id) %s (?, ?)", filter.SortBy, op), filter.Cursor.Value, filter.Cursor.ID)
+	default:
+		db = db.Offset((filter.Page - 1) * filter.Limit)
+	}
+
+	var rooms []entity.Room
+	err = db.Order(order).Limit(filter.Limit).Find(&rooms).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return rooms, total, nil
+}
+
+func filterScope(f *entity.RoomFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if f.MultichannelRoomID != "" {
+			db = db.Where("multichannel_room_id = ?", f.MultichannelRoomID)
+		}
+		if f.Status != "" {
+			db = db.Where("status = ?", f.Status)
+		}
+		if f.From != nil {
+			db = db.Where("created_at >= ?", *f.From)
+		}
+		if f.To != nil {
+			db = db.Where("created_at < ?", *f.To)
+		}
+
+		return db
+	}
 }
 
-func (r *repo) Fetch(ctx context.Context) ([]entity.Room, error) {
+// FetchExpired returns up to limit rooms created before createdBefore with an ID greater
+// than afterID, ordered by ID, so callers can page through them with the last ID. Resolved
+// rooms and rooms whose resolution failed for good, left to a replay, are excluded.
+func (r *repo) FetchExpired(ctx context.Context, createdBefore time.Time, afterID int64, limit int) ([]entity.Room, error) {
 	var rooms []entity.Room
-	err := r.db.WithContext(ctx).Find(&rooms).Error
+	err := r.db.WithContext(ctx).
+		Where("created_at <= ? AND status NOT IN ? AND id > ?", createdBefore,
+			[]entity.RoomStatus{entity.RoomStatusResolved, entity.RoomStatusResolveFailed}, afterID).
+		Order("id").
+		Limit(limit).
+		Find(&rooms).Error
 	if err != nil {
 		return nil, err
 	}
@@ -32,9 +154,20 @@ func (r *repo) Fetch(ctx context.Context) ([]entity.Room, error) {
 	return rooms, nil
 }
 
+// FindByID includes soft deleted rooms, so the history of a resolved room can be looked up.
 func (r *repo) FindByID(ctx context.Context, id int64) (*entity.Room, error) {
 	var room entity.Room
-	err := r.db.WithContext(ctx).First(&room, id).Error
+	err := r.db.WithContext(ctx).Unscoped().First(&room, id).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &room, nil
+}
+
+func (r *repo) FindByMultichannelRoomID(ctx context.Context, multichannelRoomID string) (*entity.Room, error) {
+	var room entity.Room
+	err := r.db.WithContext(ctx).Where("multichannel_room_id = ?", multichannelRoomID).First(&room).Error
 	if err != nil {
 		return nil, err
 	}
@@ -42,7 +175,90 @@ func (r *repo) FindByID(ctx context.Context, id int64) (*entity.Room, error) {
 	return &room, nil
 }
 
+// Transition moves the room to the status and records the transition as a room event.
+// Moving a room to the status it already has is a no-op. A room that is not stored yet,
+// e.g. its new session job is still queued, is created as new first. A transition made
+// under a stale fencing token, e.g. by a cron replica that lost its lock, is rejected.
+func (r *repo) Transition(ctx context.Context, multichannelRoomID string, to entity.RoomStatus, actor, note string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := postgres.CheckFencingToken(ctx, tx); err != nil {
+			return err
+		}
+
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "multichannel_room_id"}},
+			DoNothing: true,
+		}).Create(&entity.Room{
+			MultichannelRoomID: multichannelRoomID,
+			Status:             entity.RoomStatusNew,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		var room entity.Room
+		err = tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("multichannel_room_id = ?", multichannelRoomID).First(&room).Error
+		if err != nil {
+			return err
+		}
+
+		if room.Status == to {
+			return nil
+		}
+
+		from := room.Status
+		if err := room.Transition(to, actor, time.Now()); err != nil {
+			return err
+		}
+
+		if err := tx.Unscoped().Save(&room).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&entity.RoomEvent{
+			RoomID:             room.ID,
+			MultichannelRoomID: room.MultichannelRoomID,
+			FromStatus:         from,
+			ToStatus:           to,
+			Actor:              actor,
+			Note:               note,
+		}).Error
+	})
+}
+
+// MarkClosingSent records that the closing message of the room was sent. Like Transition,
+// it is rejected under a stale fencing token.
+func (r *repo) MarkClosingSent(ctx context.Context, multichannelRoomID string, at time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := postgres.CheckFencingToken(ctx, tx); err != nil {
+			return err
+		}
+
+		return tx.Model(&entity.Room{}).
+			Where("multichannel_room_id = ?", multichannelRoomID).
+			Update("closing_sent_at", at).Error
+	})
+}
+
+func (r *repo) FetchEvents(ctx context.Context, roomID int64) ([]entity.RoomEvent, error) {
+	var events []entity.RoomEvent
+	err := r.db.WithContext(ctx).Where("room_id = ?", roomID).Order("id").Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// DeleteBy soft deletes the rooms, so they are still available through FindByID and
+// their events. Like Transition, it is rejected under a stale fencing token.
 func (r *repo) DeleteBy(ctx context.Context, query map[string]any) error {
-	err := r.db.WithContext(ctx).Delete(&entity.Room{}, query).Error
-	return err
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := postgres.CheckFencingToken(ctx, tx); err != nil {
+			return err
+		}
+
+		return tx.Delete(&entity.Room{}, query).Error
+	})
 }
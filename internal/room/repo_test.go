@@ -0,0 +1,120 @@
+package room
+
+import (
+	"context"
+	"integration-go/internal/entity"
+	"integration-go/internal/pkg/postgres"
+	"integration-go/internal/pkg/postgres/pgtest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepo_Save_ReopenResolvedRoom(t *testing.T) {
+	repo := NewRepository(pgtest.New(t))
+	ctx := context.Background()
+
+	resolve := func(t *testing.T) {
+		t.Helper()
+		require.NoError(t, repo.Transition(ctx, "room-123", entity.RoomStatusResolved, "resolver", ""))
+		require.NoError(t, repo.DeleteBy(ctx, map[string]any{"multichannel_room_id": "room-123"}))
+	}
+
+	first := time.Now().Add(-time.Hour)
+	require.NoError(t, repo.Save(ctx, &entity.Room{MultichannelRoomID: "room-123", Status: entity.RoomStatusNew, CreatedAt: first}))
+	require.NoError(t, repo.Transition(ctx, "room-123", entity.RoomStatusAssigned, "agent@mail.com", ""))
+	resolve(t)
+
+	// The next session of the customer reuses the room ID
+	second := time.Now()
+	room := &entity.Room{MultichannelRoomID: "room-123", Status: entity.RoomStatusNew, CreatedAt: second}
+	require.NoError(t, repo.Save(ctx, room))
+
+	reopened, err := repo.FindByMultichannelRoomID(ctx, "room-123")
+	require.NoError(t, err)
+	assert.Equal(t, room.ID, reopened.ID)
+	assert.Equal(t, entity.RoomStatusNew, reopened.Status)
+	assert.Nil(t, reopened.ResolvedAt)
+	assert.Nil(t, reopened.AssignedAt)
+	assert.Empty(t, reopened.ResolvedBy)
+	assert.WithinDuration(t, second, reopened.CreatedAt, time.Millisecond)
+
+	expired, err := repo.FetchExpired(ctx, time.Now().Add(time.Minute), 0, 10)
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	assert.Equal(t, room.ID, expired[0].ID)
+
+	// The reopened room is assigned and resolved again
+	require.NoError(t, repo.Transition(ctx, "room-123", entity.RoomStatusAssigned, "agent@mail.com", ""))
+	resolve(t)
+
+	resolved, err := repo.FindByID(ctx, room.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.RoomStatusResolved, resolved.Status)
+	assert.True(t, resolved.DeletedAt.Valid)
+
+	events, err := repo.FetchEvents(ctx, room.ID)
+	require.NoError(t, err)
+
+	var statuses []entity.RoomStatus
+	for _, event := range events {
+		statuses = append(statuses, event.ToStatus)
+	}
+
+	assert.Equal(t, []entity.RoomStatus{
+		entity.RoomStatusAssigned,
+		entity.RoomStatusResolved,
+		entity.RoomStatusNew,
+		entity.RoomStatusAssigned,
+		entity.RoomStatusResolved,
+	}, statuses)
+}
+
+func TestRepo_Save_KeepResolvedSession(t *testing.T) {
+	repo := NewRepository(pgtest.New(t))
+	ctx := context.Background()
+
+	// The mark_as_resolved webhook is processed before the new session job of the same session
+	enqueuedAt := time.Now().Add(-time.Minute)
+	require.NoError(t, repo.Transition(ctx, "room-123", entity.RoomStatusResolved, "agent@mail.com", ""))
+
+	require.NoError(t, repo.Save(ctx, &entity.Room{MultichannelRoomID: "room-123", Status: entity.RoomStatusNew, CreatedAt: enqueuedAt}))
+
+	room, err := repo.FindByMultichannelRoomID(ctx, "room-123")
+	require.NoError(t, err)
+	assert.Equal(t, entity.RoomStatusResolved, room.Status)
+
+	// A redelivered webhook of an open room changes nothing
+	require.NoError(t, repo.Save(ctx, &entity.Room{MultichannelRoomID: "room-456", Status: entity.RoomStatusNew}))
+	require.NoError(t, repo.Transition(ctx, "room-456", entity.RoomStatusAssigned, "agent@mail.com", ""))
+	require.NoError(t, repo.Save(ctx, &entity.Room{MultichannelRoomID: "room-456", Status: entity.RoomStatusNew}))
+
+	room, err = repo.FindByMultichannelRoomID(ctx, "room-456")
+	require.NoError(t, err)
+	assert.Equal(t, entity.RoomStatusAssigned, room.Status)
+}
+
+func TestRepo_Transition_StaleFencingToken(t *testing.T) {
+	repo := NewRepository(pgtest.New(t))
+
+	stale := postgres.WithFencingToken(context.Background(), "cron:lock:resolver", 1)
+	current := postgres.WithFencingToken(context.Background(), "cron:lock:resolver", 2)
+
+	require.NoError(t, repo.Transition(stale, "room-123", entity.RoomStatusAssigned, "resolver", ""))
+	require.NoError(t, repo.Transition(current, "room-123", entity.RoomStatusResolveFailed, "resolver", ""))
+
+	// The previous holder resumes after the lock was taken over
+	err := repo.Transition(stale, "room-123", entity.RoomStatusResolved, "resolver", "")
+	assert.ErrorIs(t, err, postgres.ErrStaleFencingToken)
+	err = repo.DeleteBy(stale, map[string]any{"multichannel_room_id": "room-123"})
+	assert.ErrorIs(t, err, postgres.ErrStaleFencingToken)
+
+	room, err := repo.FindByMultichannelRoomID(context.Background(), "room-123")
+	require.NoError(t, err)
+	assert.Equal(t, entity.RoomStatusResolveFailed, room.Status)
+
+	// Writes without a token, e.g. from the API, are not fenced
+	require.NoError(t, repo.Transition(context.Background(), "room-123", entity.RoomStatusResolved, "agent@mail.com", ""))
+}
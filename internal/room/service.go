@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"integration-go/internal/entity"
 	"integration-go/internal/pkg/qismo"
+	"integration-go/internal/pkg/queue"
 
 	"gorm.io/gorm"
 )
@@ -17,22 +18,48 @@ type Omnichannel interface {
 
 //go:generate mockery --with-expecter --case snake --name Repository
 type Repository interface {
+	Fetch(ctx context.Context, filter *entity.RoomFilter) ([]entity.Room, int64, error)
 	FindByID(ctx context.Context, id int64) (*entity.Room, error)
 	Save(ctx context.Context, room *entity.Room) error
+	Transition(ctx context.Context, multichannelRoomID string, to entity.RoomStatus, actor, note string) error
+	FetchEvents(ctx context.Context, roomID int64) ([]entity.RoomEvent, error)
+}
+
+//go:generate mockery --with-expecter --case snake --name Queue
+type Queue interface {
+	Enqueue(ctx context.Context, jobType string, key string, payload any) error
 }
 
 type Service struct {
-	repo Repository
-	omni Omnichannel
+	repo  Repository
+	omni  Omnichannel
+	queue Queue
 }
 
-func NewService(repo Repository, omni Omnichannel) *Service {
+func NewService(repo Repository, omni Omnichannel, queue Queue) *Service {
 	return &Service{
-		repo: repo,
-		omni: omni,
+		repo:  repo,
+		omni:  omni,
+		queue: queue,
 	}
 }
 
+// GetRooms returns a page of the rooms matching the filter, the total of matching rooms and
+// the cursor of the next page, empty when the page is the last one.
+func (s *Service) GetRooms(ctx context.Context, filter *entity.RoomFilter) ([]entity.Room, int64, string, error) {
+	rooms, total, err := s.repo.Fetch(ctx, filter)
+	if err != nil {
+		return nil, 0, "", fmt.Errorf("failed to fetch rooms: %w", err)
+	}
+
+	var nextCursor string
+	if len(rooms) == filter.Limit {
+		nextCursor = encodeCursor(filter.SortBy, &rooms[len(rooms)-1])
+	}
+
+	return rooms, total, nextCursor, nil
+}
+
 func (s *Service) GetRoomByID(ctx context.Context, id int64) (*entity.Room, error) {
 	room, err := s.repo.FindByID(ctx, id)
 	if err != nil {
@@ -46,14 +73,41 @@ func (s *Service) GetRoomByID(ctx context.Context, id int64) (*entity.Room, erro
 	return room, nil
 }
 
+// GetRoomEvents returns the status transitions of the room, oldest first.
+func (s *Service) GetRoomEvents(ctx context.Context, id int64) ([]entity.RoomEvent, error) {
+	if _, err := s.GetRoomByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	events, err := s.repo.FetchEvents(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch room events: %w", err)
+	}
+
+	return events, nil
+}
+
+// EnqueueCreateRoom persists the new session event, so the room is created by the worker
+// and the webhook can be acknowledged without waiting for the Omnichannel API.
+func (s *Service) EnqueueCreateRoom(ctx context.Context, req *qismo.WebhookNewSessionRequest) error {
+	if err := s.queue.Enqueue(ctx, JobCreateRoom, req.Payload.Room.IDStr, req); err != nil {
+		return fmt.Errorf("failed to enqueue create room: %w", err)
+	}
+
+	return nil
+}
+
 func (s *Service) CreateRoom(ctx context.Context, req *qismo.WebhookNewSessionRequest) error {
 	err := s.omni.CreateRoomTag(ctx, req.Payload.Room.IDStr, req.Payload.Room.IDStr)
 	if err != nil {
 		return fmt.Errorf("failed to create omnichannel tag: %w", err)
 	}
 
+	// The session started when its webhook was enqueued, not when the job runs
 	err = s.repo.Save(ctx, &entity.Room{
 		MultichannelRoomID: req.Payload.Room.IDStr,
+		Status:             entity.RoomStatusNew,
+		CreatedAt:          queue.EnqueuedAt(ctx),
 	})
 
 	if err != nil {
@@ -62,3 +116,18 @@ func (s *Service) CreateRoom(ctx context.Context, req *qismo.WebhookNewSessionRe
 
 	return nil
 }
+
+// MarkAsResolved keeps the local room in sync when it is resolved outside of this service,
+// e.g. by an agent in the Omnichannel dashboard, so the resolver does not resolve it again.
+func (s *Service) MarkAsResolved(ctx context.Context, req *qismo.WebhookMarkAsResolvedRequest) error {
+	err := s.repo.Transition(ctx, req.Service.RoomID, entity.RoomStatusResolved, req.ResolvedBy.Email, "resolved in omnichannel")
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidRoomTransition) {
+			return &roomError{roomErrorInvalidTransition}
+		}
+
+		return fmt.Errorf("failed to mark room as resolved: %w", err)
+	}
+
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"integration-go/internal/pkg/qismo"
 	"integration-go/internal/room/mocks"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -15,6 +16,50 @@ import (
 
 var errUnexpected = fmt.Errorf("unexpected")
 
+func TestGetRooms(t *testing.T) {
+	mockRepo := mocks.NewRepository(t)
+	createdAt := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("error fetch rooms", func(t *testing.T) {
+		filter := &entity.RoomFilter{SortBy: "created_at", SortDesc: true, Page: 1, Limit: 2}
+		mockRepo.EXPECT().Fetch(mock.Anything, filter).Return(nil, 0, errUnexpected).Once()
+
+		svc := Service{repo: mockRepo}
+		rooms, total, nextCursor, err := svc.GetRooms(context.Background(), filter)
+		assert.Equal(t, fmt.Errorf("failed to fetch rooms: %w", errUnexpected), err)
+		assert.Nil(t, rooms)
+		assert.Equal(t, int64(0), total)
+		assert.Empty(t, nextCursor)
+	})
+
+	t.Run("success get full page with next cursor", func(t *testing.T) {
+		filter := &entity.RoomFilter{SortBy: "created_at", SortDesc: true, Page: 1, Limit: 2}
+		mockRepo.EXPECT().Fetch(mock.Anything, filter).
+			Return([]entity.Room{{ID: 9, CreatedAt: createdAt.Add(time.Minute)}, {ID: 7, CreatedAt: createdAt}}, 5, nil).Once()
+
+		svc := Service{repo: mockRepo}
+		rooms, total, nextCursor, err := svc.GetRooms(context.Background(), filter)
+		assert.Nil(t, err)
+		assert.Len(t, rooms, 2)
+		assert.Equal(t, int64(5), total)
+
+		cursor, err := decodeCursor(nextCursor, "created_at")
+		assert.Nil(t, err)
+		assert.Equal(t, &entity.RoomCursor{ID: 7, Value: createdAt}, cursor)
+	})
+
+	t.Run("success get last page", func(t *testing.T) {
+		filter := &entity.RoomFilter{SortBy: "id", Cursor: &entity.RoomCursor{ID: 7}, Limit: 2}
+		mockRepo.EXPECT().Fetch(mock.Anything, filter).Return([]entity.Room{{ID: 8}}, 5, nil).Once()
+
+		svc := Service{repo: mockRepo}
+		rooms, _, nextCursor, err := svc.GetRooms(context.Background(), filter)
+		assert.Nil(t, err)
+		assert.Len(t, rooms, 1)
+		assert.Empty(t, nextCursor)
+	})
+}
+
 func TestGetRoomByID(t *testing.T) {
 	mockRepo := mocks.NewRepository(t)
 
@@ -49,6 +94,47 @@ func TestGetRoomByID(t *testing.T) {
 	})
 
 }
+
+func TestGetRoomEvents(t *testing.T) {
+	mockRepo := mocks.NewRepository(t)
+
+	t.Run("error room not found", func(t *testing.T) {
+		mockRepo.EXPECT().FindByID(mock.Anything, int64(1)).Return(nil, gorm.ErrRecordNotFound).Once()
+
+		svc := Service{repo: mockRepo}
+		events, err := svc.GetRoomEvents(context.Background(), 1)
+		assert.Equal(t, &roomError{roomErrorNotFound}, err)
+		assert.Nil(t, events)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("error fetch events", func(t *testing.T) {
+		mockRepo.EXPECT().FindByID(mock.Anything, int64(1)).Return(&entity.Room{ID: 1}, nil).Once()
+		mockRepo.EXPECT().FetchEvents(mock.Anything, int64(1)).Return(nil, errUnexpected).Once()
+
+		svc := Service{repo: mockRepo}
+		events, err := svc.GetRoomEvents(context.Background(), 1)
+		assert.Equal(t, fmt.Errorf("failed to fetch room events: %w", errUnexpected), err)
+		assert.Nil(t, events)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("success get room events", func(t *testing.T) {
+		expected := []entity.RoomEvent{
+			{RoomID: 1, FromStatus: entity.RoomStatusNew, ToStatus: entity.RoomStatusResolved},
+		}
+
+		mockRepo.EXPECT().FindByID(mock.Anything, int64(1)).Return(&entity.Room{ID: 1}, nil).Once()
+		mockRepo.EXPECT().FetchEvents(mock.Anything, int64(1)).Return(expected, nil).Once()
+
+		svc := Service{repo: mockRepo}
+		events, err := svc.GetRoomEvents(context.Background(), 1)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, events)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
 func TestCreateRoom(t *testing.T) {
 	mockRepo := mocks.NewRepository(t)
 	mockOmni := mocks.NewOmnichannel(t)
@@ -58,7 +144,7 @@ func TestCreateRoom(t *testing.T) {
 		Payload: struct {
 			Room struct {
 				ID              string `json:"id"`
-				IDStr           string `json:"id_str"`
+				IDStr           string `json:"id_str" validate:"required"`
 				IsPublicChannel bool   `json:"is_public_channel"`
 				Name            string `json:"name"`
 				Options         string `json:"options"`
@@ -73,7 +159,7 @@ func TestCreateRoom(t *testing.T) {
 		}{
 			Room: struct {
 				ID              string `json:"id"`
-				IDStr           string `json:"id_str"`
+				IDStr           string `json:"id_str" validate:"required"`
 				IsPublicChannel bool   `json:"is_public_channel"`
 				Name            string `json:"name"`
 				Options         string `json:"options"`
@@ -113,6 +199,7 @@ func TestCreateRoom(t *testing.T) {
 
 		mockRepo.EXPECT().Save(mock.Anything, &entity.Room{
 			MultichannelRoomID: req.Payload.Room.IDStr,
+			Status:             entity.RoomStatusNew,
 		}).Return(errUnexpected).Once()
 
 		svc := Service{
@@ -133,6 +220,7 @@ func TestCreateRoom(t *testing.T) {
 
 		mockRepo.EXPECT().Save(mock.Anything, &entity.Room{
 			MultichannelRoomID: req.Payload.Room.IDStr,
+			Status:             entity.RoomStatusNew,
 		}).Return(nil).Once()
 
 		svc := Service{
@@ -147,3 +235,70 @@ func TestCreateRoom(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestEnqueueCreateRoom(t *testing.T) {
+	mockQueue := mocks.NewQueue(t)
+
+	req := &qismo.WebhookNewSessionRequest{
+		IsNewSession: true,
+		WebhookType:  "new_session",
+	}
+
+	t.Run("error enqueue", func(t *testing.T) {
+		mockQueue.EXPECT().Enqueue(mock.Anything, JobCreateRoom, req.Payload.Room.IDStr, req).Return(errUnexpected).Once()
+
+		svc := Service{queue: mockQueue}
+		err := svc.EnqueueCreateRoom(context.Background(), req)
+		assert.Equal(t, fmt.Errorf("failed to enqueue create room: %w", errUnexpected), err)
+		mockQueue.AssertExpectations(t)
+	})
+
+	t.Run("success enqueue", func(t *testing.T) {
+		mockQueue.EXPECT().Enqueue(mock.Anything, JobCreateRoom, req.Payload.Room.IDStr, req).Return(nil).Once()
+
+		svc := Service{queue: mockQueue}
+		err := svc.EnqueueCreateRoom(context.Background(), req)
+		assert.Nil(t, err)
+		mockQueue.AssertExpectations(t)
+	})
+}
+
+func TestMarkAsResolved(t *testing.T) {
+	mockRepo := mocks.NewRepository(t)
+
+	req := &qismo.WebhookMarkAsResolvedRequest{
+		ResolvedBy:  qismo.WebhookAgent{Email: "agent@mail.com"},
+		Service:     qismo.WebhookService{RoomID: "room-123", IsResolved: true},
+		WebhookType: qismo.WebhookTypeMarkAsResolved,
+	}
+
+	t.Run("error mark room as resolved", func(t *testing.T) {
+		mockRepo.EXPECT().Transition(mock.Anything, "room-123", entity.RoomStatusResolved, "agent@mail.com", mock.Anything).
+			Return(errUnexpected).Once()
+
+		svc := Service{repo: mockRepo}
+		err := svc.MarkAsResolved(context.Background(), req)
+		assert.Equal(t, fmt.Errorf("failed to mark room as resolved: %w", errUnexpected), err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("error invalid transition", func(t *testing.T) {
+		mockRepo.EXPECT().Transition(mock.Anything, "room-123", entity.RoomStatusResolved, "agent@mail.com", mock.Anything).
+			Return(fmt.Errorf("%w: resolved to resolved", entity.ErrInvalidRoomTransition)).Once()
+
+		svc := Service{repo: mockRepo}
+		err := svc.MarkAsResolved(context.Background(), req)
+		assert.Equal(t, &roomError{roomErrorInvalidTransition}, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("success mark room as resolved", func(t *testing.T) {
+		mockRepo.EXPECT().Transition(mock.Anything, "room-123", entity.RoomStatusResolved, "agent@mail.com", mock.Anything).
+			Return(nil).Once()
+
+		svc := Service{repo: mockRepo}
+		err := svc.MarkAsResolved(context.Background(), req)
+		assert.Nil(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+}